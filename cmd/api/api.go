@@ -12,6 +12,7 @@ import (
 	"multi-tenant-service/package/logger"
 	"net/http"
 	"os"
+	"syscall"
 	"time"
 
 	"os/signal"
@@ -29,7 +30,11 @@ import (
 	deliMessage "multi-tenant-service/internal/message/delivery"
 )
 
-const CmdServeHTTP = "serve-http"
+const (
+	CmdServeHTTP = "serve-http"
+	FlagAPIOnly  = "api-only"
+	FlagGRPCPort = "grpc-port"
+)
 
 type HTTP struct {
 	usecase usecase.ITenantUsecase
@@ -44,6 +49,15 @@ func (h HTTP) ServeAPI(c *cli.Context) error {
 	// Register metrics
 	metrics.Register()
 
+	apiOnly := c.Bool(FlagAPIOnly)
+	if apiOnly {
+		// Consumers and background jobs run in serve-worker processes
+		h.usecase.SetOwnership(usecase.ConsumeNone{})
+	} else if err := h.usecase.ResumeDeletionJobs(c.Context); err != nil {
+		// Pick up tenant deletions interrupted by a previous shutdown
+		log.Printf("error resuming deletion jobs %v", err)
+	}
+
 	e := echo.New()
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 	
@@ -63,21 +77,39 @@ func (h HTTP) ServeAPI(c *cli.Context) error {
 
 	e.Use(echoMiddleware.CORSWithConfig(echoMiddleware.CORSConfig{
 		// AllowOrigins:     []string{"http://localhost:3001"},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "If-Match"},
+		ExposeHeaders:    []string{"ETag"},
 		AllowCredentials: true,
 	}))
 	
 
 	tenantAPI.Use(middleware.LoggerMiddleware)
 	tenantAPI.Use(middleware.MonitoringMiddleware)
+	if h.cfg.JWT.RESTAuth {
+		// The same bearer tokens as gRPC, so neither API is a way around the
+		// other. The gateway and streams may pass theirs in ?token=
+		tenantAPI.Use(middleware.RESTAuth(h.cfg.JWT.Secret, "/gateway", "/messages/stream"))
+	}
 
 	delivery.NewTenantHTTPHandler(tenantAPI, h.usecase)
-	deliMessage.NewMessageHTTPHandler(tenantAPI, h.um)
+	deliMessage.NewMessageHTTPHandler(tenantAPI, h.um, h.cfg)
 
-	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	if !apiOnly {
+		h.usecase.Start(ctx)
+	}
+
+	var grpcStopped <-chan struct{}
+	if port := c.String(FlagGRPCPort); port != "" {
+		var err error
+		if grpcStopped, err = h.serveGRPC(ctx, port); err != nil {
+			return err
+		}
+	}
+
 	go func() {
 		if err := e.Start(fmt.Sprintf(":%v", h.cfg.Server.Port)); err != nil {
 			e.Logger.Fatal("shutting down the server")
@@ -90,7 +122,14 @@ func (h HTTP) ServeAPI(c *cli.Context) error {
 	if err := e.Shutdown(ctx); err != nil {
 		e.Logger.Fatal(err)
 	}
-	return nil
+	if grpcStopped != nil {
+		<-grpcStopped
+	}
+
+	// Stop publishing first, then drain the consumers
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), h.cfg.Server.ShutdownTimeout)
+	defer drainCancel()
+	return h.usecase.Shutdown(drainCtx)
 }
 
 func ServeAPI(usecase usecase.ITenantUsecase, um um.IMessageUsecase, cfg *config.Config) []*cli.Command {
@@ -100,6 +139,17 @@ func ServeAPI(usecase usecase.ITenantUsecase, um um.IMessageUsecase, cfg *config
 			Name:   CmdServeHTTP,
 			Usage:  "Serve multi-tenant service",
 			Action: h.ServeAPI,
+			Flags: []cli.Flag{
+				&cli.BoolFlag{
+					Name:  FlagAPIOnly,
+					Usage: "serve the API only and leave tenant consumers to serve-worker",
+				},
+				&cli.StringFlag{
+					Name:  FlagGRPCPort,
+					Usage: "also serve the gRPC API on this port",
+					Value: cfg.Server.GRPCPort,
+				},
+			},
 		},
 	}
 }
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"time"
+
+	"multi-tenant-service/cmd/middleware"
+	deliMessage "multi-tenant-service/internal/message/delivery"
+	"multi-tenant-service/internal/tenant/delivery"
+
+	"google.golang.org/grpc"
+)
+
+// grpcStopTimeout is how long open calls get to finish on shutdown.
+const grpcStopTimeout = 10 * time.Second
+
+// serveGRPC serves TenantService and MessageService on port until ctx is
+// done. The returned channel is closed once the server has stopped.
+func (h HTTP) serveGRPC(ctx context.Context, port string) (<-chan struct{}, error) {
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%v", port))
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for gRPC: %w", err)
+	}
+
+	authUnary, authStream := middleware.GRPCAuth(h.cfg.JWT.Secret)
+	monitorUnary, monitorStream := middleware.GRPCMonitoring()
+	s := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(monitorUnary, authUnary),
+		grpc.ChainStreamInterceptor(monitorStream, authStream),
+	)
+	delivery.NewTenantGRPCServer(s, h.usecase)
+	deliMessage.NewMessageGRPCServer(s, h.um)
+
+	go func() {
+		if err := s.Serve(lis); err != nil {
+			log.Printf("gRPC server stopped: %v", err)
+		}
+	}()
+
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		<-ctx.Done()
+
+		// Subscribe streams only end when their clients go away, so they are
+		// cut off if a graceful stop takes too long
+		graceful := make(chan struct{})
+		go func() {
+			s.GracefulStop()
+			close(graceful)
+		}()
+		select {
+		case <-graceful:
+		case <-time.After(grpcStopTimeout):
+			s.Stop()
+		}
+	}()
+	return stopped, nil
+}
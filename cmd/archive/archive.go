@@ -0,0 +1,111 @@
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+	"multi-tenant-service/internal/tenant/usecase"
+	"os"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	CmdArchive = "archive"
+	FlagTenant = "tenant"
+	FlagTable  = "table"
+	FlagAs     = "as"
+	FlagAttach = "attach"
+)
+
+type Archive struct {
+	usecase usecase.ITenantUsecase
+}
+
+// List prints the detached partitions of a tenant and whether each one is
+// archived.
+func (a Archive) List(c *cli.Context) error {
+	partitions, err := a.usecase.ListDetachedMessagePartitions(c.Context, c.String(FlagTenant))
+	if err != nil {
+		return err
+	}
+	return printJSON(partitions)
+}
+
+// Export archives a detached partition and prints its manifest.
+func (a Archive) Export(c *cli.Context) error {
+	manifest, err := a.usecase.ArchiveMessagePartition(c.Context, c.String(FlagTenant), c.String(FlagTable))
+	if err != nil {
+		return err
+	}
+	return printJSON(manifest)
+}
+
+// Restore loads an archived partition back into a table.
+func (a Archive) Restore(c *cli.Context) error {
+	manifest, err := a.usecase.RestoreMessagePartition(c.Context,
+		c.String(FlagTenant), c.String(FlagTable), c.String(FlagAs), c.Bool(FlagAttach))
+	if err != nil {
+		return err
+	}
+	return printJSON(manifest)
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("failed to print result: %w", err)
+	}
+	return nil
+}
+
+func NewArchive(usecase usecase.ITenantUsecase) []*cli.Command {
+	a := Archive{usecase: usecase}
+	tenantFlag := &cli.StringFlag{
+		Name:     FlagTenant,
+		Usage:    "tenant ID",
+		Required: true,
+	}
+	tableFlag := &cli.StringFlag{
+		Name:     FlagTable,
+		Usage:    "name of the partition, e.g. messages_tenant_<id>_p20250101",
+		Required: true,
+	}
+	return []*cli.Command{
+		{
+			Name:  CmdArchive,
+			Usage: "Archive detached message partitions and restore them",
+			Subcommands: []*cli.Command{
+				{
+					Name:   "list",
+					Usage:  "List the detached partitions of a tenant",
+					Action: a.List,
+					Flags:  []cli.Flag{tenantFlag},
+				},
+				{
+					Name:   "export",
+					Usage:  "Archive a detached partition",
+					Action: a.Export,
+					Flags:  []cli.Flag{tenantFlag, tableFlag},
+				},
+				{
+					Name:   "restore",
+					Usage:  "Restore an archived partition into a new table",
+					Action: a.Restore,
+					Flags: []cli.Flag{
+						tenantFlag,
+						tableFlag,
+						&cli.StringFlag{
+							Name:  FlagAs,
+							Usage: "name of the restored table (default the partition name)",
+						},
+						&cli.BoolFlag{
+							Name:  FlagAttach,
+							Usage: "attach the restored table to the tenant's messages again",
+						},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"multi-tenant-service/package/auth"
+	"multi-tenant-service/package/response"
+)
+
+// RESTAuth authenticates every request with the bearer token in the
+// Authorization header, the same token GRPCAuth takes. Only the routes whose
+// path ends in one of queryTokenRoutes, which browsers open without custom
+// headers, may pass it in the token query parameter instead. Routes with an
+// :id parameter are checked against that tenant here; the other handlers
+// check tenant access with auth.AuthorizeOptional.
+func RESTAuth(secret string, queryTokenRoutes ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			token, err := auth.BearerToken(c.Request().Header.Get(echo.HeaderAuthorization))
+			if err == auth.ErrMissingToken && allowsQueryToken(c.Path(), queryTokenRoutes) {
+				if token = c.QueryParam("token"); token != "" {
+					err = nil
+				}
+			}
+			if err != nil {
+				return response.JSONResponse(c, http.StatusUnauthorized, false, err.Error(), nil)
+			}
+			claims, err := auth.ParseToken(secret, token)
+			if err != nil {
+				return response.JSONResponse(c, http.StatusUnauthorized, false, err.Error(), nil)
+			}
+
+			ctx := auth.NewContext(c.Request().Context(), claims)
+			c.SetRequest(c.Request().WithContext(ctx))
+			if tenantID := c.Param("id"); tenantID != "" {
+				if err := auth.Authorize(ctx, tenantID); err != nil {
+					return response.JSONResponse(c, http.StatusForbidden, false, err.Error(), nil)
+				}
+			}
+			return next(c)
+		}
+	}
+}
+
+func allowsQueryToken(path string, routes []string) bool {
+	for _, route := range routes {
+		if strings.HasSuffix(path, route) {
+			return true
+		}
+	}
+	return false
+}
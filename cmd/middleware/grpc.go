@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"multi-tenant-service/metrics"
+	"multi-tenant-service/package/auth"
+)
+
+// GRPCAuth authenticates every call with the bearer token in the
+// authorization metadata; handlers check tenant access with auth.Authorize.
+func GRPCAuth(secret string) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	authenticate := func(ctx context.Context) (context.Context, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			return nil, status.Error(codes.Unauthenticated, auth.ErrMissingToken.Error())
+		}
+		token, err := auth.BearerToken(values[0])
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		claims, err := auth.ParseToken(secret, token)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return auth.NewContext(ctx, claims), nil
+	}
+
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextStream{ServerStream: ss, ctx: ctx})
+	}
+	return unary, stream
+}
+
+// GRPCMonitoring logs every call and records it in the HTTP request metrics,
+// with GRPC as the method and the full gRPC method name as the path.
+func GRPCMonitoring() (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	unary := func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		observeGRPC(info.FullMethod, start, err)
+		return resp, err
+	}
+	stream := func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		observeGRPC(info.FullMethod, start, err)
+		return err
+	}
+	return unary, stream
+}
+
+func observeGRPC(method string, start time.Time, err error) {
+	duration := time.Since(start)
+	code := status.Code(err).String()
+
+	log.Info().
+		Str("method", "GRPC").
+		Str("uri", method).
+		Str("status", code).
+		Dur("latency", duration).
+		Msg("Handled request")
+
+	metrics.HttpRequestsTotal.WithLabelValues("GRPC", method, code).Inc()
+	metrics.HttpRequestDuration.WithLabelValues("GRPC", method).Observe(duration.Seconds())
+}
+
+// contextStream replaces the context of a server stream.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextStream) Context() context.Context { return s.ctx }
@@ -2,6 +2,8 @@ package middleware
 
 import (
 	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/labstack/echo/v4"
@@ -10,6 +12,24 @@ import (
 	"multi-tenant-service/metrics"
 )
 
+// redactURI hides the token query parameter the gateway and streams accept,
+// so bearer tokens do not end up in the request log.
+func redactURI(uri string) string {
+	path, rawQuery, ok := strings.Cut(uri, "?")
+	if !ok {
+		return uri
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return path + "?REDACTED"
+	}
+	if !query.Has("token") {
+		return uri
+	}
+	query.Set("token", "REDACTED")
+	return path + "?" + query.Encode()
+}
+
 func LoggerMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		start := time.Now()
@@ -18,7 +38,7 @@ func LoggerMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 		// Logging info request
 		log.Info().
 			Str("method", c.Request().Method).
-			Str("uri", c.Request().RequestURI).
+			Str("uri", redactURI(c.Request().RequestURI)).
 			Str("remote_ip", c.RealIP()).
 			Int("status", c.Response().Status).
 			Dur("latency", stop.Sub(start)).
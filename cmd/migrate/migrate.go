@@ -1,52 +1,330 @@
 package migrate
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"multi-tenant-service/migrations"
 	"multi-tenant-service/package/config"
+	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/urfave/cli/v2"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 )
 
+const (
+	CmdMigrate = "migrate"
+	FlagDir    = "dir"
+	FlagAll    = "all"
+)
+
+// defaultDirectory is where create writes when no directory is configured.
+const defaultDirectory = "migrations"
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
 type Migrate struct {
 	conf *config.Config
 }
 
-func (h *Migrate) Migrate(c *cli.Context) error {
-	migrationsPath, err := filepath.Abs("migrations")
+// Up applies all pending migrations, or the next N when given.
+func (h *Migrate) Up(c *cli.Context) error {
+	steps, err := optionalCount(c)
+	if err != nil {
+		return err
+	}
+	return h.run(c, func(m *migrate.Migrate) error {
+		if steps > 0 {
+			return m.Steps(steps)
+		}
+		return m.Up()
+	})
+}
+
+// Down reverts the last migration, the last N when given, or all of them
+// with --all.
+func (h *Migrate) Down(c *cli.Context) error {
+	steps, err := optionalCount(c)
 	if err != nil {
-		fmt.Println("error", err)
+		return err
+	}
+	if c.Bool(FlagAll) {
+		if steps > 0 {
+			return fmt.Errorf("--all cannot be combined with a count")
+		}
+		return h.run(c, func(m *migrate.Migrate) error { return m.Down() })
+	}
+	return h.run(c, func(m *migrate.Migrate) error { return m.Steps(-max(steps, 1)) })
+}
+
+// Goto migrates up or down to the given version.
+func (h *Migrate) Goto(c *cli.Context) error {
+	version, err := strconv.ParseUint(c.Args().First(), 10, 64)
+	if err != nil || c.NArg() != 1 {
+		return fmt.Errorf("usage: migrate goto VERSION")
+	}
+	return h.run(c, func(m *migrate.Migrate) error { return m.Migrate(uint(version)) })
+}
+
+// Force records version as applied and clears the dirty flag without
+// running anything, to recover from a failed migration. -1 means none.
+func (h *Migrate) Force(c *cli.Context) error {
+	version, err := strconv.Atoi(c.Args().First())
+	if err != nil || c.NArg() != 1 || version < -1 {
+		return fmt.Errorf("usage: migrate force VERSION")
 	}
-	m, err := migrate.New(
-		fmt.Sprintf("file://%s", migrationsPath),
-		h.conf.Database.URL,
-	)
+	return h.run(c, func(m *migrate.Migrate) error { return m.Force(version) })
+}
+
+// Status prints the database version and every migration with whether it
+// is applied.
+func (h *Migrate) Status(c *cli.Context) error {
+	m, err := h.open(c)
 	if err != nil {
-		return fmt.Errorf("failed to create migrate instance: %w", err)
+		return err
 	}
 	defer m.Close()
 
-	err = m.Up()
-	if err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("failed to run migrations: %w", err)
+	current, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		fmt.Println("Database version: none")
+	} else if err != nil {
+		return fmt.Errorf("failed to read database version: %w", err)
+	} else if dirty {
+		fmt.Printf("Database version: %d (dirty, fix it and run migrate force)\n", current)
+	} else {
+		fmt.Printf("Database version: %d\n", current)
 	}
 
+	src, err := h.source(c)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	version, err := src.First()
+	for err == nil {
+		name, _ := migrationName(src, version)
+		state := "pending"
+		if current > 0 && version <= current {
+			state = "applied"
+		}
+		fmt.Printf("%d  %-8s %s\n", version, state, name)
+		version, err = src.Next(version)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to list migrations: %w", err)
+	}
 	return nil
 }
 
+// Create writes empty up and down files for a new migration, numbered after
+// the newest one.
+func (h *Migrate) Create(c *cli.Context) error {
+	name := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(c.Args().First()), "_"), "_")
+	if name == "" || c.NArg() != 1 {
+		return fmt.Errorf("usage: migrate create NAME")
+	}
+
+	dir := h.directory(c)
+	if dir == "" {
+		dir = defaultDirectory
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	var version uint
+	for _, entry := range entries {
+		if m, err := source.Parse(entry.Name()); err == nil && m.Version > version {
+			version = m.Version
+		}
+	}
+	version++
+
+	for _, direction := range []source.Direction{source.Up, source.Down} {
+		path := filepath.Join(dir, fmt.Sprintf("%d_%s.%s.sql", version, name, direction))
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to create migration: %w", err)
+		}
+		f.Close()
+		fmt.Println(path)
+	}
+	return nil
+}
+
+// run opens the migrations and the database, runs fn and reports an
+// up-to-date database as success.
+func (h *Migrate) run(c *cli.Context, fn func(m *migrate.Migrate) error) error {
+	m, err := h.open(c)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	err = fn(m)
+	if errors.Is(err, migrate.ErrNoChange) {
+		log.Printf("No migrations to run")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+	return nil
+}
+
+func (h *Migrate) open(c *cli.Context) (*migrate.Migrate, error) {
+	src, err := h.source(c)
+	if err != nil {
+		return nil, err
+	}
+	m, err := migrate.NewWithSourceInstance("migrations", src, h.conf.Database.URL)
+	if err != nil {
+		src.Close()
+		return nil, fmt.Errorf("failed to create migrate instance: %w", err)
+	}
+	m.Log = logger{}
+	return m, nil
+}
+
+// source reads the migrations from the configured directory, or from the
+// ones embedded in the binary when there is none.
+func (h *Migrate) source(c *cli.Context) (source.Driver, error) {
+	dir := h.directory(c)
+	if dir == "" {
+		src, err := iofs.New(migrations.FS, ".")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read embedded migrations: %w", err)
+		}
+		return src, nil
+	}
+
+	path, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	src, err := (&file.File{}).Open(fmt.Sprintf("file://%s", path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations in %s: %w", dir, err)
+	}
+	return src, nil
+}
+
+func (h *Migrate) directory(c *cli.Context) string {
+	if c.IsSet(FlagDir) {
+		return c.String(FlagDir)
+	}
+	return h.conf.Migrations.Directory
+}
+
+// migrationName returns the name part of a migration's file names.
+func migrationName(src source.Driver, version uint) (string, error) {
+	r, name, err := src.ReadUp(version)
+	if errors.Is(err, os.ErrNotExist) {
+		r, name, err = src.ReadDown(version)
+	}
+	if err != nil {
+		return "", err
+	}
+	r.Close()
+	return name, nil
+}
+
+// optionalCount parses the optional step count of up and down.
+func optionalCount(c *cli.Context) (int, error) {
+	if c.NArg() == 0 {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(c.Args().First())
+	if err != nil || n < 1 || c.NArg() > 1 {
+		return 0, fmt.Errorf("the step count must be a positive number")
+	}
+	return n, nil
+}
+
+// logger prints the migrations as they are applied.
+type logger struct{}
+
+func (logger) Printf(format string, v ...interface{}) {
+	log.Printf(strings.TrimSuffix(format, "\n"), v...)
+}
+
+func (logger) Verbose() bool { return false }
+
 func NewMigrate(conf *config.Config) []*cli.Command {
 	h := Migrate{
 		conf: conf,
 	}
+	dirFlag := &cli.StringFlag{
+		Name:  FlagDir,
+		Usage: "directory of migration files (default the migrations embedded in the binary)",
+	}
 	return []*cli.Command{
 		{
-			Name:   "migrate",
-			Usage:  "Migrate database",
-			Action: h.Migrate,
+			Name:  CmdMigrate,
+			Usage: "Migrate database",
+			// Without a subcommand everything pending is applied, as before
+			Action: h.Up,
+			Flags:  []cli.Flag{dirFlag},
+			Subcommands: []*cli.Command{
+				{
+					Name:      "up",
+					Usage:     "Apply all pending migrations, or the next N",
+					ArgsUsage: "[N]",
+					Action:    h.Up,
+					Flags:     []cli.Flag{dirFlag},
+				},
+				{
+					Name:      "down",
+					Usage:     "Revert the last migration, the last N, or all with --all",
+					ArgsUsage: "[N]",
+					Action:    h.Down,
+					Flags: []cli.Flag{
+						dirFlag,
+						&cli.BoolFlag{
+							Name:  FlagAll,
+							Usage: "revert every migration",
+						},
+					},
+				},
+				{
+					Name:   "status",
+					Usage:  "Show the database version and which migrations are applied",
+					Action: h.Status,
+					Flags:  []cli.Flag{dirFlag},
+				},
+				{
+					Name:      "goto",
+					Usage:     "Migrate up or down to a version",
+					ArgsUsage: "VERSION",
+					Action:    h.Goto,
+					Flags:     []cli.Flag{dirFlag},
+				},
+				{
+					Name:      "force",
+					Usage:     "Set the version without running migrations, clearing the dirty flag",
+					ArgsUsage: "VERSION",
+					Action:    h.Force,
+					Flags:     []cli.Flag{dirFlag},
+				},
+				{
+					Name:      "create",
+					Usage:     "Create empty up and down files for a new migration",
+					ArgsUsage: "NAME",
+					Action:    h.Create,
+					Flags:     []cli.Flag{dirFlag},
+				},
+			},
 		},
 	}
-}
\ No newline at end of file
+}
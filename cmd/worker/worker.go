@@ -0,0 +1,112 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"multi-tenant-service/internal/tenant/usecase"
+	"multi-tenant-service/package/config"
+	"multi-tenant-service/package/logger"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	CmdServeWorker = "serve-worker"
+	FlagShardIndex = "shard-index"
+	FlagShardCount = "shard-count"
+	FlagOwnership  = "ownership"
+	FlagReplicaID  = "replica-id"
+)
+
+type Worker struct {
+	usecase usecase.ITenantUsecase
+	cfg     *config.Config
+}
+
+// ServeWorker runs tenant consumers and background tenant jobs without the
+// HTTP API. Tenants are split between replicas by shard or by lease.
+func (w Worker) ServeWorker(c *cli.Context) error {
+	if err := logger.SetLogger(); err != nil {
+		log.Printf("error logger %v", err)
+	}
+
+	switch c.String(FlagOwnership) {
+	case "lease":
+		replicaID := c.String(FlagReplicaID)
+		if replicaID == "" {
+			replicaID = defaultReplicaID()
+		}
+		w.usecase.EnableLeases(replicaID)
+		log.Printf("Starting worker %s with lease ownership", replicaID)
+	case "shard", "":
+		shard := usecase.HashShard{
+			Index: c.Int(FlagShardIndex),
+			Count: c.Int(FlagShardCount),
+		}
+		if shard.Count < 1 || shard.Index < 0 || shard.Index >= shard.Count {
+			return fmt.Errorf("invalid shard %d of %d", shard.Index, shard.Count)
+		}
+		w.usecase.SetOwnership(shard)
+		log.Printf("Starting worker for shard %d of %d", shard.Index, shard.Count)
+	default:
+		return fmt.Errorf("unknown ownership %q", c.String(FlagOwnership))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := w.usecase.ResumeDeletionJobs(ctx); err != nil {
+		log.Printf("error resuming deletion jobs %v", err)
+	}
+
+	w.usecase.Start(ctx)
+
+	<-ctx.Done()
+	ctx, cancel := context.WithTimeout(context.Background(), w.cfg.Server.ShutdownTimeout)
+	defer cancel()
+	return w.usecase.Shutdown(ctx)
+}
+
+func defaultReplicaID() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "worker"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+func NewWorker(usecase usecase.ITenantUsecase, cfg *config.Config) []*cli.Command {
+	w := &Worker{usecase: usecase, cfg: cfg}
+	return []*cli.Command{
+		{
+			Name:   CmdServeWorker,
+			Usage:  "Run tenant consumers without the HTTP API",
+			Action: w.ServeWorker,
+			Flags: []cli.Flag{
+				&cli.IntFlag{
+					Name:  FlagShardIndex,
+					Usage: "shard of tenants owned by this replica",
+					Value: cfg.Worker.ShardIndex,
+				},
+				&cli.IntFlag{
+					Name:  FlagShardCount,
+					Usage: "number of worker replicas tenants are split across",
+					Value: max(cfg.Worker.ShardCount, 1),
+				},
+				&cli.StringFlag{
+					Name:  FlagOwnership,
+					Usage: "how tenants are split between replicas: shard or lease",
+					Value: cfg.Worker.Ownership,
+				},
+				&cli.StringFlag{
+					Name:  FlagReplicaID,
+					Usage: "replica name used for tenant leases (default hostname-pid)",
+				},
+			},
+		},
+	}
+}
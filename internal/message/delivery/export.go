@@ -0,0 +1,189 @@
+package delivery
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"multi-tenant-service/package/response"
+	"multi-tenant-service/package/structs"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// exportFlushRows is how many rows are written between flushes.
+const exportFlushRows = 100
+
+// ExportMessages godoc
+// @Summary Export messages
+// @Description Stream the stored messages of a tenant as NDJSON or CSV, oldest first. The CSV columns are id, tenant_id, created_at and payload as JSON.
+// @Tags messages
+// @Produce json
+// @Produce text/csv
+// @Param id path string true "Tenant ID"
+// @Param format query string false "ndjson (default) or csv"
+// @Param from query string false "Only messages created at or after this RFC 3339 time"
+// @Param to query string false "Only messages created before this RFC 3339 time"
+// @Param filter query string false "Rules condition messages must match, e.g. $.payload.type == \"order\""
+// @Success 200 {string} string "exported messages"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tenants/{id}/messages/export [get]
+func (h *MessageHandler) ExportMessages(c echo.Context) error {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, "Invalid tenant ID", nil)
+	}
+
+	format := c.QueryParam("format")
+	if format == "" {
+		format = structs.ExportFormatNDJSON
+	}
+	if format != structs.ExportFormatNDJSON && format != structs.ExportFormatCSV {
+		return response.JSONResponse(c, http.StatusBadRequest, false, "format must be ndjson or csv", nil)
+	}
+
+	req := structs.ExportMessagesRequest{TenantID: tenantID, Filter: c.QueryParam("filter")}
+	if req.From, err = parseTimeParam(c, "from"); err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, "Invalid from", nil)
+	}
+	if req.To, err = parseTimeParam(c, "to"); err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, "Invalid to", nil)
+	}
+
+	res := c.Response()
+	csvWriter := csv.NewWriter(res)
+	started := false
+	start := func() error {
+		contentType := "application/x-ndjson"
+		if format == structs.ExportFormatCSV {
+			contentType = "text/csv"
+		}
+		res.Header().Set(echo.HeaderContentType, contentType)
+		res.Header().Set(echo.HeaderContentDisposition,
+			fmt.Sprintf(`attachment; filename="messages-%s.%s"`, tenantID, format))
+		res.WriteHeader(http.StatusOK)
+		started = true
+		if format == structs.ExportFormatCSV {
+			return csvWriter.Write([]string{"id", "tenant_id", "created_at", "payload"})
+		}
+		return nil
+	}
+
+	rows := 0
+	err = h.messageUsecase.ExportMessages(c.Request().Context(), req, func(msg structs.Message) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if err := writeExportRow(res, csvWriter, format, msg); err != nil {
+			return err
+		}
+		if rows++; rows%exportFlushRows == 0 {
+			csvWriter.Flush()
+			res.Flush()
+		}
+		return nil
+	})
+	if err == nil && !started {
+		err = start()
+	}
+	if started {
+		csvWriter.Flush()
+		if err != nil {
+			// The status is sent already, so abort the response instead: the
+			// connection is reset and the client cannot take a truncated
+			// export for a complete one
+			log.Printf("Export of tenant %s stopped after %d messages: %v", tenantID, rows, err)
+			panic(http.ErrAbortHandler)
+		}
+		return nil
+	}
+
+	switch {
+	case err.Error() == "tenant not found":
+		return response.JSONResponse(c, http.StatusNotFound, false, err.Error(), nil)
+	case err.Error() == "tenant is being deleted":
+		return response.JSONResponse(c, http.StatusConflict, false, err.Error(), nil)
+	case err.Error() == "from must be before to", strings.HasPrefix(err.Error(), "invalid filter: "):
+		return response.JSONResponse(c, http.StatusBadRequest, false, err.Error(), nil)
+	}
+	return response.JSONResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+}
+
+// parseTimeParam returns the RFC 3339 time in query parameter name, or nil
+// when it is not set.
+func parseTimeParam(c echo.Context, name string) (*time.Time, error) {
+	raw := c.QueryParam(name)
+	if raw == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func writeExportRow(res *echo.Response, csvWriter *csv.Writer, format string, msg structs.Message) error {
+	if format == structs.ExportFormatNDJSON {
+		data, err := json.Marshal(msg)
+		if err != nil {
+			return err
+		}
+		_, err = res.Write(append(data, '\n'))
+		return err
+	}
+
+	payload, err := json.Marshal(msg.Payload)
+	if err != nil {
+		return err
+	}
+	return csvWriter.Write([]string{
+		msg.ID.String(),
+		msg.TenantID.String(),
+		msg.CreatedAt.UTC().Format(time.RFC3339Nano),
+		string(payload),
+	})
+}
+
+// ImportMessages godoc
+// @Summary Import messages
+// @Description Bulk-load NDJSON messages, one object per line as produced by the export, into the tenant's stored messages. Ids and created_at are kept; missing ones are generated. The import is all or nothing and does not publish the messages.
+// @Tags messages
+// @Accept application/x-ndjson
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} structs.ImportMessagesResult
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tenants/{id}/messages/import [post]
+func (h *MessageHandler) ImportMessages(c echo.Context) error {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, "Invalid tenant ID", nil)
+	}
+
+	result, err := h.messageUsecase.ImportMessages(c.Request().Context(), tenantID, c.Request().Body)
+	if err != nil {
+		switch {
+		case err.Error() == "tenant not found":
+			return response.JSONResponse(c, http.StatusNotFound, false, err.Error(), nil)
+		case err.Error() == "tenant is being deleted", err.Error() == "message already exists":
+			return response.JSONResponse(c, http.StatusConflict, false, err.Error(), nil)
+		case strings.HasPrefix(err.Error(), "invalid import line "):
+			return response.JSONResponse(c, http.StatusBadRequest, false, err.Error(), nil)
+		}
+		return response.JSONResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+	}
+	return response.JSONSuccess(c, result, "Messages imported successfully")
+}
@@ -0,0 +1,183 @@
+package delivery
+
+import (
+	"context"
+	"strings"
+
+	"multi-tenant-service/internal/message/usecase"
+	"multi-tenant-service/package/auth"
+	pb "multi-tenant-service/package/pb/multitenant/v1"
+	"multi-tenant-service/package/structs"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxPublishBatch bounds the messages of one PublishBatch call.
+const maxPublishBatch = 500
+
+// MessageGRPCServer serves MessageService on top of the message usecase.
+type MessageGRPCServer struct {
+	pb.UnimplementedMessageServiceServer
+	messageUsecase usecase.IMessageUsecase
+}
+
+func NewMessageGRPCServer(s *grpc.Server, messageUsecase usecase.IMessageUsecase) {
+	pb.RegisterMessageServiceServer(s, &MessageGRPCServer{messageUsecase: messageUsecase})
+}
+
+func (s *MessageGRPCServer) Publish(ctx context.Context, req *pb.PublishRequest) (*emptypb.Empty, error) {
+	if err := s.publish(ctx, req); err != nil {
+		return nil, err
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *MessageGRPCServer) PublishBatch(ctx context.Context, req *pb.PublishBatchRequest) (*pb.PublishBatchResponse, error) {
+	if len(req.GetMessages()) > maxPublishBatch {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d messages per batch", maxPublishBatch)
+	}
+	resp := &pb.PublishBatchResponse{Results: make([]*pb.PublishResult, 0, len(req.GetMessages()))}
+	for _, msg := range req.GetMessages() {
+		result := &pb.PublishResult{Success: true}
+		if err := s.publish(ctx, msg); err != nil {
+			result.Success = false
+			result.Error = status.Convert(err).Message()
+		}
+		resp.Results = append(resp.Results, result)
+	}
+	return resp, nil
+}
+
+func (s *MessageGRPCServer) publish(ctx context.Context, req *pb.PublishRequest) error {
+	tenantID, err := authorizeMessageTenant(ctx, req.GetTenantId())
+	if err != nil {
+		return err
+	}
+	err = s.messageUsecase.PublishMessage(ctx, structs.CreateMessageRequest{
+		TenantID:    tenantID,
+		Payload:     req.GetPayload().AsMap(),
+		OrderingKey: req.GetOrderingKey(),
+	})
+	if err != nil {
+		return messageGRPCError(err)
+	}
+	return nil
+}
+
+func (s *MessageGRPCServer) ListMessages(ctx context.Context, req *pb.ListMessagesRequest) (*pb.ListMessagesResponse, error) {
+	tenantID, err := authorizeMessageTenant(ctx, req.GetTenantId())
+	if err != nil {
+		return nil, err
+	}
+	limit := int(req.GetLimit())
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+	list := structs.RequestGetMessage{TenantID: tenantID, Limit: limit}
+	if req.GetCursor() != "" {
+		cursor := req.GetCursor()
+		list.Cursor = &cursor
+	}
+
+	messages, err := s.messageUsecase.GetMessages(ctx, list)
+	if err != nil {
+		return nil, messageGRPCError(err)
+	}
+	resp := &pb.ListMessagesResponse{Messages: make([]*pb.Message, 0, len(messages.Data))}
+	for i := range messages.Data {
+		msg, err := messageToPB(&messages.Data[i], "")
+		if err != nil {
+			return nil, err
+		}
+		resp.Messages = append(resp.Messages, msg)
+	}
+	if messages.NextCursor != nil {
+		resp.NextCursor = *messages.NextCursor
+	}
+	return resp, nil
+}
+
+func (s *MessageGRPCServer) Subscribe(req *pb.SubscribeRequest, stream pb.MessageService_SubscribeServer) error {
+	ctx := stream.Context()
+	tenantID, err := authorizeMessageTenant(ctx, req.GetTenantId())
+	if err != nil {
+		return err
+	}
+
+	err = s.messageUsecase.StreamMessages(ctx, structs.RequestStreamMessages{
+		TenantID: tenantID,
+		Cursor:   req.GetCursor(),
+		Filter:   req.GetFilter(),
+	}, func(event structs.StreamEvent) error {
+		// HTTP/2 keepalives take the place of heartbeats
+		if event.Message == nil {
+			return nil
+		}
+		msg, err := messageToPB(event.Message, event.ID)
+		if err != nil {
+			return err
+		}
+		return stream.Send(msg)
+	})
+	if err != nil && ctx.Err() == nil {
+		return messageGRPCError(err)
+	}
+	return nil
+}
+
+func authorizeMessageTenant(ctx context.Context, raw string) (uuid.UUID, error) {
+	tenantID, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "Invalid tenant_id")
+	}
+	if err := auth.Authorize(ctx, tenantID.String()); err != nil {
+		if err == auth.ErrForbidden {
+			return uuid.Nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return uuid.Nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return tenantID, nil
+}
+
+// messageGRPCError maps usecase errors to status codes the way the REST
+// handlers map them to HTTP statuses.
+func messageGRPCError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case err.Error() == "tenant not found":
+		return status.Error(codes.NotFound, err.Error())
+	case err.Error() == "tenant is suspended", err.Error() == "tenant is being deleted":
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case err.Error() == "payload too large":
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case err.Error() == "invalid cursor", strings.HasPrefix(err.Error(), "invalid filter: "),
+		strings.HasPrefix(err.Error(), "invalid cursor format"):
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func messageToPB(msg *structs.Message, cursor string) (*pb.Message, error) {
+	payload, err := structpb.NewStruct(msg.Payload)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if cursor == "" {
+		cursor = structs.MessageCursor{CreatedAt: msg.CreatedAt, ID: msg.ID}.String()
+	}
+	return &pb.Message{
+		Id:        msg.ID.String(),
+		TenantId:  msg.TenantID.String(),
+		Payload:   payload,
+		CreatedAt: timestamppb.New(msg.CreatedAt),
+		Cursor:    cursor,
+	}, nil
+}
@@ -1,11 +1,17 @@
 package delivery
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
 	"multi-tenant-service/internal/message/usecase"
+	"multi-tenant-service/package/auth"
+	"multi-tenant-service/package/config"
 	"multi-tenant-service/package/response"
 	"multi-tenant-service/package/structs"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -13,6 +19,7 @@ import (
 
 type MessageHandler struct {
 	messageUsecase usecase.IMessageUsecase
+	cfg            *config.Config
 }
 
 // PublishMessage godoc
@@ -24,6 +31,9 @@ type MessageHandler struct {
 // @Param message body structs.CreateMessageRequest true "Message data"
 // @Success 202 {object} structs.Response
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 413 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /messages [post]
 func (h *MessageHandler) PublishMessage(c echo.Context) error {
@@ -32,8 +42,19 @@ func (h *MessageHandler) PublishMessage(c echo.Context) error {
 	if err := c.Bind(&req); err != nil {
 		return response.JSONResponse(c, http.StatusBadRequest, false, err.Error(), nil)
 	}
+	if err := auth.AuthorizeOptional(ctx, req.TenantID.String()); err != nil {
+		return response.JSONResponse(c, http.StatusForbidden, false, err.Error(), nil)
+	}
 
 	if err := h.messageUsecase.PublishMessage(ctx, req); err != nil {
+		switch err.Error() {
+		case "tenant not found":
+			return response.JSONResponse(c, http.StatusNotFound, false, err.Error(), nil)
+		case "tenant is suspended", "tenant is being deleted":
+			return response.JSONResponse(c, http.StatusConflict, false, err.Error(), nil)
+		case "payload too large":
+			return response.JSONResponse(c, http.StatusRequestEntityTooLarge, false, err.Error(), nil)
+		}
 		return response.JSONResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
 	}
 	return response.JSONResponse(c, http.StatusAccepted, true, "Message published successfully", nil)
@@ -62,6 +83,9 @@ func (h *MessageHandler) GetMessages(c echo.Context) error {
 	if err != nil {
 		return response.JSONResponse(c, http.StatusBadRequest, false, "Invalid tenant_id", nil)
 	}
+	if err := auth.AuthorizeOptional(ctx, tenantID.String()); err != nil {
+		return response.JSONResponse(c, http.StatusForbidden, false, err.Error(), nil)
+	}
 
 	cursor := c.QueryParam("cursor")
 	var cursorPtr *string
@@ -88,16 +112,103 @@ func (h *MessageHandler) GetMessages(c echo.Context) error {
 	return response.JSONSuccess(c, messages, "Messages retrieved successfully")
 }
 
-func NewMessageHandler(e *echo.Group, messageUsecase usecase.IMessageUsecase) *MessageHandler {
+// StreamMessages godoc
+// @Summary Stream new messages
+// @Description Push messages stored for a tenant as server-sent events. Each event id is a cursor; reconnecting with Last-Event-ID resumes after it.
+// @Tags messages
+// @Produce text/event-stream
+// @Param id path string true "Tenant ID"
+// @Param Last-Event-ID header string false "Resume after this cursor"
+// @Param cursor query string false "Resume after this cursor when Last-Event-ID cannot be set"
+// @Param filter query string false "Rules condition messages must match, e.g. $.payload.type == \"order\""
+// @Success 200 {string} string "event stream"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tenants/{id}/messages/stream [get]
+func (h *MessageHandler) StreamMessages(c echo.Context) error {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, "Invalid tenant ID", nil)
+	}
+
+	cursor := c.Request().Header.Get("Last-Event-ID")
+	if cursor == "" {
+		cursor = c.QueryParam("cursor")
+	}
+
+	req := structs.RequestStreamMessages{
+		TenantID: tenantID,
+		Cursor:   cursor,
+		Filter:   c.QueryParam("filter"),
+	}
+
+	res := c.Response()
+	started := false
+	err = h.messageUsecase.StreamMessages(c.Request().Context(), req, func(event structs.StreamEvent) error {
+		if !started {
+			res.Header().Set(echo.HeaderContentType, "text/event-stream")
+			res.Header().Set(echo.HeaderCacheControl, "no-cache")
+			res.Header().Set(echo.HeaderConnection, "keep-alive")
+			res.WriteHeader(http.StatusOK)
+			started = true
+		}
+		if err := writeStreamEvent(res, event); err != nil {
+			return err
+		}
+		res.Flush()
+		return nil
+	})
+	if err == nil || started {
+		// Once the stream is open the client only sees it end
+		return nil
+	}
+
+	switch {
+	case err.Error() == "tenant not found":
+		return response.JSONResponse(c, http.StatusNotFound, false, err.Error(), nil)
+	case err.Error() == "invalid cursor", strings.HasPrefix(err.Error(), "invalid filter: "):
+		return response.JSONResponse(c, http.StatusBadRequest, false, err.Error(), nil)
+	}
+	return response.JSONResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+}
+
+// writeStreamEvent writes a message as an SSE event, or a heartbeat as a
+// comment line.
+func writeStreamEvent(w io.Writer, event structs.StreamEvent) error {
+	if event.Message == nil {
+		_, err := io.WriteString(w, ": heartbeat\n\n")
+		return err
+	}
+	data, err := json.Marshal(event.Message)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %s\nevent: message\ndata: %s\n\n", event.ID, data)
+	return err
+}
+
+func NewMessageHandler(e *echo.Group, messageUsecase usecase.IMessageUsecase, cfg *config.Config) *MessageHandler {
 	return &MessageHandler{
 		messageUsecase: messageUsecase,
+		cfg:            cfg,
 	}
 }
 
-func NewMessageHTTPHandler(r *echo.Group, messageUsecase usecase.IMessageUsecase)  {
+func NewMessageHTTPHandler(r *echo.Group, messageUsecase usecase.IMessageUsecase, cfg *config.Config)  {
 	h := &MessageHandler{
 		messageUsecase: messageUsecase,
+		cfg:            cfg,
 	}
 	r.POST("/messages", h.PublishMessage).Name = "PublishMessage"
+	r.POST("/messages/request", h.RequestMessage).Name = "RequestMessage"
 	r.GET("/messages", h.GetMessages).Name = "GetMessages"
+	r.GET("/tenants/:id/messages/stream", h.StreamMessages).Name = "StreamMessages"
+	r.GET("/tenants/:id/messages/export", h.ExportMessages).Name = "ExportMessages"
+	r.POST("/tenants/:id/messages/import", h.ImportMessages).Name = "ImportMessages"
+	r.GET("/gateway", h.Gateway).Name = "Gateway"
+	r.POST("/tenants/:id/pull", h.PullMessages).Name = "PullMessages"
+	r.POST("/tenants/:id/ack", h.AckMessages).Name = "AckMessages"
+	r.POST("/tenants/:id/nack", h.NackMessages).Name = "NackMessages"
+	r.POST("/tenants/:id/extend", h.ExtendMessages).Name = "ExtendMessages"
 }
\ No newline at end of file
@@ -0,0 +1,130 @@
+package delivery
+
+import (
+	"context"
+	"multi-tenant-service/package/response"
+	"multi-tenant-service/package/structs"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// PullMessages godoc
+// @Summary Lease messages
+// @Description Lease up to max_messages messages of a pull-mode tenant. They stay hidden from other pulls for the visibility timeout and must be acked with their receipt handles, or they are handed out again.
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param request body structs.PullRequest false "Pull options"
+// @Success 200 {object} structs.Response
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tenants/{id}/pull [post]
+func (h *MessageHandler) PullMessages(c echo.Context) error {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, "Invalid tenant ID", nil)
+	}
+
+	var req structs.PullRequest
+	if c.Request().ContentLength != 0 {
+		if err := c.Bind(&req); err != nil {
+			return response.JSONResponse(c, http.StatusBadRequest, false, err.Error(), nil)
+		}
+	}
+
+	messages, err := h.messageUsecase.PullMessages(c.Request().Context(), tenantID, req)
+	if err != nil {
+		return pullErrorResponse(c, err)
+	}
+	return response.JSONSuccess(c, messages, "Messages leased successfully")
+}
+
+// AckMessages godoc
+// @Summary Acknowledge pulled messages
+// @Description Delete leased messages by receipt handle
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param request body structs.ReceiptRequest true "Receipt handles"
+// @Success 200 {object} structs.Response
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tenants/{id}/ack [post]
+func (h *MessageHandler) AckMessages(c echo.Context) error {
+	return h.settleReceipts(c, h.messageUsecase.AckMessages, "Messages acknowledged")
+}
+
+// NackMessages godoc
+// @Summary Return pulled messages
+// @Description Make leased messages available again after visibility_timeout seconds
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param request body structs.ReceiptRequest true "Receipt handles and redelivery delay"
+// @Success 200 {object} structs.Response
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tenants/{id}/nack [post]
+func (h *MessageHandler) NackMessages(c echo.Context) error {
+	return h.settleReceipts(c, h.messageUsecase.NackMessages, "Messages returned")
+}
+
+// ExtendMessages godoc
+// @Summary Extend message leases
+// @Description Restart the visibility timeout of leased messages
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param request body structs.ReceiptRequest true "Receipt handles and new visibility timeout"
+// @Success 200 {object} structs.Response
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tenants/{id}/extend [post]
+func (h *MessageHandler) ExtendMessages(c echo.Context) error {
+	return h.settleReceipts(c, h.messageUsecase.ExtendMessages, "Leases extended")
+}
+
+func (h *MessageHandler) settleReceipts(c echo.Context, settle func(ctx context.Context, tenantID uuid.UUID, req structs.ReceiptRequest) (*structs.ReceiptResult, error), msg string) error {
+	tenantID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, "Invalid tenant ID", nil)
+	}
+
+	var req structs.ReceiptRequest
+	if err := c.Bind(&req); err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, err.Error(), nil)
+	}
+
+	result, err := settle(c.Request().Context(), tenantID, req)
+	if err != nil {
+		return pullErrorResponse(c, err)
+	}
+	return response.JSONSuccess(c, result, msg)
+}
+
+func pullErrorResponse(c echo.Context, err error) error {
+	if strings.Contains(err.Error(), " must be between ") {
+		return response.JSONResponse(c, http.StatusBadRequest, false, err.Error(), nil)
+	}
+	switch err.Error() {
+	case "tenant not found":
+		return response.JSONResponse(c, http.StatusNotFound, false, err.Error(), nil)
+	case "receipt_handles must not be empty", "too many receipt handles":
+		return response.JSONResponse(c, http.StatusBadRequest, false, err.Error(), nil)
+	case "tenant is not in pull mode", "tenant is suspended", "tenant is being deleted":
+		return response.JSONResponse(c, http.StatusConflict, false, err.Error(), nil)
+	}
+	return response.JSONResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+}
@@ -0,0 +1,60 @@
+package delivery
+
+import (
+	"multi-tenant-service/package/auth"
+	"multi-tenant-service/package/response"
+	"multi-tenant-service/package/structs"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequestMessage godoc
+// @Summary Send a request and wait for the reply
+// @Description Publish a message with reply_to and correlation_id set and wait for the tenant pipeline to reply. The reply is the payload set by a pipeline stage, such as a webhook with reply enabled, or else the processed payload.
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param request body structs.MessageRequest true "Request message"
+// @Success 200 {object} structs.Response
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 413 {object} map[string]string
+// @Failure 502 {object} map[string]string
+// @Failure 504 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /messages/request [post]
+func (h *MessageHandler) RequestMessage(c echo.Context) error {
+	var req structs.MessageRequest
+	if err := c.Bind(&req); err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, err.Error(), nil)
+	}
+	if err := auth.AuthorizeOptional(c.Request().Context(), req.TenantID.String()); err != nil {
+		return response.JSONResponse(c, http.StatusForbidden, false, err.Error(), nil)
+	}
+
+	reply, err := h.messageUsecase.RequestMessage(c.Request().Context(), req)
+	if err != nil {
+		if strings.Contains(err.Error(), " must be between ") {
+			return response.JSONResponse(c, http.StatusBadRequest, false, err.Error(), nil)
+		}
+		switch err.Error() {
+		case "tenant not found":
+			return response.JSONResponse(c, http.StatusNotFound, false, err.Error(), nil)
+		case "tenant is suspended", "tenant is being deleted", "tenant is in pull mode", "request dropped by rules":
+			return response.JSONResponse(c, http.StatusConflict, false, err.Error(), nil)
+		case "payload too large":
+			return response.JSONResponse(c, http.StatusRequestEntityTooLarge, false, err.Error(), nil)
+		case "request timed out":
+			return response.JSONResponse(c, http.StatusGatewayTimeout, false, err.Error(), nil)
+		}
+		return response.JSONResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+	}
+	if reply.Error != "" {
+		// The pipeline gave up on the request and dead-lettered it
+		return response.JSONResponse(c, http.StatusBadGateway, false, reply.Error, reply)
+	}
+	return response.JSONSuccess(c, reply, "Reply received successfully")
+}
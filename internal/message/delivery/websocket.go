@@ -0,0 +1,80 @@
+package delivery
+
+import (
+	"log"
+	"multi-tenant-service/package/auth"
+	"multi-tenant-service/package/response"
+	"multi-tenant-service/package/structs"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/websocket"
+)
+
+// maxGatewayFrameBytes bounds the frames a client can send.
+const maxGatewayFrameBytes = 1 << 20
+
+// Gateway godoc
+// @Summary WebSocket gateway
+// @Description Upgrade to a WebSocket that subscribes to tenant streams or subscriptions, publishes messages and acknowledges deliveries with JSON frames. Authenticate with a bearer token in the Authorization header or the token query parameter.
+// @Tags messages
+// @Param token query string false "Bearer token, for clients that cannot set headers"
+// @Success 101 {string} string "switching protocols"
+// @Failure 401 {object} map[string]string
+// @Router /gateway [get]
+func (h *MessageHandler) Gateway(c echo.Context) error {
+	// With REST auth on, the middleware verified the token already
+	claims, ok := auth.FromContext(c.Request().Context())
+	if !ok {
+		token, err := auth.BearerToken(c.Request().Header.Get(echo.HeaderAuthorization))
+		if err == auth.ErrMissingToken {
+			token, err = c.QueryParam("token"), nil
+		}
+		if err == nil {
+			claims, err = auth.ParseToken(h.cfg.JWT.Secret, token)
+		}
+		if err != nil {
+			return response.JSONResponse(c, http.StatusUnauthorized, false, err.Error(), nil)
+		}
+	}
+
+	// The token authenticates the client, so the origin check is not needed
+	server := websocket.Server{Handler: func(ws *websocket.Conn) {
+		defer ws.Close()
+		ws.MaxPayloadBytes = maxGatewayFrameBytes
+
+		session := h.messageUsecase.OpenGateway(c.Request().Context(), claims)
+		defer session.Close()
+
+		go h.writeGatewayFrames(ws, session.Outbox(), session.Done())
+
+		for {
+			var frame structs.GatewayFrame
+			if err := websocket.JSON.Receive(ws, &frame); err != nil {
+				return
+			}
+			session.Handle(frame)
+		}
+	}}
+	server.ServeHTTP(c.Response(), c.Request())
+	return nil
+}
+
+// writeGatewayFrames writes frames until the session ends. A client that does
+// not read for WriteTimeout is disconnected.
+func (h *MessageHandler) writeGatewayFrames(ws *websocket.Conn, out <-chan structs.GatewayFrame, done <-chan struct{}) {
+	for {
+		select {
+		case frame := <-out:
+			ws.SetWriteDeadline(time.Now().Add(h.cfg.Gateway.WriteTimeout))
+			if err := websocket.JSON.Send(ws, frame); err != nil {
+				log.Printf("Closing gateway connection: %v", err)
+				ws.Close()
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"multi-tenant-service/package/structs"
+
+	"github.com/google/uuid"
+)
+
+// GetGatewayCursor returns the last message the gateway client acknowledged
+// on the tenant stream, or nil if it never acknowledged one.
+func (r *MessageRepository) GetGatewayCursor(ctx context.Context, subject string, tenantID uuid.UUID) (*structs.MessageCursor, error) {
+	var cursor structs.MessageCursor
+	err := r.db.QueryRowContext(ctx, `
+		SELECT created_at, message_id FROM gateway_cursors
+		WHERE subject = $1 AND tenant_id = $2
+	`, subject, tenantID).Scan(&cursor.CreatedAt, &cursor.ID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gateway cursor: %w", err)
+	}
+	return &cursor, nil
+}
+
+// SaveGatewayCursor records the last message the gateway client acknowledged
+// on the tenant stream. A cursor behind the stored one is ignored.
+func (r *MessageRepository) SaveGatewayCursor(ctx context.Context, subject string, tenantID uuid.UUID, cursor structs.MessageCursor) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO gateway_cursors (subject, tenant_id, created_at, message_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (subject, tenant_id) DO UPDATE SET
+			created_at = EXCLUDED.created_at,
+			message_id = EXCLUDED.message_id,
+			updated_at = NOW()
+		WHERE (gateway_cursors.created_at, gateway_cursors.message_id) < (EXCLUDED.created_at, EXCLUDED.message_id)
+	`, subject, tenantID, cursor.CreatedAt, cursor.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save gateway cursor: %w", err)
+	}
+	return nil
+}
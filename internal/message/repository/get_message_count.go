@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -14,4 +15,14 @@ func (r *MessageRepository) GetMessageCount(ctx context.Context, tenantID uuid.U
 		return 0, fmt.Errorf("failed to get message count: %w", err)
 	}
 	return  count, nil
-}
\ No newline at end of file
+}
+// CountMessagesBetween counts the messages stored from from up to, but not
+// including, to.
+func (r *MessageRepository) CountMessagesBetween(ctx context.Context, tenantID uuid.UUID, from, to time.Time) (int64, error) {
+	var count int64
+	query := "SELECT COUNT(*) FROM messages WHERE tenant_id = $1 AND created_at >= $2 AND created_at < $3"
+	if err := r.db.QueryRowContext(ctx, query, tenantID, from, to).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count messages: %w", err)
+	}
+	return count, nil
+}
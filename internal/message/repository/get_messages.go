@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"multi-tenant-service/package/structs"
 	"time"
@@ -40,12 +41,28 @@ func (r *MessageRepository) 	GetMessages(ctx context.Context, req structs.Reques
 	defer rows.Close()
 
 	for rows.Next() {
-		var msg structs.Message
-		if err := rows.Scan(&msg.ID, &msg.TenantID, &msg.Payload, &msg.CreatedAt); err != nil {
-			return nil, fmt.Errorf("failed to scan message: %w", err)
+		msg, err := scanMessage(rows)
+		if err != nil {
+			return nil, err
 		}
 		messages = append(messages, msg)
 	}
 
 	return messages, nil
+}
+
+// scanMessage reads id, tenant_id, payload and created_at, decoding the
+// JSONB payload.
+func scanMessage(row interface{ Scan(...interface{}) error }) (structs.Message, error) {
+	var msg structs.Message
+	var payload []byte
+	if err := row.Scan(&msg.ID, &msg.TenantID, &payload, &msg.CreatedAt); err != nil {
+		return msg, fmt.Errorf("failed to scan message: %w", err)
+	}
+	if len(payload) > 0 {
+		if err := json.Unmarshal(payload, &msg.Payload); err != nil {
+			return msg, fmt.Errorf("failed to decode message payload: %w", err)
+		}
+	}
+	return msg, nil
 }
\ No newline at end of file
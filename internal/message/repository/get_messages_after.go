@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"multi-tenant-service/package/structs"
+
+	"github.com/google/uuid"
+)
+
+// GetMessagesAfter returns up to limit messages that come after cursor.
+func (r *MessageRepository) GetMessagesAfter(ctx context.Context, tenantID uuid.UUID, cursor structs.MessageCursor, limit int) ([]structs.Message, error) {
+	query := `
+		SELECT id, tenant_id, payload, created_at
+		FROM messages
+		WHERE tenant_id = $1 AND (created_at, id) > ($2, $3)
+		ORDER BY created_at ASC, id ASC
+		LIMIT $4
+	`
+	rows, err := r.db.QueryContext(ctx, query, tenantID, cursor.CreatedAt, cursor.ID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []structs.Message
+	for rows.Next() {
+		msg, err := scanMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, msg)
+	}
+	return messages, rows.Err()
+}
+
+// GetLatestMessageCursor returns the position of the newest message, or the
+// zero cursor when the tenant has none.
+func (r *MessageRepository) GetLatestMessageCursor(ctx context.Context, tenantID uuid.UUID) (structs.MessageCursor, error) {
+	var cursor structs.MessageCursor
+	err := r.db.QueryRowContext(ctx, `
+		SELECT created_at, id FROM messages
+		WHERE tenant_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT 1
+	`, tenantID).Scan(&cursor.CreatedAt, &cursor.ID)
+	if err == sql.ErrNoRows {
+		return structs.MessageCursor{}, nil
+	}
+	if err != nil {
+		return cursor, fmt.Errorf("failed to get latest message: %w", err)
+	}
+	return cursor, nil
+}
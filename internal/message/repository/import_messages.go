@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	repoTenant "multi-tenant-service/internal/tenant/repository"
+	"multi-tenant-service/package/structs"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// ImportMessages loads the messages next returns until io.EOF into the
+// tenant partition, keeping their ids and timestamps. Nothing is stored
+// unless every message is.
+//
+// The primary key includes created_at, so it does not stop an id from being
+// stored twice with different timestamps. The messages are therefore copied
+// into a staging table first and only inserted when none of their ids is
+// taken, while holding the lock partition maintenance takes for the tenant.
+func (r *MessageRepository) ImportMessages(ctx context.Context, tenantID uuid.UUID, next func() (*structs.Message, error)) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	table := repoTenant.PartitionTable(tenantID.String())
+	if err := repoTenant.LockPartition(ctx, tx, table); err != nil {
+		return 0, err
+	}
+	_, err = tx.ExecContext(ctx, `
+		CREATE TEMP TABLE message_import (id UUID, tenant_id UUID, payload JSONB, created_at TIMESTAMPTZ)
+		ON COMMIT DROP
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create staging table: %w", err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn("message_import", "id", "tenant_id", "payload", "created_at"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to start copy: %w", err)
+	}
+	defer stmt.Close()
+
+	var imported int64
+	for {
+		msg, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		payload, err := json.Marshal(msg.Payload)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode payload: %w", err)
+		}
+		if _, err := stmt.ExecContext(ctx, msg.ID, tenantID, string(payload), msg.CreatedAt); err != nil {
+			return 0, copyError(err)
+		}
+		imported++
+	}
+
+	// The rows are only checked by the server once the copy ends
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		return 0, copyError(err)
+	}
+	if err := stmt.Close(); err != nil {
+		return 0, copyError(err)
+	}
+
+	var taken bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM message_import i JOIN messages m ON m.tenant_id = $1 AND m.id = i.id
+		) OR EXISTS (
+			SELECT 1 FROM message_import GROUP BY id HAVING COUNT(*) > 1
+		)
+	`, tenantID).Scan(&taken)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check message ids: %w", err)
+	}
+	if taken {
+		return 0, fmt.Errorf("message already exists")
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s (id, tenant_id, payload, created_at)
+		SELECT id, tenant_id, payload, created_at FROM message_import
+	`, table)
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return 0, copyError(err)
+	}
+	return imported, tx.Commit()
+}
+
+func copyError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+		return fmt.Errorf("message already exists")
+	}
+	return fmt.Errorf("failed to copy messages: %w", err)
+}
@@ -2,22 +2,35 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log"
 	"multi-tenant-service/package/structs"
 )
 
+// InsertMessage stores a message and notifies streams of the tenant.
 func (r MessageRepository) InsertMessage(ctx context.Context, req structs.CreateMessageRequest) error {
+	payload, err := json.Marshal(req.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+
 	// Store message in database
 	query := `
 		INSERT INTO messages (tenant_id, payload)
 		VALUES ($1, $2)
 	`
-	_, err := r.db.ExecContext(ctx, query, req.TenantID, req.Payload)
+	_, err = r.db.ExecContext(ctx, query, req.TenantID, payload)
 	if err != nil {
 		return fmt.Errorf("failed to store message: %w", err)
 	}
 
+	if r.notifier != nil {
+		if err := r.notifier.Notify(ctx, req.TenantID.String()); err != nil {
+			log.Printf("Failed to notify streams of tenant %s: %v", req.TenantID, err)
+		}
+	}
+
 	log.Printf("Processed message for tenant %s", req.TenantID)
 	return nil
 }
\ No newline at end of file
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"multi-tenant-service/package/structs"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// InsertMessageLease stores a message taken off the tenant queue by a pull.
+func (r *MessageRepository) InsertMessageLease(ctx context.Context, lease structs.MessageLease) error {
+	headers, err := json.Marshal(lease.Headers)
+	if err != nil {
+		return fmt.Errorf("failed to encode headers: %w", err)
+	}
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO message_leases (message_id, receipt_handle, tenant_id, body, headers, receive_count, visible_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, lease.MessageID, lease.ReceiptHandle, lease.TenantID, lease.Body, headers, lease.ReceiveCount, lease.VisibleAt)
+	if err != nil {
+		return fmt.Errorf("failed to store message lease: %w", err)
+	}
+	return nil
+}
+
+// RenewExpiredLeases leases again up to limit messages whose visibility
+// timeout ran out, giving each a new receipt handle.
+func (r *MessageRepository) RenewExpiredLeases(ctx context.Context, tenantID uuid.UUID, limit int, visibility time.Duration) ([]structs.MessageLease, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE message_leases SET
+			receipt_handle = uuid_generate_v4(),
+			receive_count = receive_count + 1,
+			visible_at = NOW() + $3 * INTERVAL '1 millisecond'
+		WHERE message_id IN (
+			SELECT message_id FROM message_leases
+			WHERE tenant_id = $1 AND visible_at <= NOW()
+			ORDER BY created_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING message_id, receipt_handle, tenant_id, body, headers, receive_count, visible_at
+	`, tenantID, limit, visibility.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to renew message leases: %w", err)
+	}
+	defer rows.Close()
+
+	var leases []structs.MessageLease
+	for rows.Next() {
+		var lease structs.MessageLease
+		var headers []byte
+		if err := rows.Scan(&lease.MessageID, &lease.ReceiptHandle, &lease.TenantID, &lease.Body,
+			&headers, &lease.ReceiveCount, &lease.VisibleAt); err != nil {
+			return nil, fmt.Errorf("failed to scan message lease: %w", err)
+		}
+		if len(headers) > 0 {
+			if err := json.Unmarshal(headers, &lease.Headers); err != nil {
+				return nil, fmt.Errorf("failed to decode lease headers: %w", err)
+			}
+		}
+		leases = append(leases, lease)
+	}
+	return leases, rows.Err()
+}
+
+// DeleteMessageLeases removes the leases with the given receipt handles and
+// returns the handles it found.
+func (r *MessageRepository) DeleteMessageLeases(ctx context.Context, tenantID uuid.UUID, handles []uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		DELETE FROM message_leases
+		WHERE tenant_id = $1 AND receipt_handle = ANY($2)
+		RETURNING receipt_handle
+	`, tenantID, pq.Array(handles))
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete message leases: %w", err)
+	}
+	return scanReceiptHandles(rows)
+}
+
+// SetLeaseVisibility makes the leases with the given receipt handles visible
+// again after visibility. Expired leases are left alone, since another pull
+// may already have them. It returns the handles it changed.
+func (r *MessageRepository) SetLeaseVisibility(ctx context.Context, tenantID uuid.UUID, handles []uuid.UUID, visibility time.Duration) ([]uuid.UUID, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		UPDATE message_leases SET visible_at = NOW() + $3 * INTERVAL '1 millisecond'
+		WHERE tenant_id = $1 AND receipt_handle = ANY($2) AND visible_at > NOW()
+		RETURNING receipt_handle
+	`, tenantID, pq.Array(handles), visibility.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to update message leases: %w", err)
+	}
+	return scanReceiptHandles(rows)
+}
+
+func scanReceiptHandles(rows *sql.Rows) ([]uuid.UUID, error) {
+	defer rows.Close()
+
+	var handles []uuid.UUID
+	for rows.Next() {
+		var handle uuid.UUID
+		if err := rows.Scan(&handle); err != nil {
+			return nil, fmt.Errorf("failed to scan receipt handle: %w", err)
+		}
+		handles = append(handles, handle)
+	}
+	return handles, rows.Err()
+}
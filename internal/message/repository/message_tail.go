@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"multi-tenant-service/package/structs"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// commitLag bounds how long after its created_at a message is assumed to
+// commit. created_at is taken when the insert starts, so a message can become
+// visible after messages with a later created_at were already read.
+const commitLag = 10 * time.Second
+
+// MessageTail reads a tenant's messages in keyset order from a cursor without
+// losing the ones that commit late. Every pass after the first reads again
+// from commitLag behind the furthest message returned, never before the
+// starting cursor, and skips the ids it returned already. Late messages are
+// therefore returned after messages that come later in keyset order.
+type MessageTail struct {
+	repo     IMessageRepository
+	tenantID uuid.UUID
+	start    structs.MessageCursor
+	cursor   structs.MessageCursor
+	scan     structs.MessageCursor
+	seen     map[uuid.UUID]time.Time
+}
+
+// NewMessageTail returns a tail reading the messages after cursor.
+func NewMessageTail(repo IMessageRepository, tenantID uuid.UUID, cursor structs.MessageCursor) *MessageTail {
+	return &MessageTail{
+		repo:     repo,
+		tenantID: tenantID,
+		start:    cursor,
+		cursor:   cursor,
+		scan:     cursor,
+		seen:     make(map[uuid.UUID]time.Time),
+	}
+}
+
+// Next reads one batch of up to limit messages and returns those not
+// returned before. caughtUp reports that the batch reached the newest
+// message and ended a pass; the next call starts a new one. A reader that
+// stops at the end reads until it has caught up twice, so the second pass
+// picks up what committed late during the first.
+func (t *MessageTail) Next(ctx context.Context, limit int) (messages []structs.Message, caughtUp bool, err error) {
+	batch, err := t.repo.GetMessagesAfter(ctx, t.tenantID, t.scan, limit)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, msg := range batch {
+		position := structs.MessageCursor{CreatedAt: msg.CreatedAt, ID: msg.ID}
+		t.scan = position
+		if _, ok := t.seen[msg.ID]; ok {
+			continue
+		}
+		t.seen[msg.ID] = msg.CreatedAt
+		if t.cursor.Before(position) {
+			t.cursor = position
+		}
+		messages = append(messages, msg)
+	}
+	if len(batch) == limit {
+		return messages, false, nil
+	}
+
+	t.Rescan()
+	return messages, true, nil
+}
+
+// Rescan ends the current pass, so the next batch is read from inside the lag
+// window. Readers with an end bound call it once they read past the bound.
+func (t *MessageTail) Rescan() {
+	horizon := t.cursor.CreatedAt.Add(-commitLag)
+	t.scan = structs.MessageCursor{CreatedAt: horizon}
+	if t.scan.Before(t.start) {
+		t.scan = t.start
+	}
+	// Messages behind the window are not read again
+	for id, createdAt := range t.seen {
+		if createdAt.Before(horizon) {
+			delete(t.seen, id)
+		}
+	}
+}
+
+// Cursor returns the furthest position returned so far. Resuming from it
+// skips messages that were still to commit behind it.
+func (t *MessageTail) Cursor() structs.MessageCursor {
+	return t.cursor
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"multi-tenant-service/package/connection/database"
+	"multi-tenant-service/package/notifier"
 	"multi-tenant-service/package/structs"
 	"time"
 
@@ -10,19 +11,33 @@ import (
 )
 
 type MessageRepository struct {
-	db *database.DB
+	db       *database.DB
+	notifier notifier.Notifier
 }
 
 type IMessageRepository interface {
 	PublishMessage(ctx context.Context, req structs.CreateMessageRequest) error
 	GetMessages(ctx context.Context, req structs.RequestGetMessage, cursorTime time.Time) ([]structs.Message,error)
 	GetMessageCount(ctx context.Context, tenantID uuid.UUID) (int, error)
+	CountMessagesBetween(ctx context.Context, tenantID uuid.UUID, from, to time.Time) (int64, error)
 	InsertMessage(ctx context.Context, req structs.CreateMessageRequest) error
+	ImportMessages(ctx context.Context, tenantID uuid.UUID, next func() (*structs.Message, error)) (int64, error)
+	GetMessagesAfter(ctx context.Context, tenantID uuid.UUID, cursor structs.MessageCursor, limit int) ([]structs.Message, error)
+	GetLatestMessageCursor(ctx context.Context, tenantID uuid.UUID) (structs.MessageCursor, error)
+	GetGatewayCursor(ctx context.Context, subject string, tenantID uuid.UUID) (*structs.MessageCursor, error)
+	SaveGatewayCursor(ctx context.Context, subject string, tenantID uuid.UUID, cursor structs.MessageCursor) error
+	InsertMessageLease(ctx context.Context, lease structs.MessageLease) error
+	RenewExpiredLeases(ctx context.Context, tenantID uuid.UUID, limit int, visibility time.Duration) ([]structs.MessageLease, error)
+	DeleteMessageLeases(ctx context.Context, tenantID uuid.UUID, handles []uuid.UUID) ([]uuid.UUID, error)
+	SetLeaseVisibility(ctx context.Context, tenantID uuid.UUID, handles []uuid.UUID, visibility time.Duration) ([]uuid.UUID, error)
 }
 
 
-func NewMessageRepository(db *database.DB) IMessageRepository {
+// NewMessageRepository returns a repository that notifies n about every
+// stored message; n may be nil.
+func NewMessageRepository(db *database.DB, n notifier.Notifier) IMessageRepository {
 	return &MessageRepository{
-		db: db,
+		db:       db,
+		notifier: n,
 	}
 }
\ No newline at end of file
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"multi-tenant-service/internal/message/repository"
+	"multi-tenant-service/package/rules"
+	"multi-tenant-service/package/structs"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const exportBatchSize = 500
+
+// ExportMessages emits the tenant's stored messages in the requested range
+// that pass the filter, oldest first apart from messages that committed
+// late. They are read in batches, so the export never holds more than one
+// batch. Errors about the request are returned before anything is emitted.
+func (mu *MessageUsecase) ExportMessages(ctx context.Context, req structs.ExportMessagesRequest, emit func(structs.Message) error) error {
+	if _, err := mu.exportableTenant(ctx, req.TenantID); err != nil {
+		return err
+	}
+	if req.From != nil && req.To != nil && !req.From.Before(*req.To) {
+		return fmt.Errorf("from must be before to")
+	}
+
+	var filter *rules.Expr
+	if req.Filter != "" {
+		var err error
+		if filter, err = rules.Compile(req.Filter); err != nil {
+			return fmt.Errorf("invalid filter: %v", err)
+		}
+	}
+
+	var cursor structs.MessageCursor
+	if req.From != nil {
+		cursor.CreatedAt = *req.From
+	}
+	// The second pass picks up messages that committed late during the first
+	tail := repository.NewMessageTail(mu.repository, req.TenantID, cursor)
+	for passes := 0; passes < 2; {
+		messages, caughtUp, err := tail.Next(ctx, exportBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, msg := range messages {
+			if req.To != nil && !msg.CreatedAt.Before(*req.To) {
+				continue
+			}
+			if filter != nil && !filter.Match(map[string]interface{}{
+				"payload": msg.Payload,
+				"headers": map[string]interface{}{},
+			}) {
+				continue
+			}
+			if err := emit(msg); err != nil {
+				return err
+			}
+		}
+		if !caughtUp && req.To != nil && !tail.Cursor().CreatedAt.Before(*req.To) {
+			tail.Rescan()
+			caughtUp = true
+		}
+		if caughtUp {
+			passes++
+		}
+	}
+	return nil
+}
+
+// ImportMessages bulk-loads NDJSON messages, one JSON object per line in the
+// export format, into the tenant's messages. Ids and created_at are kept and
+// generated when missing. Imported messages are stored only; they do not go
+// through the tenant queue.
+func (mu *MessageUsecase) ImportMessages(ctx context.Context, tenantID uuid.UUID, body io.Reader) (*structs.ImportMessagesResult, error) {
+	if _, err := mu.exportableTenant(ctx, tenantID); err != nil {
+		return nil, err
+	}
+
+	lines := bufio.NewReader(body)
+	line := 0
+	imported, err := mu.repository.ImportMessages(ctx, tenantID, func() (*structs.Message, error) {
+		for {
+			data, err := lines.ReadBytes('\n')
+			if err != nil && !errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("failed to read import: %w", err)
+			}
+			if len(data) == 0 && errors.Is(err, io.EOF) {
+				return nil, io.EOF
+			}
+			line++
+			data = bytes.TrimSpace(data)
+			if len(data) == 0 {
+				continue
+			}
+
+			var msg structs.Message
+			if err := json.Unmarshal(data, &msg); err != nil {
+				return nil, fmt.Errorf("invalid import line %d: %v", line, err)
+			}
+			if msg.TenantID != uuid.Nil && msg.TenantID != tenantID {
+				return nil, fmt.Errorf("invalid import line %d: tenant_id does not match", line)
+			}
+			if msg.ID == uuid.Nil {
+				msg.ID = uuid.New()
+			}
+			if msg.CreatedAt.IsZero() {
+				msg.CreatedAt = time.Now()
+			}
+			return &msg, nil
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &structs.ImportMessagesResult{Imported: imported}, nil
+}
+
+// exportableTenant returns the tenant unless it is deleted or being deleted.
+func (mu *MessageUsecase) exportableTenant(ctx context.Context, tenantID uuid.UUID) (*structs.Tenant, error) {
+	tenant, err := mu.repoTenant.GetTenant(ctx, tenantID.String())
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case tenant.ID == uuid.Nil, tenant.Status == structs.TenantStatusDeleted:
+		return nil, fmt.Errorf("tenant not found")
+	case tenant.Status == structs.TenantStatusDeleting:
+		return nil, fmt.Errorf("tenant is being deleted")
+	}
+	return tenant, nil
+}
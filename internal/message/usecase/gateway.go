@@ -0,0 +1,414 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"multi-tenant-service/package/auth"
+	"multi-tenant-service/package/broker"
+	"multi-tenant-service/package/rules"
+	"multi-tenant-service/package/structs"
+	"strconv"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// GatewaySession is the state of one gateway connection: its subscriptions
+// and the deliveries pushed to it that are not acknowledged yet.
+//
+// Unacknowledged deliveries are redelivered after a reconnect. Subscription
+// queue deliveries are requeued when the session closes; tenant streams
+// resume after the last message the client acknowledged, which is stored
+// per token subject.
+type GatewaySession struct {
+	mu     *MessageUsecase
+	claims auth.Claims
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// out holds the frames the transport writes to the client.
+	out chan structs.GatewayFrame
+	// credits bounds the unacknowledged deliveries, so a slow client only
+	// holds up its own subscriptions.
+	credits chan struct{}
+
+	lock    sync.Mutex
+	nextID  uint64
+	subs    map[string]*gatewaySub
+	pending map[string]*gatewayDelivery
+	wg      sync.WaitGroup
+}
+
+type gatewaySub struct {
+	key      string
+	tenantID uuid.UUID
+	cancel   context.CancelFunc
+	done     chan struct{}
+	// queued is set for subscription queues; tenant streams read the
+	// messages table instead.
+	queued bool
+	// unacked lists the stream deliveries in the order they were pushed.
+	unacked []*gatewayDelivery
+}
+
+type gatewayDelivery struct {
+	id       string
+	sub      *gatewaySub
+	cursor   structs.MessageCursor
+	delivery broker.Delivery
+	acked    bool
+}
+
+// OpenGateway starts a session for a client authenticated with claims. It
+// ends when ctx is done or Close is called.
+func (mu *MessageUsecase) OpenGateway(ctx context.Context, claims auth.Claims) *GatewaySession {
+	ctx, cancel := context.WithCancel(ctx)
+	maxInFlight := mu.cfg.Gateway.MaxInFlight
+	return &GatewaySession{
+		mu:      mu,
+		claims:  claims,
+		ctx:     ctx,
+		cancel:  cancel,
+		out:     make(chan structs.GatewayFrame, maxInFlight+16),
+		credits: make(chan struct{}, maxInFlight),
+		subs:    make(map[string]*gatewaySub),
+		pending: make(map[string]*gatewayDelivery),
+	}
+}
+
+// Outbox returns the frames to write to the client.
+func (s *GatewaySession) Outbox() <-chan structs.GatewayFrame { return s.out }
+
+// Done is closed when the session ends.
+func (s *GatewaySession) Done() <-chan struct{} { return s.ctx.Done() }
+
+// Handle processes a frame sent by the client. Replies go to the outbox.
+func (s *GatewaySession) Handle(frame structs.GatewayFrame) {
+	var err error
+	switch frame.Op {
+	case structs.GatewayOpSubscribe:
+		err = s.subscribe(frame)
+	case structs.GatewayOpUnsubscribe:
+		err = s.unsubscribe(frame)
+	case structs.GatewayOpPublish:
+		err = s.publish(frame)
+	case structs.GatewayOpAck:
+		err = s.ack(frame)
+	default:
+		err = fmt.Errorf("unknown op %q", frame.Op)
+	}
+	if err != nil {
+		s.fail(frame, err)
+	}
+}
+
+// Close stops every subscription. Subscription queue deliveries that were
+// not acknowledged go back to their queue.
+func (s *GatewaySession) Close() {
+	s.cancel()
+	s.wg.Wait()
+}
+
+func (s *GatewaySession) send(frame structs.GatewayFrame) bool {
+	return s.sendContext(s.ctx, frame)
+}
+
+func (s *GatewaySession) sendContext(ctx context.Context, frame structs.GatewayFrame) bool {
+	select {
+	case s.out <- frame:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (s *GatewaySession) fail(frame structs.GatewayFrame, err error) {
+	s.send(structs.GatewayFrame{
+		Op:           structs.GatewayOpError,
+		ID:           frame.ID,
+		TenantID:     frame.TenantID,
+		Subscription: frame.Subscription,
+		Error:        err.Error(),
+	})
+}
+
+func subKey(frame structs.GatewayFrame) string {
+	if frame.Subscription == "" {
+		return frame.TenantID.String()
+	}
+	return frame.TenantID.String() + "/" + frame.Subscription
+}
+
+func (s *GatewaySession) subscribe(frame structs.GatewayFrame) error {
+	if !s.claims.Allows(frame.TenantID.String()) {
+		return fmt.Errorf("tenant not allowed")
+	}
+	if frame.Subscription != "" && !rules.ValidSubscription(frame.Subscription) {
+		return fmt.Errorf("invalid subscription")
+	}
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	sub := &gatewaySub{
+		key:      subKey(frame),
+		tenantID: frame.TenantID,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+		queued:   frame.Subscription != "",
+	}
+
+	s.lock.Lock()
+	if _, ok := s.subs[sub.key]; ok {
+		s.lock.Unlock()
+		cancel()
+		return fmt.Errorf("already subscribed")
+	}
+	s.subs[sub.key] = sub
+	s.lock.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer close(sub.done)
+		defer s.forget(sub)
+
+		var err error
+		if sub.queued {
+			err = s.pumpQueue(ctx, sub, frame)
+		} else {
+			err = s.pumpStream(ctx, sub, frame)
+		}
+		if err != nil && ctx.Err() == nil {
+			s.fail(frame, err)
+		}
+	}()
+	return nil
+}
+
+// pumpStream pushes the stored messages of a tenant, resuming after the last
+// one the client acknowledged unless the frame names a cursor.
+func (s *GatewaySession) pumpStream(ctx context.Context, sub *gatewaySub, frame structs.GatewayFrame) error {
+	req := structs.RequestStreamMessages{
+		TenantID: frame.TenantID,
+		Cursor:   frame.Cursor,
+		Filter:   frame.Filter,
+	}
+	if req.Cursor == "" {
+		stored, err := s.mu.repository.GetGatewayCursor(ctx, s.claims.Subject, frame.TenantID)
+		if err != nil {
+			return err
+		}
+		if stored != nil {
+			req.Cursor = stored.String()
+		}
+	}
+
+	subscribed := false
+	return s.mu.StreamMessages(ctx, req, func(event structs.StreamEvent) error {
+		if event.Message == nil {
+			op := structs.GatewayOpHeartbeat
+			if !subscribed {
+				// The first heartbeat means the request was valid
+				op, subscribed = structs.GatewayOpSubscribed, true
+			}
+			s.sendContext(ctx, structs.GatewayFrame{Op: op, ID: frame.ID, TenantID: frame.TenantID})
+			return nil
+		}
+
+		body, err := json.Marshal(event.Message)
+		if err != nil {
+			return err
+		}
+		// The event id, unlike the message position, never moves back
+		cursor, err := structs.ParseMessageCursor(event.ID)
+		if err != nil {
+			return err
+		}
+		return s.push(ctx, sub, &gatewayDelivery{cursor: cursor}, structs.GatewayFrame{
+			TenantID: frame.TenantID,
+			Cursor:   event.ID,
+			Message:  body,
+		})
+	})
+}
+
+// pumpQueue pushes the messages routed to a subscription. The broker holds
+// back further deliveries while MaxInFlight of them are unacknowledged.
+func (s *GatewaySession) pumpQueue(ctx context.Context, sub *gatewaySub, frame structs.GatewayFrame) error {
+	tenant, err := s.mu.repoTenant.GetTenant(ctx, frame.TenantID.String())
+	if err != nil {
+		return err
+	}
+	if tenant.ID == uuid.Nil || tenant.Status == structs.TenantStatusDeleted {
+		return fmt.Errorf("tenant not found")
+	}
+
+	queueName := rules.SubscriptionQueue(frame.TenantID.String(), frame.Subscription)
+	if _, err := s.mu.mq.DeclareQueue(ctx, queueName, broker.QueueOptions{}); err != nil {
+		return fmt.Errorf("failed to declare queue: %w", err)
+	}
+	queue, err := s.mu.mq.Consume(ctx, queueName, broker.ConsumeOptions{
+		Tag:      fmt.Sprintf("gateway-%s-%s", s.claims.Subject, uuid.NewString()),
+		Prefetch: s.mu.cfg.Gateway.MaxInFlight,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to consume queue: %w", err)
+	}
+	defer func() {
+		if err := queue.Close(); err != nil {
+			log.Printf("Failed to close gateway subscription %s: %v", sub.key, err)
+		}
+	}()
+
+	s.sendContext(ctx, structs.GatewayFrame{
+		Op:           structs.GatewayOpSubscribed,
+		ID:           frame.ID,
+		TenantID:     frame.TenantID,
+		Subscription: frame.Subscription,
+	})
+
+	for {
+		var d broker.Delivery
+		var ok bool
+		select {
+		case d, ok = <-queue.Deliveries():
+			if !ok {
+				return fmt.Errorf("subscription closed by broker")
+			}
+		case <-ctx.Done():
+			return nil
+		}
+
+		if !json.Valid(d.Body) {
+			log.Printf("Dropping malformed message on %s", queueName)
+			d.Nack(false)
+			continue
+		}
+		// Deliveries left unacknowledged are requeued when the queue is closed
+		err := s.push(ctx, sub, &gatewayDelivery{delivery: d}, structs.GatewayFrame{
+			TenantID:     frame.TenantID,
+			Subscription: frame.Subscription,
+			Message:      d.Body,
+		})
+		if err != nil {
+			return nil
+		}
+	}
+}
+
+// push waits for a credit, records the delivery as pending and sends it.
+func (s *GatewaySession) push(ctx context.Context, sub *gatewaySub, delivery *gatewayDelivery, frame structs.GatewayFrame) error {
+	select {
+	case s.credits <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	s.lock.Lock()
+	s.nextID++
+	delivery.id = strconv.FormatUint(s.nextID, 10)
+	delivery.sub = sub
+	s.pending[delivery.id] = delivery
+	if !sub.queued {
+		sub.unacked = append(sub.unacked, delivery)
+	}
+	s.lock.Unlock()
+
+	frame.Op = structs.GatewayOpMessage
+	frame.Delivery = delivery.id
+	if !s.sendContext(ctx, frame) {
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (s *GatewaySession) ack(frame structs.GatewayFrame) error {
+	s.lock.Lock()
+	delivery, ok := s.pending[frame.Delivery]
+	if !ok {
+		s.lock.Unlock()
+		return fmt.Errorf("unknown delivery")
+	}
+	delete(s.pending, frame.Delivery)
+	<-s.credits
+
+	sub := delivery.sub
+	if sub.queued {
+		s.lock.Unlock()
+		return delivery.delivery.Ack()
+	}
+
+	// The stored cursor only moves past deliveries acknowledged without gaps,
+	// so anything unacknowledged before it is redelivered on reconnect
+	delivery.acked = true
+	var cursor *structs.MessageCursor
+	for len(sub.unacked) > 0 && sub.unacked[0].acked {
+		cursor = &sub.unacked[0].cursor
+		sub.unacked = sub.unacked[1:]
+	}
+	s.lock.Unlock()
+
+	if cursor == nil {
+		return nil
+	}
+	return s.mu.repository.SaveGatewayCursor(s.ctx, s.claims.Subject, sub.tenantID, *cursor)
+}
+
+func (s *GatewaySession) unsubscribe(frame structs.GatewayFrame) error {
+	s.lock.Lock()
+	sub, ok := s.subs[subKey(frame)]
+	s.lock.Unlock()
+	if !ok {
+		return fmt.Errorf("not subscribed")
+	}
+
+	s.stop(sub)
+	s.send(structs.GatewayFrame{
+		Op:           structs.GatewayOpUnsubscribed,
+		ID:           frame.ID,
+		TenantID:     frame.TenantID,
+		Subscription: frame.Subscription,
+	})
+	return nil
+}
+
+// stop ends a subscription and waits for its pump to exit.
+func (s *GatewaySession) stop(sub *gatewaySub) {
+	sub.cancel()
+	<-sub.done
+}
+
+// forget removes a subscription that ended along with its pending
+// deliveries; acks for them are rejected afterwards.
+func (s *GatewaySession) forget(sub *gatewaySub) {
+	s.lock.Lock()
+	if s.subs[sub.key] == sub {
+		delete(s.subs, sub.key)
+	}
+	for id, delivery := range s.pending {
+		if delivery.sub == sub {
+			delete(s.pending, id)
+			<-s.credits
+		}
+	}
+	sub.unacked = nil
+	s.lock.Unlock()
+}
+
+// publish publishes a message with the same checks as PublishMessage.
+func (s *GatewaySession) publish(frame structs.GatewayFrame) error {
+	if !s.claims.Allows(frame.TenantID.String()) {
+		return fmt.Errorf("tenant not allowed")
+	}
+	err := s.mu.PublishMessage(s.ctx, structs.CreateMessageRequest{
+		TenantID:    frame.TenantID,
+		Payload:     frame.Payload,
+		OrderingKey: frame.OrderingKey,
+	})
+	if err != nil {
+		return err
+	}
+	s.send(structs.GatewayFrame{Op: structs.GatewayOpPublished, ID: frame.ID, TenantID: frame.TenantID})
+	return nil
+}
@@ -4,54 +4,179 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
+	"multi-tenant-service/package/broker"
+	"multi-tenant-service/package/rules"
 	"multi-tenant-service/package/structs"
+	"time"
 
 	"github.com/google/uuid"
-	amqp "github.com/rabbitmq/amqp091-go"
 )
 
 func (mu *MessageUsecase) PublishMessage(ctx context.Context, req structs.CreateMessageRequest)  error {
+	_, err := mu.publishMessage(ctx, req, "", "")
+	return err
+}
+
+// publishMessage publishes req to the tenant queue, asking for a reply on
+// replyTo when it is set. It reports false when the rules dropped the message.
+func (mu *MessageUsecase) publishMessage(ctx context.Context, req structs.CreateMessageRequest, replyTo, correlationID string) (bool, error) {
 	tenant, err := mu.repoTenant.GetTenant(ctx, req.TenantID.String())
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	if tenant.ID == uuid.Nil {
-		return fmt.Errorf("tenant not found")
+		return false, fmt.Errorf("tenant not found")
 	}
 
-	queueName := fmt.Sprintf("tenant_%s_queue", req.TenantID.String())
-	// Create channel for publishing
-	ch, err := mu.mqClient.CreateChannel("publisher")
-	if err != nil {
-		return fmt.Errorf("failed to create channel: %w", err)
+	switch tenant.Status {
+	case structs.TenantStatusDeleted:
+		return false, fmt.Errorf("tenant not found")
+	case structs.TenantStatusDeleting:
+		return false, fmt.Errorf("tenant is being deleted")
+	case structs.TenantStatusSuspended:
+		// Buffered messages wait in the tenant queue until the consumer
+		// resumes; a request would only time out there
+		if mu.cfg.Tenant.SuspendedPublishPolicy != "buffer" || replyTo != "" {
+			return false, fmt.Errorf("tenant is suspended")
+		}
+	}
+	if replyTo != "" && tenant.Settings.Delivery == structs.DeliveryPull {
+		return false, fmt.Errorf("tenant is in pull mode")
 	}
 
+	queueName := fmt.Sprintf("tenant_%s_queue", req.TenantID.String())
 	// Ensure queue exists
-	_, err = mu.mqClient.DeclareQueue(ch, queueName)
-	if err != nil {
-		return fmt.Errorf("failed to declare queue: %w", err)
+	if _, err := mu.mq.DeclareQueue(ctx, queueName, broker.QueueOptions{}); err != nil {
+		return false, fmt.Errorf("failed to declare queue: %w", err)
 	}
 
 	// Marshal message
 	body, err := json.Marshal(req)
 	if err != nil {
-		return fmt.Errorf("failed to marshal message: %w", err)
+		return false, fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	if limit := tenant.Settings.Limits.MaxPayloadBytes; limit > 0 && len(body) > limit {
+		return false, fmt.Errorf("payload too large")
+	}
+
+	msg := broker.Message{
+		ContentType:   "application/json",
+		Body:          body,
+		CorrelationID: correlationID,
+		ReplyTo:       replyTo,
+	}
+	if req.OrderingKey != "" {
+		msg.Headers = map[string]interface{}{structs.HeaderOrderingKey: req.OrderingKey}
+	}
+
+	var routes []string
+	if len(tenant.Settings.Rules) > 0 && tenant.Settings.RulesPhase != rules.PhaseConsume {
+		result, err := mu.applyRules(tenant, req.Payload, msg.Headers)
+		if err != nil {
+			return false, err
+		}
+		if result.Dropped && len(result.Routes) == 0 {
+			return false, nil
+		}
+		req.Payload = result.Payload
+		if msg.Body, err = json.Marshal(req); err != nil {
+			return false, fmt.Errorf("failed to marshal message: %w", err)
+		}
+		msg.Headers = result.Headers
+		// Only replay jobs mark messages as replayed
+		delete(msg.Headers, structs.HeaderReplayed)
+		delete(msg.Headers, structs.HeaderReplayJob)
+		if len(msg.Headers) == 0 {
+			msg.Headers = nil
+		}
+		routes = result.Routes
+
+		if result.Dropped {
+			// Route followed by drop sends the message only to the subscriptions
+			return false, mu.publishRoutes(ctx, tenant, msg, routes)
+		}
+	}
+
+	if req.OrderingKey != "" {
+		// Rules may rewrite headers but not the key the message was published with
+		if msg.Headers == nil {
+			msg.Headers = map[string]interface{}{}
+		}
+		msg.Headers[structs.HeaderOrderingKey] = req.OrderingKey
 	}
 
 	// Publish message
-	err = ch.PublishWithContext(ctx,
-		"",        // exchange
-		queueName, // routing key
-		false,     // mandatory
-		false,     // immediate
-		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        body,
-		})
+	err = mu.mq.Publish(ctx, queueName, msg)
+	if err != nil {
+		return false, fmt.Errorf("failed to publish message: %w", err)
+	}
+
+	// Copies go out only once the message itself is accepted, so a failed
+	// publish does not leave them behind. Failing the request now would make
+	// the client publish the message twice, so a failed copy is only logged
+	if err := mu.publishRoutes(ctx, tenant, msg, routes); err != nil {
+		log.Printf("Failed to route message for tenant %s: %v", tenant.ID, err)
+	}
+	return true, nil
+}
+
+// compiledRules is a tenant's rules engine and the tenant version it was
+// compiled from.
+type compiledRules struct {
+	version time.Time
+	engine  *rules.Engine
+}
+
+// rulesEngine returns the compiled rules of the tenant, compiling them again
+// only when the tenant was updated since.
+func (mu *MessageUsecase) rulesEngine(tenant *structs.Tenant) (*rules.Engine, error) {
+	mu.rulesMu.Lock()
+	defer mu.rulesMu.Unlock()
+
+	if cached, ok := mu.rules[tenant.ID]; ok && cached.version.Equal(tenant.UpdatedAt) {
+		return cached.engine, nil
+	}
+	engine, err := rules.New(tenant.Settings.Rules)
+	if err != nil {
+		return nil, err
+	}
+	mu.rules[tenant.ID] = compiledRules{version: tenant.UpdatedAt, engine: engine}
+	return engine, nil
+}
+
+// applyRules runs the tenant rules at publish time on the payload and the
+// headers the message is published with.
+func (mu *MessageUsecase) applyRules(tenant *structs.Tenant, payload, headers map[string]interface{}) (*rules.Result, error) {
+	engine, err := mu.rulesEngine(tenant)
 	if err != nil {
-		return fmt.Errorf("failed to publish message: %w", err)
+		return nil, fmt.Errorf("failed to compile rules: %w", err)
 	}
+	result, err := engine.Apply(payload, headers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply rules: %w", err)
+	}
+	return result, nil
+}
 
+// publishRoutes sends a copy of the message to every subscription the rules
+// routed it to. The copies never ask for a reply.
+func (mu *MessageUsecase) publishRoutes(ctx context.Context, tenant *structs.Tenant, msg broker.Message, routes []string) error {
+	for _, subscription := range routes {
+		queueName := rules.SubscriptionQueue(tenant.ID.String(), subscription)
+		if _, err := mu.mq.DeclareQueue(ctx, queueName, broker.QueueOptions{}); err != nil {
+			return fmt.Errorf("failed to declare queue: %w", err)
+		}
+		err := mu.mq.Publish(ctx, queueName, broker.Message{
+			ContentType: msg.ContentType,
+			Body:        msg.Body,
+			Headers:     msg.Headers,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to route message to %s: %w", subscription, err)
+		}
+	}
 	return nil
 }
\ No newline at end of file
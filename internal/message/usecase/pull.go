@@ -0,0 +1,299 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"multi-tenant-service/package/broker"
+	"multi-tenant-service/package/structs"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxPullMessages      = 100
+	maxPullWait          = 20 * time.Second
+	maxVisibilityTimeout = 12 * time.Hour
+	// pullIdle is how long a pull that already has a message waits for more.
+	pullIdle = 50 * time.Millisecond
+)
+
+// PullMessages leases up to req.MaxMessages messages of a pull-mode tenant.
+// Messages whose lease ran out are handed out again first, then new ones are
+// taken off the tenant queue. A message received more often than
+// tenant.max_delivery_attempts goes to the dead-letter queue instead.
+func (mu *MessageUsecase) PullMessages(ctx context.Context, tenantID uuid.UUID, req structs.PullRequest) ([]structs.PulledMessage, error) {
+	if req.MaxMessages == 0 {
+		req.MaxMessages = 1
+	}
+	if req.MaxMessages < 1 || req.MaxMessages > maxPullMessages {
+		return nil, fmt.Errorf("max_messages must be between 1 and %d", maxPullMessages)
+	}
+	wait := time.Duration(req.WaitSeconds) * time.Second
+	if wait < 0 || wait > maxPullWait {
+		return nil, fmt.Errorf("wait_seconds must be between 0 and %d", int(maxPullWait.Seconds()))
+	}
+	visibility, err := mu.visibilityTimeout(req.VisibilityTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	tenant, err := mu.repoTenant.GetTenant(ctx, tenantID.String())
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case tenant.ID == uuid.Nil || tenant.Status == structs.TenantStatusDeleted:
+		return nil, fmt.Errorf("tenant not found")
+	case tenant.Status == structs.TenantStatusDeleting:
+		return nil, fmt.Errorf("tenant is being deleted")
+	case tenant.Status == structs.TenantStatusSuspended:
+		return nil, fmt.Errorf("tenant is suspended")
+	case tenant.Settings.Delivery != structs.DeliveryPull:
+		return nil, fmt.Errorf("tenant is not in pull mode")
+	}
+
+	renewed, err := mu.repository.RenewExpiredLeases(ctx, tenantID, req.MaxMessages, visibility)
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]structs.PulledMessage, 0, req.MaxMessages)
+	for _, lease := range renewed {
+		if lease.ReceiveCount > mu.maxReceives() {
+			if err := mu.deadLetterLease(ctx, lease); err != nil {
+				log.Printf("Failed to dead-letter message %s of tenant %s: %v", lease.MessageID, tenantID, err)
+			}
+			continue
+		}
+		messages = append(messages, pulledMessage(lease))
+	}
+
+	if len(messages) < req.MaxMessages {
+		leased, err := mu.leaseFromQueue(ctx, tenantID, req.MaxMessages-len(messages), visibility, wait)
+		for _, lease := range leased {
+			messages = append(messages, pulledMessage(lease))
+		}
+		if err != nil && len(messages) == 0 {
+			return nil, err
+		}
+		if err != nil {
+			log.Printf("Pull for tenant %s stopped early: %v", tenantID, err)
+		}
+	}
+	return messages, nil
+}
+
+// leaseFromQueue moves up to n messages from the tenant queue into leases.
+// It waits up to wait for the first one, then only briefly for more.
+func (mu *MessageUsecase) leaseFromQueue(ctx context.Context, tenantID uuid.UUID, n int, visibility, wait time.Duration) ([]structs.MessageLease, error) {
+	queueName := fmt.Sprintf("tenant_%s_queue", tenantID.String())
+	if _, err := mu.mq.DeclareQueue(ctx, queueName, broker.QueueOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to declare queue: %w", err)
+	}
+	sub, err := mu.mq.Consume(ctx, queueName, broker.ConsumeOptions{
+		Tag:      fmt.Sprintf("pull_%s_%s", tenantID.String(), uuid.NewString()),
+		Prefetch: n,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to consume queue: %w", err)
+	}
+	// Deliveries that were not leased go back to the queue
+	defer func() {
+		if err := sub.Close(); err != nil {
+			log.Printf("Failed to close pull subscription for tenant %s: %v", tenantID, err)
+		}
+	}()
+
+	if wait < pullIdle {
+		wait = pullIdle
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	var leases []structs.MessageLease
+	for len(leases) < n {
+		var d broker.Delivery
+		var ok bool
+		select {
+		case d, ok = <-sub.Deliveries():
+			if !ok {
+				return leases, nil
+			}
+		case <-timer.C:
+			return leases, nil
+		case <-ctx.Done():
+			return leases, nil
+		}
+
+		lease := structs.MessageLease{
+			MessageID:     uuid.New(),
+			ReceiptHandle: uuid.New(),
+			TenantID:      tenantID,
+			Body:          d.Body,
+			Headers:       d.Headers,
+			ReceiveCount:  1,
+			VisibleAt:     time.Now().Add(visibility),
+		}
+		if err := mu.repository.InsertMessageLease(ctx, lease); err != nil {
+			d.Nack(true)
+			return leases, err
+		}
+		// The message now lives in the lease; if the ack is lost it is
+		// delivered twice, never dropped
+		if err := d.Ack(); err != nil {
+			log.Printf("Failed to ack pulled message of tenant %s: %v", tenantID, err)
+		}
+		leases = append(leases, lease)
+		timer.Reset(pullIdle)
+	}
+	return leases, nil
+}
+
+// AckMessages deletes the leased messages for good.
+func (mu *MessageUsecase) AckMessages(ctx context.Context, tenantID uuid.UUID, req structs.ReceiptRequest) (*structs.ReceiptResult, error) {
+	return mu.settleReceipts(ctx, tenantID, req, "receipt handle not found",
+		func(handles []uuid.UUID) ([]uuid.UUID, error) {
+			return mu.repository.DeleteMessageLeases(ctx, tenantID, handles)
+		})
+}
+
+// NackMessages makes the leased messages available again after
+// req.VisibilityTimeout seconds.
+func (mu *MessageUsecase) NackMessages(ctx context.Context, tenantID uuid.UUID, req structs.ReceiptRequest) (*structs.ReceiptResult, error) {
+	if req.VisibilityTimeout < 0 || time.Duration(req.VisibilityTimeout)*time.Second > maxVisibilityTimeout {
+		return nil, fmt.Errorf("visibility_timeout must be between 0 and %d", int(maxVisibilityTimeout.Seconds()))
+	}
+	delay := time.Duration(req.VisibilityTimeout) * time.Second
+	return mu.settleReceipts(ctx, tenantID, req, "lease not found or expired",
+		func(handles []uuid.UUID) ([]uuid.UUID, error) {
+			return mu.repository.SetLeaseVisibility(ctx, tenantID, handles, delay)
+		})
+}
+
+// ExtendMessages restarts the visibility timeout of the leased messages with
+// req.VisibilityTimeout seconds, or the default when it is 0.
+func (mu *MessageUsecase) ExtendMessages(ctx context.Context, tenantID uuid.UUID, req structs.ReceiptRequest) (*structs.ReceiptResult, error) {
+	visibility, err := mu.visibilityTimeout(req.VisibilityTimeout)
+	if err != nil {
+		return nil, err
+	}
+	return mu.settleReceipts(ctx, tenantID, req, "lease not found or expired",
+		func(handles []uuid.UUID) ([]uuid.UUID, error) {
+			return mu.repository.SetLeaseVisibility(ctx, tenantID, handles, visibility)
+		})
+}
+
+// settleReceipts applies update to the valid receipt handles and reports
+// which of them it found.
+func (mu *MessageUsecase) settleReceipts(ctx context.Context, tenantID uuid.UUID, req structs.ReceiptRequest, notFound string, update func([]uuid.UUID) ([]uuid.UUID, error)) (*structs.ReceiptResult, error) {
+	if len(req.ReceiptHandles) == 0 {
+		return nil, fmt.Errorf("receipt_handles must not be empty")
+	}
+	if len(req.ReceiptHandles) > maxPullMessages {
+		return nil, fmt.Errorf("too many receipt handles")
+	}
+
+	tenant, err := mu.repoTenant.GetTenant(ctx, tenantID.String())
+	if err != nil {
+		return nil, err
+	}
+	if tenant.ID == uuid.Nil || tenant.Status == structs.TenantStatusDeleted {
+		return nil, fmt.Errorf("tenant not found")
+	}
+
+	result := &structs.ReceiptResult{Succeeded: []string{}, Failed: []structs.ReceiptFailure{}}
+	handles := make([]uuid.UUID, 0, len(req.ReceiptHandles))
+	for _, raw := range req.ReceiptHandles {
+		handle, err := uuid.Parse(raw)
+		if err != nil {
+			result.Failed = append(result.Failed, structs.ReceiptFailure{ReceiptHandle: raw, Error: "invalid receipt handle"})
+			continue
+		}
+		handles = append(handles, handle)
+	}
+	if len(handles) == 0 {
+		return result, nil
+	}
+
+	updated, err := update(handles)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[uuid.UUID]bool, len(updated))
+	for _, handle := range updated {
+		found[handle] = true
+	}
+	for _, handle := range handles {
+		if found[handle] {
+			result.Succeeded = append(result.Succeeded, handle.String())
+		} else {
+			result.Failed = append(result.Failed, structs.ReceiptFailure{ReceiptHandle: handle.String(), Error: notFound})
+		}
+	}
+	return result, nil
+}
+
+func (mu *MessageUsecase) visibilityTimeout(seconds int) (time.Duration, error) {
+	visibility := time.Duration(seconds) * time.Second
+	if visibility < 0 || visibility > maxVisibilityTimeout {
+		return 0, fmt.Errorf("visibility_timeout must be between 0 and %d", int(maxVisibilityTimeout.Seconds()))
+	}
+	if visibility == 0 {
+		visibility = mu.cfg.Pull.VisibilityTimeout
+	}
+	return visibility, nil
+}
+
+func (mu *MessageUsecase) maxReceives() int {
+	if mu.cfg.Tenant.MaxDeliveryAttempts <= 0 {
+		return 5
+	}
+	return mu.cfg.Tenant.MaxDeliveryAttempts
+}
+
+// deadLetterLease moves a message that was received too often to the tenant
+// dead-letter queue.
+func (mu *MessageUsecase) deadLetterLease(ctx context.Context, lease structs.MessageLease) error {
+	headers := make(map[string]interface{}, len(lease.Headers)+2)
+	for k, v := range lease.Headers {
+		headers[k] = v
+	}
+	headers["x-attempts"] = int64(lease.ReceiveCount - 1)
+	headers["x-error"] = "receive count exceeded"
+
+	dlq := fmt.Sprintf("tenant_%s_dlq", lease.TenantID.String())
+	if _, err := mu.mq.DeclareQueue(ctx, dlq, broker.QueueOptions{}); err != nil {
+		return fmt.Errorf("failed to declare queue: %w", err)
+	}
+	err := mu.mq.Publish(ctx, dlq, broker.Message{
+		ContentType: "application/json",
+		Body:        lease.Body,
+		Headers:     headers,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish to dead-letter queue: %w", err)
+	}
+	_, err = mu.repository.DeleteMessageLeases(ctx, lease.TenantID, []uuid.UUID{lease.ReceiptHandle})
+	return err
+}
+
+func pulledMessage(lease structs.MessageLease) structs.PulledMessage {
+	msg := structs.PulledMessage{
+		MessageID:     lease.MessageID,
+		ReceiptHandle: lease.ReceiptHandle,
+		Headers:       lease.Headers,
+		ReceiveCount:  lease.ReceiveCount,
+		VisibleAt:     lease.VisibleAt,
+	}
+	var req structs.CreateMessageRequest
+	if err := json.Unmarshal(lease.Body, &req); err != nil {
+		log.Printf("Failed to decode pulled message %s: %v", lease.MessageID, err)
+		return msg
+	}
+	msg.Payload = req.Payload
+	msg.OrderingKey = req.OrderingKey
+	return msg
+}
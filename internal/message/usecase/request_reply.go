@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"multi-tenant-service/package/broker"
+	"multi-tenant-service/package/structs"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// replyQueue is the callback queue of this process. Every request names it
+// as reply_to, and the replies arriving on it are matched to the waiting
+// requests by correlation ID.
+type replyQueue struct {
+	name string
+	sub  broker.Subscription
+
+	mu      sync.Mutex
+	pending map[string]chan structs.MessageReply
+	closed  bool
+}
+
+// RequestMessage publishes a request to the tenant queue and waits for the
+// reply the tenant pipeline sends once it has processed it.
+func (mu *MessageUsecase) RequestMessage(ctx context.Context, req structs.MessageRequest) (*structs.MessageReply, error) {
+	timeout := time.Duration(req.Timeout) * time.Second
+	if timeout < 0 || timeout > mu.cfg.Request.MaxTimeout {
+		return nil, fmt.Errorf("timeout must be between 0 and %d", int(mu.cfg.Request.MaxTimeout.Seconds()))
+	}
+	if timeout == 0 {
+		timeout = mu.cfg.Request.Timeout
+	}
+
+	replies, err := mu.replyQueue(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Register before publishing so a fast reply is not missed
+	correlationID := uuid.NewString()
+	reply := replies.expect(correlationID)
+	defer replies.forget(correlationID)
+
+	published, err := mu.publishMessage(ctx, structs.CreateMessageRequest{
+		TenantID:    req.TenantID,
+		Payload:     req.Payload,
+		OrderingKey: req.OrderingKey,
+	}, replies.name, correlationID)
+	if err != nil {
+		return nil, err
+	}
+	if !published {
+		return nil, fmt.Errorf("request dropped by rules")
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case r, ok := <-reply:
+		if !ok {
+			return nil, fmt.Errorf("reply queue closed")
+		}
+		return &r, nil
+	case <-timer.C:
+		return nil, fmt.Errorf("request timed out")
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// replyQueue returns the callback queue, declaring it on the first request
+// and again after the broker dropped the previous one.
+func (mu *MessageUsecase) replyQueue(ctx context.Context) (*replyQueue, error) {
+	mu.replyMu.Lock()
+	defer mu.replyMu.Unlock()
+
+	if mu.replies != nil && !mu.replies.isClosed() {
+		return mu.replies, nil
+	}
+
+	name := fmt.Sprintf("replies_%s", uuid.NewString())
+	if _, err := mu.mq.DeclareQueue(ctx, name, broker.QueueOptions{AutoDelete: true}); err != nil {
+		return nil, fmt.Errorf("failed to declare reply queue: %w", err)
+	}
+	sub, err := mu.mq.Consume(ctx, name, broker.ConsumeOptions{Tag: name, Exclusive: true})
+	if err != nil {
+		mu.mq.DeleteQueue(context.WithoutCancel(ctx), name)
+		return nil, fmt.Errorf("failed to consume reply queue: %w", err)
+	}
+
+	q := &replyQueue{
+		name:    name,
+		sub:     sub,
+		pending: make(map[string]chan structs.MessageReply),
+	}
+	go q.run()
+	mu.replies = q
+	return q, nil
+}
+
+// Close stops receiving replies and deletes the callback queue.
+func (mu *MessageUsecase) Close() error {
+	mu.replyMu.Lock()
+	defer mu.replyMu.Unlock()
+
+	if mu.replies == nil {
+		return nil
+	}
+	q := mu.replies
+	mu.replies = nil
+	if err := q.sub.Cancel(); err != nil {
+		log.Printf("Failed to cancel reply queue %s: %v", q.name, err)
+	}
+	return mu.mq.DeleteQueue(context.Background(), q.name)
+}
+
+// run hands every reply to the request waiting for it. Replies nobody waits
+// for any more, because the request timed out, are dropped.
+func (q *replyQueue) run() {
+	for d := range q.sub.Deliveries() {
+		if err := d.Ack(); err != nil {
+			log.Printf("Failed to ack reply on %s: %v", q.name, err)
+		}
+
+		var reply structs.MessageReply
+		if err := json.Unmarshal(d.Body, &reply); err != nil {
+			log.Printf("Dropping invalid reply on %s: %v", q.name, err)
+			continue
+		}
+		if reply.CorrelationID == "" {
+			reply.CorrelationID = d.CorrelationID
+		}
+
+		q.mu.Lock()
+		if ch, ok := q.pending[reply.CorrelationID]; ok {
+			ch <- reply
+			delete(q.pending, reply.CorrelationID)
+		}
+		q.mu.Unlock()
+	}
+
+	// The subscription ended; fail the requests still waiting
+	q.mu.Lock()
+	q.closed = true
+	for correlationID, ch := range q.pending {
+		close(ch)
+		delete(q.pending, correlationID)
+	}
+	q.mu.Unlock()
+}
+
+func (q *replyQueue) expect(correlationID string) <-chan structs.MessageReply {
+	ch := make(chan structs.MessageReply, 1)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		close(ch)
+		return ch
+	}
+	q.pending[correlationID] = ch
+	return ch
+}
+
+func (q *replyQueue) forget(correlationID string) {
+	q.mu.Lock()
+	delete(q.pending, correlationID)
+	q.mu.Unlock()
+}
+
+func (q *replyQueue) isClosed() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.closed
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"multi-tenant-service/internal/message/repository"
+	"multi-tenant-service/package/rules"
+	"multi-tenant-service/package/structs"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const streamBatchSize = 100
+
+// StreamMessages emits the tenant's stored messages that come after
+// req.Cursor, then keeps emitting new ones until ctx is done. Errors about
+// the request are returned before anything is emitted; the first event is
+// always a heartbeat so the caller can open the stream.
+func (mu *MessageUsecase) StreamMessages(ctx context.Context, req structs.RequestStreamMessages, emit func(structs.StreamEvent) error) error {
+	tenant, err := mu.repoTenant.GetTenant(ctx, req.TenantID.String())
+	if err != nil {
+		return err
+	}
+	if tenant.ID == uuid.Nil || tenant.Status == structs.TenantStatusDeleted {
+		return fmt.Errorf("tenant not found")
+	}
+
+	var filter *rules.Expr
+	if req.Filter != "" {
+		if filter, err = rules.Compile(req.Filter); err != nil {
+			return fmt.Errorf("invalid filter: %v", err)
+		}
+	}
+
+	// Subscribe before the first query so nothing stored in between is missed
+	wake, unsubscribe := mu.notifier.Subscribe(req.TenantID.String())
+	defer unsubscribe()
+
+	var cursor structs.MessageCursor
+	if req.Cursor != "" {
+		if cursor, err = structs.ParseMessageCursor(req.Cursor); err != nil {
+			return err
+		}
+	} else if cursor, err = mu.repository.GetLatestMessageCursor(ctx, req.TenantID); err != nil {
+		return err
+	}
+
+	if err := emit(structs.StreamEvent{}); err != nil {
+		return err
+	}
+
+	heartbeat := time.NewTicker(mu.cfg.Stream.HeartbeatInterval)
+	defer heartbeat.Stop()
+
+	tail := repository.NewMessageTail(mu.repository, req.TenantID, cursor)
+	for {
+		if err := emitTail(ctx, tail, filter, emit); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-wake:
+		case <-heartbeat.C:
+			// Also re-polls, in case a notification from another replica was lost
+			if err := emit(structs.StreamEvent{}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// emitTail emits every message the tail reads until it catches up. Event ids
+// are the furthest position emitted so far, so a message that committed late
+// does not move a resuming client back.
+func emitTail(ctx context.Context, tail *repository.MessageTail, filter *rules.Expr, emit func(structs.StreamEvent) error) error {
+	cursor := tail.Cursor()
+	for {
+		messages, caughtUp, err := tail.Next(ctx, streamBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for i := range messages {
+			msg := messages[i]
+			if position := (structs.MessageCursor{CreatedAt: msg.CreatedAt, ID: msg.ID}); cursor.Before(position) {
+				cursor = position
+			}
+			if filter != nil && !filter.Match(map[string]interface{}{
+				"payload": msg.Payload,
+				"headers": map[string]interface{}{},
+			}) {
+				continue
+			}
+			if err := emit(structs.StreamEvent{ID: cursor.String(), Message: &msg}); err != nil {
+				return err
+			}
+		}
+
+		if caughtUp {
+			return nil
+		}
+	}
+}
@@ -2,33 +2,65 @@ package usecase
 
 import (
 	"context"
+	"io"
 	"multi-tenant-service/internal/message/repository"
 	repoTenant "multi-tenant-service/internal/tenant/repository"
+	"multi-tenant-service/package/auth"
+	"multi-tenant-service/package/config"
+	"multi-tenant-service/package/notifier"
+	"sync"
 
-	rabbitmq "multi-tenant-service/package/rabbit-mq"
+	"multi-tenant-service/package/broker"
 	"multi-tenant-service/package/structs"
+
+	"github.com/google/uuid"
 )
 
 type MessageUsecase struct {
 	repository repository.IMessageRepository
 	repoTenant repoTenant.ITenantRepository
-	mqClient *rabbitmq.Client
+	mq         broker.Broker
+	cfg      *config.Config
+	notifier notifier.Notifier
+
+	// replies is the callback queue of request/reply, declared on first use
+	replyMu sync.Mutex
+	replies *replyQueue
+
+	// rules caches the compiled publish-time rules per tenant
+	rulesMu sync.Mutex
+	rules   map[uuid.UUID]compiledRules
 }
 
 type IMessageUsecase interface {
 	GetMessages(ctx context.Context, req structs.RequestGetMessage) (*structs.MessageResponse, error)
 	PublishMessage(ctx context.Context, req structs.CreateMessageRequest) error
+	StreamMessages(ctx context.Context, req structs.RequestStreamMessages, emit func(structs.StreamEvent) error) error
+	ExportMessages(ctx context.Context, req structs.ExportMessagesRequest, emit func(structs.Message) error) error
+	ImportMessages(ctx context.Context, tenantID uuid.UUID, body io.Reader) (*structs.ImportMessagesResult, error)
+	OpenGateway(ctx context.Context, claims auth.Claims) *GatewaySession
+	PullMessages(ctx context.Context, tenantID uuid.UUID, req structs.PullRequest) ([]structs.PulledMessage, error)
+	AckMessages(ctx context.Context, tenantID uuid.UUID, req structs.ReceiptRequest) (*structs.ReceiptResult, error)
+	NackMessages(ctx context.Context, tenantID uuid.UUID, req structs.ReceiptRequest) (*structs.ReceiptResult, error)
+	ExtendMessages(ctx context.Context, tenantID uuid.UUID, req structs.ReceiptRequest) (*structs.ReceiptResult, error)
+	RequestMessage(ctx context.Context, req structs.MessageRequest) (*structs.MessageReply, error)
+	Close() error
 }
 
 
 
 func NewMessageUsecase(messgeRepo repository.IMessageRepository, 
 	repoTenant repoTenant.ITenantRepository,
-	mqClient *rabbitmq.Client) IMessageUsecase {
+	mq broker.Broker,
+	cfg *config.Config,
+	n notifier.Notifier) IMessageUsecase {
 	return &MessageUsecase{
 		repository: messgeRepo,
 		repoTenant: repoTenant,
-		mqClient: mqClient,
+		mq:         mq,
+		cfg:      cfg,
+		notifier: n,
+		rules:    make(map[uuid.UUID]compiledRules),
 	}
 	
 }
\ No newline at end of file
@@ -0,0 +1,434 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"multi-tenant-service/internal/tenant/usecase"
+	"multi-tenant-service/package/auth"
+	pb "multi-tenant-service/package/pb/multitenant/v1"
+	"multi-tenant-service/package/selector"
+	"multi-tenant-service/package/structs"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// TenantGRPCServer serves TenantService on top of the tenant usecase.
+type TenantGRPCServer struct {
+	pb.UnimplementedTenantServiceServer
+	tenantUsecase usecase.ITenantUsecase
+}
+
+func NewTenantGRPCServer(s *grpc.Server, tenantUsecase usecase.ITenantUsecase) {
+	pb.RegisterTenantServiceServer(s, &TenantGRPCServer{tenantUsecase: tenantUsecase})
+}
+
+func (s *TenantGRPCServer) CreateTenant(ctx context.Context, req *pb.CreateTenantRequest) (*pb.Tenant, error) {
+	if err := auth.Authorize(ctx, "*"); err != nil {
+		return nil, grpcAuthError(err)
+	}
+	create := structs.CreateTenantRequest{
+		Name:              req.GetName(),
+		ConcurrencyConfig: int(req.GetConcurrencyConfig()),
+		Labels:            req.GetLabels(),
+	}
+	if req.GetSettings() != nil {
+		if err := fromStruct(req.GetSettings(), &create.Settings); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+	}
+	tenant, err := s.tenantUsecase.CreateTenant(ctx, create)
+	if err != nil {
+		return nil, tenantGRPCError(err)
+	}
+	return tenantToPB(tenant)
+}
+
+func (s *TenantGRPCServer) GetTenant(ctx context.Context, req *pb.TenantRef) (*pb.Tenant, error) {
+	if err := authorizeTenant(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+	tenant, err := s.tenantUsecase.GetTenant(ctx, req.GetId())
+	if err != nil {
+		return nil, tenantGRPCError(err)
+	}
+	return tenantToPB(tenant)
+}
+
+func (s *TenantGRPCServer) ListTenants(ctx context.Context, req *pb.ListTenantsRequest) (*pb.ListTenantsResponse, error) {
+	if err := auth.Authorize(ctx, "*"); err != nil {
+		return nil, grpcAuthError(err)
+	}
+	sel, err := selector.Parse(req.GetSelector())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	tenants, err := s.tenantUsecase.ListTenant(ctx, structs.RequestListTenant{Selector: sel, Status: req.GetStatus()})
+	if err != nil {
+		return nil, tenantGRPCError(err)
+	}
+	resp := &pb.ListTenantsResponse{Tenants: make([]*pb.Tenant, 0, len(tenants))}
+	for i := range tenants {
+		tenant, err := tenantToPB(&tenants[i])
+		if err != nil {
+			return nil, err
+		}
+		resp.Tenants = append(resp.Tenants, tenant)
+	}
+	return resp, nil
+}
+
+func (s *TenantGRPCServer) UpdateTenant(ctx context.Context, req *pb.UpdateTenantRequest) (*pb.Tenant, error) {
+	if err := authorizeTenant(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+	var update structs.UpdateTenantRequest
+	if req.Name != nil {
+		update.Name = req.Name
+	}
+	if req.GetLabels() != nil {
+		labels := structs.Labels(req.GetLabels().GetValues())
+		update.Labels = &labels
+	}
+	if req.GetSettings() != nil {
+		var settings structs.TenantSettings
+		if err := fromStruct(req.GetSettings(), &settings); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		update.Settings = &settings
+	}
+	var ifMatch *time.Time
+	if req.GetIfMatch() != nil {
+		t := req.GetIfMatch().AsTime()
+		ifMatch = &t
+	}
+
+	tenant, err := s.tenantUsecase.UpdateTenant(ctx, req.GetId(), update, ifMatch)
+	if err != nil {
+		return nil, tenantGRPCError(err)
+	}
+	return tenantToPB(tenant)
+}
+
+func (s *TenantGRPCServer) DeleteTenant(ctx context.Context, req *pb.TenantRef) (*pb.Tenant, error) {
+	if err := authorizeTenant(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+	tenant, err := s.tenantUsecase.DeleteTenant(ctx, req.GetId())
+	if err != nil {
+		return nil, tenantGRPCError(err)
+	}
+	return tenantToPB(tenant)
+}
+
+func (s *TenantGRPCServer) RestoreTenant(ctx context.Context, req *pb.TenantRef) (*pb.Tenant, error) {
+	if err := authorizeTenant(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+	tenant, err := s.tenantUsecase.RestoreTenant(ctx, req.GetId())
+	if err != nil {
+		return nil, tenantGRPCError(err)
+	}
+	return tenantToPB(tenant)
+}
+
+func (s *TenantGRPCServer) GetDeletionJob(ctx context.Context, req *pb.TenantRef) (*pb.DeletionJob, error) {
+	if err := authorizeTenant(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+	job, err := s.tenantUsecase.GetDeletionJob(ctx, req.GetId())
+	if err != nil {
+		return nil, tenantGRPCError(err)
+	}
+	resp := &pb.DeletionJob{
+		TenantId:  job.TenantID.String(),
+		Step:      job.Step,
+		Status:    job.Status,
+		Attempts:  int32(job.Attempts),
+		CreatedAt: timestamppb.New(job.CreatedAt),
+		UpdatedAt: timestamppb.New(job.UpdatedAt),
+	}
+	if job.LastError != nil {
+		resp.LastError = *job.LastError
+	}
+	return resp, nil
+}
+
+func (s *TenantGRPCServer) SuspendTenant(ctx context.Context, req *pb.TenantRef) (*emptypb.Empty, error) {
+	return s.tenantAction(ctx, req.GetId(), s.tenantUsecase.SuspendTenant)
+}
+
+func (s *TenantGRPCServer) ResumeTenant(ctx context.Context, req *pb.TenantRef) (*emptypb.Empty, error) {
+	return s.tenantAction(ctx, req.GetId(), s.tenantUsecase.ResumeTenant)
+}
+
+func (s *TenantGRPCServer) UpdateConcurrency(ctx context.Context, req *pb.UpdateConcurrencyRequest) (*emptypb.Empty, error) {
+	return s.tenantAction(ctx, req.GetId(), func(ctx context.Context, tenantID string) error {
+		return s.tenantUsecase.UpdateTenantConcurrency(ctx, tenantID, int(req.GetWorkers()))
+	})
+}
+
+func (s *TenantGRPCServer) RestartConsumer(ctx context.Context, req *pb.TenantRef) (*emptypb.Empty, error) {
+	return s.tenantAction(ctx, req.GetId(), s.tenantUsecase.RestartConsumer)
+}
+
+func (s *TenantGRPCServer) PauseConsumer(ctx context.Context, req *pb.TenantRef) (*emptypb.Empty, error) {
+	return s.tenantAction(ctx, req.GetId(), s.tenantUsecase.PauseConsumer)
+}
+
+func (s *TenantGRPCServer) ResumeConsumer(ctx context.Context, req *pb.TenantRef) (*emptypb.Empty, error) {
+	return s.tenantAction(ctx, req.GetId(), s.tenantUsecase.ResumeConsumer)
+}
+
+func (s *TenantGRPCServer) tenantAction(ctx context.Context, tenantID string, action func(context.Context, string) error) (*emptypb.Empty, error) {
+	if err := authorizeTenant(ctx, tenantID); err != nil {
+		return nil, err
+	}
+	if err := action(ctx, tenantID); err != nil {
+		return nil, tenantGRPCError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *TenantGRPCServer) BulkUpdateConcurrency(ctx context.Context, req *pb.BulkRequest) (*pb.BulkResponse, error) {
+	return s.bulk(ctx, req, func(ctx context.Context, list structs.RequestListTenant) ([]structs.BulkTenantResult, error) {
+		return s.tenantUsecase.BulkUpdateConcurrency(ctx, list, int(req.GetWorkers()))
+	})
+}
+
+func (s *TenantGRPCServer) BulkSuspend(ctx context.Context, req *pb.BulkRequest) (*pb.BulkResponse, error) {
+	return s.bulk(ctx, req, s.tenantUsecase.BulkSuspend)
+}
+
+func (s *TenantGRPCServer) BulkResume(ctx context.Context, req *pb.BulkRequest) (*pb.BulkResponse, error) {
+	return s.bulk(ctx, req, s.tenantUsecase.BulkResume)
+}
+
+func (s *TenantGRPCServer) bulk(ctx context.Context, req *pb.BulkRequest, run func(context.Context, structs.RequestListTenant) ([]structs.BulkTenantResult, error)) (*pb.BulkResponse, error) {
+	if err := auth.Authorize(ctx, "*"); err != nil {
+		return nil, grpcAuthError(err)
+	}
+	sel, err := selector.Parse(req.GetSelector())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	results, err := run(ctx, structs.RequestListTenant{Selector: sel})
+	if err != nil {
+		return nil, tenantGRPCError(err)
+	}
+	resp := &pb.BulkResponse{Results: make([]*pb.BulkResult, 0, len(results))}
+	for _, result := range results {
+		resp.Results = append(resp.Results, &pb.BulkResult{
+			TenantId: result.TenantID,
+			Success:  result.Success,
+			Error:    result.Error,
+		})
+	}
+	return resp, nil
+}
+
+func (s *TenantGRPCServer) DryRunRules(ctx context.Context, req *pb.DryRunRulesRequest) (*pb.DryRunRulesResponse, error) {
+	if err := authorizeTenant(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+	dryRun := structs.RulesDryRunRequest{
+		Payload: req.GetPayload().AsMap(),
+		Headers: req.GetHeaders().AsMap(),
+	}
+	if req.GetRules() != nil {
+		var defs []structs.Rule
+		if err := fromStruct(req.GetRules(), &defs); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		dryRun.Rules = &defs
+	}
+
+	result, err := s.tenantUsecase.DryRunRules(ctx, req.GetId(), dryRun)
+	if err != nil {
+		return nil, tenantGRPCError(err)
+	}
+	resp := &pb.DryRunRulesResponse{Dropped: result.Dropped, Routes: result.Routes, Matched: result.Matched}
+	if resp.Payload, err = toStruct(result.Payload); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if resp.Headers, err = toStruct(result.Headers); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	return resp, nil
+}
+
+func (s *TenantGRPCServer) GetConsumerStatus(ctx context.Context, req *pb.TenantRef) (*pb.ConsumerStatus, error) {
+	if err := authorizeTenant(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+	st, err := s.tenantUsecase.GetConsumerStatus(ctx, req.GetId())
+	if err != nil {
+		return nil, tenantGRPCError(err)
+	}
+	resp := &pb.ConsumerStatus{
+		TenantId:       st.TenantID.String(),
+		State:          st.State,
+		Workers:        int32(st.Workers),
+		Prefetch:       int32(st.Prefetch),
+		InFlight:       int32(st.InFlight),
+		Processed:      st.Processed,
+		Failed:         st.Failed,
+		LastError:      st.LastError,
+		QueueDepth:     int32(st.QueueDepth),
+		QueueConsumers: int32(st.QueueConsumers),
+	}
+	if st.LastDeliveryAt != nil {
+		resp.LastDeliveryAt = timestamppb.New(*st.LastDeliveryAt)
+	}
+	if st.LastErrorAt != nil {
+		resp.LastErrorAt = timestamppb.New(*st.LastErrorAt)
+	}
+	return resp, nil
+}
+
+func (s *TenantGRPCServer) ListScalingEvents(ctx context.Context, req *pb.ListScalingEventsRequest) (*pb.ListScalingEventsResponse, error) {
+	if err := authorizeTenant(ctx, req.GetId()); err != nil {
+		return nil, err
+	}
+	limit := 50
+	if req.GetLimit() > 0 {
+		limit = min(int(req.GetLimit()), 500)
+	}
+	events, err := s.tenantUsecase.ListScalingEvents(ctx, req.GetId(), limit)
+	if err != nil {
+		return nil, tenantGRPCError(err)
+	}
+	resp := &pb.ListScalingEventsResponse{Events: make([]*pb.ScalingEvent, 0, len(events))}
+	for _, event := range events {
+		resp.Events = append(resp.Events, &pb.ScalingEvent{
+			Id:          event.ID,
+			TenantId:    event.TenantID.String(),
+			FromWorkers: int32(event.FromWorkers),
+			ToWorkers:   int32(event.ToWorkers),
+			QueueDepth:  int32(event.QueueDepth),
+			Utilization: event.Utilization,
+			Reason:      event.Reason,
+			CreatedAt:   timestamppb.New(event.CreatedAt),
+		})
+	}
+	return resp, nil
+}
+
+func (s *TenantGRPCServer) ListAssignments(ctx context.Context, _ *emptypb.Empty) (*pb.Assignments, error) {
+	if err := auth.Authorize(ctx, "*"); err != nil {
+		return nil, grpcAuthError(err)
+	}
+	assignment, err := s.tenantUsecase.ListAssignments(ctx)
+	if err != nil {
+		return nil, tenantGRPCError(err)
+	}
+	resp := &pb.Assignments{}
+	for _, replica := range assignment.Replicas {
+		resp.Replicas = append(resp.Replicas, &pb.Replica{
+			Id:          replica.ID,
+			StartedAt:   timestamppb.New(replica.StartedAt),
+			HeartbeatAt: timestamppb.New(replica.HeartbeatAt),
+		})
+	}
+	for _, lease := range assignment.Leases {
+		resp.Leases = append(resp.Leases, &pb.Lease{
+			TenantId:   lease.TenantID.String(),
+			Owner:      lease.Owner,
+			AcquiredAt: timestamppb.New(lease.AcquiredAt),
+			ExpiresAt:  timestamppb.New(lease.ExpiresAt),
+		})
+	}
+	return resp, nil
+}
+
+func authorizeTenant(ctx context.Context, tenantID string) error {
+	if _, err := uuid.Parse(tenantID); err != nil {
+		return status.Error(codes.InvalidArgument, "Invalid tenant ID")
+	}
+	if err := auth.Authorize(ctx, tenantID); err != nil {
+		return grpcAuthError(err)
+	}
+	return nil
+}
+
+func grpcAuthError(err error) error {
+	if err == auth.ErrForbidden {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return status.Error(codes.Unauthenticated, err.Error())
+}
+
+// tenantGRPCError maps usecase errors to status codes the way
+// tenantErrorResponse maps them to HTTP statuses.
+func tenantGRPCError(err error) error {
+	if strings.HasPrefix(err.Error(), "invalid pipeline: ") || strings.HasPrefix(err.Error(), "invalid rules: ") {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	switch err.Error() {
+	case "tenant not found", "deletion job not found":
+		return status.Error(codes.NotFound, err.Error())
+	case "name must not be empty", "settings must not be negative", "workers must be at least 1",
+		"autoscale bounds are invalid", "delivery must be push or pull",
+		"partition interval must be daily or monthly", "retention action must be drop or detach":
+		return status.Error(codes.InvalidArgument, err.Error())
+	case "tenant was modified":
+		return status.Error(codes.Aborted, err.Error())
+	case "tenant is not active", "tenant is not suspended",
+		"tenant is already deleted", "tenant is not deleted",
+		"consumer is not running", "consumer is not paused":
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func tenantToPB(tenant *structs.Tenant) (*pb.Tenant, error) {
+	settings, err := toStruct(tenant.Settings)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	resp := &pb.Tenant{
+		Id:                tenant.ID.String(),
+		Name:              tenant.Name,
+		ConcurrencyConfig: int32(tenant.ConcurrencyConfig),
+		Status:            tenant.Status,
+		Labels:            tenant.Labels,
+		Settings:          settings,
+		CreatedAt:         timestamppb.New(tenant.CreatedAt),
+		UpdatedAt:         timestamppb.New(tenant.UpdatedAt),
+	}
+	if tenant.DeletedAt != nil {
+		resp.DeletedAt = timestamppb.New(*tenant.DeletedAt)
+	}
+	return resp, nil
+}
+
+// toStruct converts a JSON-encodable value to a protobuf Struct through its
+// JSON form, so the field names match the REST API.
+func toStruct(v interface{}) (*structpb.Struct, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return structpb.NewStruct(fields)
+}
+
+// fromStruct decodes a protobuf Struct or ListValue into v through JSON.
+func fromStruct(src interface{ MarshalJSON() ([]byte, error) }, v interface{}) error {
+	data, err := src.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
@@ -1,10 +1,16 @@
 package delivery
 
 import (
+	"fmt"
 	"multi-tenant-service/internal/tenant/usecase"
+	"multi-tenant-service/package/auth"
 	"multi-tenant-service/package/response"
+	"multi-tenant-service/package/selector"
 	"multi-tenant-service/package/structs"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -28,6 +34,9 @@ type TenantHTTPHandler struct {
 // @Router /tenants [post]
 func (h *TenantHTTPHandler) CreateTenant(c echo.Context) error {
 	ctx := c.Request().Context()
+	if err := auth.AuthorizeOptional(ctx, "*"); err != nil {
+		return response.JSONResponse(c, http.StatusForbidden, false, err.Error(), nil)
+	}
 	var req structs.CreateTenantRequest
 	if err := c.Bind(&req); err != nil {
 		return response.JSONResponse(c, http.StatusBadRequest, false, err.Error(), nil)
@@ -35,19 +44,167 @@ func (h *TenantHTTPHandler) CreateTenant(c echo.Context) error {
 
 	tenant, err := h.tenantUsecase.CreateTenant(ctx, req)
 	if err != nil {
-		return response.JSONResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+		return tenantErrorResponse(c, err)
 	}
 	return response.JSONResponse(c, http.StatusCreated, true, "Tenant created successfully", tenant)
 }
 
 
+// ListTenants godoc
+// @Summary List tenants
+// @Description List tenants, optionally filtered by a label selector such as plan=gold,region!=eu
+// @Tags tenants
+// @Produce json
+// @Param selector query string false "Label selector"
+// @Param status query string false "Tenant status"
+// @Success 200 {array} structs.Tenant
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tenants [get]
+func (h *TenantHTTPHandler) ListTenants(c echo.Context) error {
+	ctx := c.Request().Context()
+	if err := auth.AuthorizeOptional(ctx, "*"); err != nil {
+		return response.JSONResponse(c, http.StatusForbidden, false, err.Error(), nil)
+	}
+	sel, err := selector.Parse(c.QueryParam("selector"))
+	if err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, err.Error(), nil)
+	}
+
+	tenants, err := h.tenantUsecase.ListTenant(ctx, structs.RequestListTenant{
+		Selector: sel,
+		Status:   c.QueryParam("status"),
+	})
+	if err != nil {
+		return response.JSONResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+	}
+	return response.JSONSuccess(c, tenants, "Tenants retrieved successfully")
+}
+
+// BulkTenants godoc
+// @Summary Apply an operation to many tenants
+// @Description Apply concurrency, suspend or resume to every tenant matched by the selector
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Param action path string true "concurrency, suspend or resume"
+// @Param request body structs.BulkTenantRequest true "Selector and parameters"
+// @Success 200 {array} structs.BulkTenantResult
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tenants/bulk/{action} [post]
+func (h *TenantHTTPHandler) BulkTenants(c echo.Context) error {
+	ctx := c.Request().Context()
+	if err := auth.AuthorizeOptional(ctx, "*"); err != nil {
+		return response.JSONResponse(c, http.StatusForbidden, false, err.Error(), nil)
+	}
+	var req structs.BulkTenantRequest
+	if err := c.Bind(&req); err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, err.Error(), nil)
+	}
+
+	sel, err := selector.Parse(req.Selector)
+	if err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, err.Error(), nil)
+	}
+	if len(sel) == 0 {
+		return response.JSONResponse(c, http.StatusBadRequest, false, "selector is required", nil)
+	}
+	listReq := structs.RequestListTenant{Selector: sel}
+
+	var results []structs.BulkTenantResult
+	switch c.Param("action") {
+	case "concurrency":
+		results, err = h.tenantUsecase.BulkUpdateConcurrency(ctx, listReq, req.Workers)
+	case "suspend":
+		results, err = h.tenantUsecase.BulkSuspend(ctx, listReq)
+	case "resume":
+		results, err = h.tenantUsecase.BulkResume(ctx, listReq)
+	default:
+		return response.JSONResponse(c, http.StatusBadRequest, false, "Unknown bulk action", nil)
+	}
+	if err != nil {
+		return tenantErrorResponse(c, err)
+	}
+	return response.JSONSuccess(c, results, "Bulk operation completed")
+}
+
+// GetTenant godoc
+// @Summary Get tenant
+// @Description Get a tenant. The ETag header can be sent back as If-Match when updating it.
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} structs.Tenant
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tenants/{id} [get]
+func (h *TenantHTTPHandler) GetTenant(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("id")
+	if _, err := uuid.Parse(tenantID); err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, "Invalid tenant ID", nil)
+	}
+
+	tenant, err := h.tenantUsecase.GetTenant(ctx, tenantID)
+	if err != nil {
+		return tenantErrorResponse(c, err)
+	}
+	c.Response().Header().Set("ETag", tenantETag(tenant))
+	return response.JSONSuccess(c, tenant, "Tenant retrieved successfully")
+}
+
+// UpdateTenant godoc
+// @Summary Update tenant
+// @Description Update name, labels or settings of a tenant. Settings apply to the running consumer immediately.
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param If-Match header string false "ETag returned by a previous read"
+// @Param tenant body structs.UpdateTenantRequest true "Fields to update"
+// @Success 200 {object} structs.Tenant
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 412 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tenants/{id} [patch]
+func (h *TenantHTTPHandler) UpdateTenant(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("id")
+	if _, err := uuid.Parse(tenantID); err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, "Invalid tenant ID", nil)
+	}
+
+	ifMatch, err := parseIfMatch(c.Request().Header.Get("If-Match"))
+	if err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, "Invalid If-Match header", nil)
+	}
+
+	var req structs.UpdateTenantRequest
+	if err := c.Bind(&req); err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, err.Error(), nil)
+	}
+
+	tenant, err := h.tenantUsecase.UpdateTenant(ctx, tenantID, req, ifMatch)
+	if err != nil {
+		return tenantErrorResponse(c, err)
+	}
+	c.Response().Header().Set("ETag", tenantETag(tenant))
+	return response.JSONResponse(c, http.StatusOK, true, "Tenant updated successfully", tenant)
+}
+
 // DeleteTenant godoc
 // @Summary Delete tenant
-// @Description Delete tenant and stop its consumer
+// @Description Stop the tenant consumer and detach its partition. The tenant can be restored until the grace period expires.
 // @Tags tenants
+// @Produce json
 // @Param id path string true "Tenant ID"
-// @Success 204
+// @Success 200 {object} structs.Tenant
+// @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /tenants/{id} [delete]
 func (h *TenantHTTPHandler) DeleteTenant(c echo.Context) error {
@@ -57,21 +214,73 @@ func (h *TenantHTTPHandler) DeleteTenant(c echo.Context) error {
 		return response.JSONResponse(c, http.StatusBadRequest, false, "Invalid tenant ID", nil)
 	}
 
-	if err := h.tenantUsecase.DeleteTenant(ctx, tenantID); err != nil {
-		return response.JSONResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+	tenant, err := h.tenantUsecase.DeleteTenant(ctx, tenantID)
+	if err != nil {
+		return tenantErrorResponse(c, err)
+	}
+	return response.JSONResponse(c, http.StatusOK, true, "Tenant deleted successfully", tenant)
+}
+
+// RestoreTenant godoc
+// @Summary Restore tenant
+// @Description Reattach the partition of a deleted tenant and restart its consumer
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} structs.Tenant
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tenants/{id}/restore [post]
+func (h *TenantHTTPHandler) RestoreTenant(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("id")
+	if _, err := uuid.Parse(tenantID); err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, "Invalid tenant ID", nil)
+	}
+
+	tenant, err := h.tenantUsecase.RestoreTenant(ctx, tenantID)
+	if err != nil {
+		return tenantErrorResponse(c, err)
 	}
-	return response.JSONResponse(c, http.StatusNoContent, true, "Tenant deleted successfully", nil)
+	return response.JSONResponse(c, http.StatusOK, true, "Tenant restored successfully", tenant)
+}
+
+// GetDeletionJob godoc
+// @Summary Get tenant deletion progress
+// @Description Get the state of the deletion job of a tenant
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} structs.TenantDeletionJob
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tenants/{id}/deletion [get]
+func (h *TenantHTTPHandler) GetDeletionJob(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("id")
+	if _, err := uuid.Parse(tenantID); err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, "Invalid tenant ID", nil)
+	}
+
+	job, err := h.tenantUsecase.GetDeletionJob(ctx, tenantID)
+	if err != nil {
+		return tenantErrorResponse(c, err)
+	}
+	return response.JSONSuccess(c, job, "Deletion job retrieved successfully")
 }
 
 // UpdateConcurrency godoc
 // @Summary Update tenant concurrency
-// @Description Update the number of concurrent workers for a tenant
+// @Description Update the number of concurrent workers for a tenant. The count is stored right away and the consumer that owns the tenant applies it in the background.
 // @Tags tenants
 // @Accept json
 // @Produce json
 // @Param id path string true "Tenant ID"
 // @Param config body structs.UpdateConcurrencyRequest true "Concurrency config"
-// @Success 200 {object} structs.Response
+// @Success 202 {object} structs.Response
 // @Failure 400 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -80,7 +289,7 @@ func (h *TenantHTTPHandler) UpdateConcurrency(c echo.Context) error {
 	ctx := c.Request().Context()
 	tenantID := c.Param("id")
 	if _, err := uuid.Parse(tenantID); err != nil {
-		response.JSONResponse(c, http.StatusBadRequest, false, "Invalid tenant ID", nil)
+		return response.JSONResponse(c, http.StatusBadRequest, false, "Invalid tenant ID", nil)
 	}
 
 	var req structs.UpdateConcurrencyRequest
@@ -95,15 +304,299 @@ func (h *TenantHTTPHandler) UpdateConcurrency(c echo.Context) error {
 		return response.JSONResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
 	}
 
-	return response.JSONResponse(c, http.StatusOK, true, "Concurrency updated successfully", nil)
+	return response.JSONResponse(c, http.StatusAccepted, true, "Concurrency update accepted", nil)
 }
 
+// GetConsumer godoc
+// @Summary Get tenant consumer status
+// @Description Show the state, owner, workers, in-flight messages, counters, last error and queue depth of the tenant consumer, as last reported by the replica that owns the tenant
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} structs.ConsumerStatus
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tenants/{id}/consumer [get]
+func (h *TenantHTTPHandler) GetConsumer(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("id")
+	if _, err := uuid.Parse(tenantID); err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, "Invalid tenant ID", nil)
+	}
+
+	status, err := h.tenantUsecase.GetConsumerStatus(ctx, tenantID)
+	if err != nil {
+		return tenantErrorResponse(c, err)
+	}
+	return response.JSONSuccess(c, status, "Consumer status retrieved successfully")
+}
+
+// ConsumerAction godoc
+// @Summary Restart, pause or resume the tenant consumer
+// @Description restart drains and restarts the consumer; pause stops consuming while publishing continues; resume undoes pause. Pauses are stored on the tenant, and a replica other than the owner applies the action on its next reconcile pass
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param action path string true "restart, pause or resume"
+// @Success 200 {object} structs.Response
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tenants/{id}/consumer/{action} [post]
+func (h *TenantHTTPHandler) ConsumerAction(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("id")
+	if _, err := uuid.Parse(tenantID); err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, "Invalid tenant ID", nil)
+	}
+
+	var err error
+	action := c.Param("action")
+	switch action {
+	case "restart":
+		err = h.tenantUsecase.RestartConsumer(ctx, tenantID)
+	case "pause":
+		err = h.tenantUsecase.PauseConsumer(ctx, tenantID)
+	case "resume":
+		err = h.tenantUsecase.ResumeConsumer(ctx, tenantID)
+	default:
+		return response.JSONResponse(c, http.StatusBadRequest, false, "Unknown consumer action", nil)
+	}
+	if err != nil {
+		return tenantErrorResponse(c, err)
+	}
+	return response.JSONResponse(c, http.StatusOK, true, fmt.Sprintf("Consumer %s completed successfully", action), nil)
+}
+
+// DryRunRules godoc
+// @Summary Dry-run tenant rules
+// @Description Show what the tenant rules, or the rules in the request, do to a sample payload and headers
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param sample body structs.RulesDryRunRequest true "Sample message"
+// @Success 200 {object} rules.Result
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tenants/{id}/rules/dry-run [post]
+func (h *TenantHTTPHandler) DryRunRules(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("id")
+	if _, err := uuid.Parse(tenantID); err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, "Invalid tenant ID", nil)
+	}
+
+	var req structs.RulesDryRunRequest
+	if err := c.Bind(&req); err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, err.Error(), nil)
+	}
+
+	result, err := h.tenantUsecase.DryRunRules(ctx, tenantID, req)
+	if err != nil {
+		return tenantErrorResponse(c, err)
+	}
+	return response.JSONSuccess(c, result, "Rules evaluated successfully")
+}
+
+// ListScalingEvents godoc
+// @Summary List autoscaler decisions
+// @Description List the worker count changes the autoscaler made for a tenant, newest first
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param limit query int false "Maximum number of events (default 50, max 500)"
+// @Success 200 {array} structs.TenantScalingEvent
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tenants/{id}/scaling-events [get]
+func (h *TenantHTTPHandler) ListScalingEvents(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("id")
+	if _, err := uuid.Parse(tenantID); err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, "Invalid tenant ID", nil)
+	}
+
+	limit := 50
+	if raw := c.QueryParam("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 {
+			return response.JSONResponse(c, http.StatusBadRequest, false, "Invalid limit", nil)
+		}
+		limit = min(parsed, 500)
+	}
+
+	events, err := h.tenantUsecase.ListScalingEvents(ctx, tenantID, limit)
+	if err != nil {
+		return tenantErrorResponse(c, err)
+	}
+	return response.JSONSuccess(c, events, "Scaling events retrieved successfully")
+}
+
+// ListMessagePartitions godoc
+// @Summary List message partitions
+// @Description List the time sub-partitions holding a tenant's messages, oldest first
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {array} structs.MessagePartition
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tenants/{id}/partitions [get]
+func (h *TenantHTTPHandler) ListMessagePartitions(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("id")
+	if _, err := uuid.Parse(tenantID); err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, "Invalid tenant ID", nil)
+	}
+
+	partitions, err := h.tenantUsecase.ListMessagePartitions(ctx, tenantID)
+	if err != nil {
+		return tenantErrorResponse(c, err)
+	}
+	return response.JSONSuccess(c, partitions, "Partitions retrieved successfully")
+}
+
+// SuspendTenant godoc
+// @Summary Suspend tenant
+// @Description Stop the tenant consumer while keeping its queue
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} structs.Response
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tenants/{id}/suspend [post]
+func (h *TenantHTTPHandler) SuspendTenant(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("id")
+	if _, err := uuid.Parse(tenantID); err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, "Invalid tenant ID", nil)
+	}
+
+	if err := h.tenantUsecase.SuspendTenant(ctx, tenantID); err != nil {
+		return tenantErrorResponse(c, err)
+	}
+	return response.JSONResponse(c, http.StatusOK, true, "Tenant suspended successfully", nil)
+}
+
+// ResumeTenant godoc
+// @Summary Resume tenant
+// @Description Restart the tenant consumer with the stored concurrency config
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} structs.Response
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tenants/{id}/resume [post]
+func (h *TenantHTTPHandler) ResumeTenant(c echo.Context) error {
+	ctx := c.Request().Context()
+	tenantID := c.Param("id")
+	if _, err := uuid.Parse(tenantID); err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, "Invalid tenant ID", nil)
+	}
+
+	if err := h.tenantUsecase.ResumeTenant(ctx, tenantID); err != nil {
+		return tenantErrorResponse(c, err)
+	}
+	return response.JSONResponse(c, http.StatusOK, true, "Tenant resumed successfully", nil)
+}
+
+// ListAssignments godoc
+// @Summary List tenant assignments
+// @Description Show the live worker replicas and which replica holds the lease of each tenant
+// @Tags admin
+// @Produce json
+// @Success 200 {object} structs.TenantAssignment
+// @Failure 500 {object} map[string]string
+// @Router /admin/assignments [get]
+func (h *TenantHTTPHandler) ListAssignments(c echo.Context) error {
+	ctx := c.Request().Context()
+	if err := auth.AuthorizeOptional(ctx, "*"); err != nil {
+		return response.JSONResponse(c, http.StatusForbidden, false, err.Error(), nil)
+	}
+	assignment, err := h.tenantUsecase.ListAssignments(ctx)
+	if err != nil {
+		return response.JSONResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+	}
+	return response.JSONSuccess(c, assignment, "Assignments retrieved successfully")
+}
+
+// tenantErrorResponse maps usecase errors to HTTP status codes.
+func tenantErrorResponse(c echo.Context, err error) error {
+	if strings.HasPrefix(err.Error(), "invalid pipeline: ") || strings.HasPrefix(err.Error(), "invalid rules: ") {
+		return response.JSONResponse(c, http.StatusBadRequest, false, err.Error(), nil)
+	}
+	switch err.Error() {
+	case "tenant not found", "deletion job not found":
+		return response.JSONResponse(c, http.StatusNotFound, false, err.Error(), nil)
+	case "name must not be empty", "settings must not be negative", "workers must be at least 1",
+		"autoscale bounds are invalid", "delivery must be push or pull",
+		"partition interval must be daily or monthly", "retention action must be drop or detach":
+		return response.JSONResponse(c, http.StatusBadRequest, false, err.Error(), nil)
+	case "tenant was modified":
+		return response.JSONResponse(c, http.StatusPreconditionFailed, false, err.Error(), nil)
+	case "tenant is not active", "tenant is not suspended",
+		"tenant is already deleted", "tenant is not deleted",
+		"consumer is not running", "consumer is not paused":
+		return response.JSONResponse(c, http.StatusConflict, false, err.Error(), nil)
+	}
+	return response.JSONResponse(c, http.StatusInternalServerError, false, err.Error(), nil)
+}
+
+// tenantETag derives a strong ETag from updated_at, which changes on every write.
+func tenantETag(tenant *structs.Tenant) string {
+	return fmt.Sprintf(`"%d"`, tenant.UpdatedAt.UnixMicro())
+}
+
+// parseIfMatch turns an If-Match header into the updated_at it refers to.
+// An empty header or "*" matches any version.
+func parseIfMatch(header string) (*time.Time, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+	micros, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(header, "W/"), `"`), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	updatedAt := time.UnixMicro(micros)
+	return &updatedAt, nil
+}
 
 func NewTenantHTTPHandler(r *echo.Group, tenantUsecase usecase.ITenantUsecase)  {
 	h := &TenantHTTPHandler{
 		tenantUsecase: tenantUsecase,
 	}
 	r.POST("/tenants", h.CreateTenant).Name = "CreateTenant"
+	r.GET("/tenants", h.ListTenants).Name = "ListTenants"
+	r.POST("/tenants/bulk/:action", h.BulkTenants).Name = "BulkTenants"
+	r.GET("/tenants/:id", h.GetTenant).Name = "GetTenant"
+	r.PATCH("/tenants/:id", h.UpdateTenant).Name = "UpdateTenant"
 	r.DELETE("/tenants/:id", h.DeleteTenant).Name = "DeleteTenant"
+	r.POST("/tenants/:id/restore", h.RestoreTenant).Name = "RestoreTenant"
+	r.GET("/tenants/:id/deletion", h.GetDeletionJob).Name = "GetDeletionJob"
+	r.POST("/tenants/:id/replays", h.CreateReplayJob).Name = "CreateReplayJob"
+	r.GET("/tenants/:id/replays", h.ListReplayJobs).Name = "ListReplayJobs"
+	r.GET("/tenants/:id/replays/:job_id", h.GetReplayJob).Name = "GetReplayJob"
+	r.POST("/tenants/:id/replays/:job_id/cancel", h.CancelReplayJob).Name = "CancelReplayJob"
 	r.PUT("/tenants/:id/config/concurrency", h.UpdateConcurrency).Name = "UpdateConcurrency"
+	r.GET("/tenants/:id/consumer", h.GetConsumer).Name = "GetConsumer"
+	r.POST("/tenants/:id/consumer/:action", h.ConsumerAction).Name = "ConsumerAction"
+	r.POST("/tenants/:id/rules/dry-run", h.DryRunRules).Name = "DryRunRules"
+	r.GET("/tenants/:id/scaling-events", h.ListScalingEvents).Name = "ListScalingEvents"
+	r.GET("/tenants/:id/partitions", h.ListMessagePartitions).Name = "ListMessagePartitions"
+	r.POST("/tenants/:id/suspend", h.SuspendTenant).Name = "SuspendTenant"
+	r.POST("/tenants/:id/resume", h.ResumeTenant).Name = "ResumeTenant"
+	r.GET("/admin/assignments", h.ListAssignments).Name = "ListAssignments"
 }
\ No newline at end of file
@@ -0,0 +1,143 @@
+package delivery
+
+import (
+	"multi-tenant-service/package/response"
+	"multi-tenant-service/package/structs"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// CreateReplayJob godoc
+// @Summary Replay stored messages
+// @Description Start a job that reads the tenant's stored messages in a time range and republishes those matching the filter to the tenant queue, a subscription or a webhook, with an x-replayed header
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param request body structs.CreateReplayJobRequest true "Replay job"
+// @Success 202 {object} structs.ReplayJob
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tenants/{id}/replays [post]
+func (h *TenantHTTPHandler) CreateReplayJob(c echo.Context) error {
+	tenantID := c.Param("id")
+	if _, err := uuid.Parse(tenantID); err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, "Invalid tenant ID", nil)
+	}
+
+	var req structs.CreateReplayJobRequest
+	if err := c.Bind(&req); err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, err.Error(), nil)
+	}
+
+	job, err := h.tenantUsecase.CreateReplayJob(c.Request().Context(), tenantID, req)
+	if err != nil {
+		return replayErrorResponse(c, err)
+	}
+	return response.JSONResponse(c, http.StatusAccepted, true, "Replay job created successfully", job)
+}
+
+// ListReplayJobs godoc
+// @Summary List replay jobs
+// @Description List the latest replay jobs of a tenant, newest first
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {array} structs.ReplayJob
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tenants/{id}/replays [get]
+func (h *TenantHTTPHandler) ListReplayJobs(c echo.Context) error {
+	tenantID := c.Param("id")
+	if _, err := uuid.Parse(tenantID); err != nil {
+		return response.JSONResponse(c, http.StatusBadRequest, false, "Invalid tenant ID", nil)
+	}
+
+	jobs, err := h.tenantUsecase.ListReplayJobs(c.Request().Context(), tenantID)
+	if err != nil {
+		return replayErrorResponse(c, err)
+	}
+	return response.JSONSuccess(c, jobs, "Replay jobs retrieved successfully")
+}
+
+// GetReplayJob godoc
+// @Summary Get replay progress
+// @Description Get the status and counters of a replay job
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param job_id path string true "Replay job ID"
+// @Success 200 {object} structs.ReplayJob
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tenants/{id}/replays/{job_id} [get]
+func (h *TenantHTTPHandler) GetReplayJob(c echo.Context) error {
+	tenantID, jobID, ok := replayJobParams(c)
+	if !ok {
+		return response.JSONResponse(c, http.StatusBadRequest, false, "Invalid tenant or replay job ID", nil)
+	}
+
+	job, err := h.tenantUsecase.GetReplayJob(c.Request().Context(), tenantID, jobID)
+	if err != nil {
+		return replayErrorResponse(c, err)
+	}
+	return response.JSONSuccess(c, job, "Replay job retrieved successfully")
+}
+
+// CancelReplayJob godoc
+// @Summary Cancel a replay job
+// @Description Stop a pending or running replay job. Messages already replayed stay published.
+// @Tags tenants
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param job_id path string true "Replay job ID"
+// @Success 200 {object} structs.ReplayJob
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /tenants/{id}/replays/{job_id}/cancel [post]
+func (h *TenantHTTPHandler) CancelReplayJob(c echo.Context) error {
+	tenantID, jobID, ok := replayJobParams(c)
+	if !ok {
+		return response.JSONResponse(c, http.StatusBadRequest, false, "Invalid tenant or replay job ID", nil)
+	}
+
+	job, err := h.tenantUsecase.CancelReplayJob(c.Request().Context(), tenantID, jobID)
+	if err != nil {
+		return replayErrorResponse(c, err)
+	}
+	return response.JSONSuccess(c, job, "Replay job cancelled successfully")
+}
+
+func replayJobParams(c echo.Context) (string, string, bool) {
+	tenantID, jobID := c.Param("id"), c.Param("job_id")
+	if _, err := uuid.Parse(tenantID); err != nil {
+		return "", "", false
+	}
+	if _, err := uuid.Parse(jobID); err != nil {
+		return "", "", false
+	}
+	return tenantID, jobID, true
+}
+
+func replayErrorResponse(c echo.Context, err error) error {
+	msg := err.Error()
+	switch {
+	case msg == "replay job not found":
+		return response.JSONResponse(c, http.StatusNotFound, false, msg, nil)
+	case msg == "replay job already finished", msg == "tenant is being deleted":
+		return response.JSONResponse(c, http.StatusConflict, false, msg, nil)
+	case msg == "from must be before to", strings.Contains(msg, " must be between "),
+		strings.HasPrefix(msg, "target must be "), strings.HasPrefix(msg, "invalid filter: "),
+		strings.HasPrefix(msg, "invalid subscription "), strings.HasPrefix(msg, "invalid webhook: "):
+		return response.JSONResponse(c, http.StatusBadRequest, false, msg, nil)
+	}
+	return tenantErrorResponse(c, err)
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"multi-tenant-service/package/structs"
+	"time"
+)
+
+// restoreBatchSize is how many archived rows are inserted per statement.
+const restoreBatchSize = 500
+
+// ListDetachedMessagePartitions returns the sub-partitions retention
+// detached from a tenant's messages partition, oldest first. Their bounds
+// come from the comment DetachMessagePartition left on them.
+func (r TenantRepository) ListDetachedMessagePartitions(ctx context.Context, tenantID string) ([]structs.MessagePartition, error) {
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT relname, false, %s
+		FROM (
+			SELECT c.relname, obj_description(c.oid, 'pg_class') AS bound
+			FROM pg_class c
+			WHERE c.relkind = 'r' AND pg_table_is_visible(c.oid)
+				AND left(c.relname, length($1) + 1) = $1 || '_'
+				AND NOT EXISTS (SELECT 1 FROM pg_inherits i WHERE i.inhrelid = c.oid)
+		) detached
+		WHERE bound LIKE 'FOR VALUES %%'
+		ORDER BY 4 NULLS FIRST
+	`, fmt.Sprintf(partitionBounds, "bound")), PartitionTable(tenantID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list detached message partitions: %w", err)
+	}
+	return scanMessagePartitions(rows)
+}
+
+// ScanMessagePartition calls fn with every row of a partition table as a
+// JSON object, in created_at order.
+func (r TenantRepository) ScanMessagePartition(ctx context.Context, table string, fn func(row json.RawMessage) error) error {
+	rows, err := r.db.QueryContext(ctx,
+		fmt.Sprintf(`SELECT row_to_json(m)::text FROM %s m ORDER BY created_at, id`, table))
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row []byte
+		if err := rows.Scan(&row); err != nil {
+			return fmt.Errorf("failed to scan row of %s: %w", table, err)
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// RestoreMessagePartition creates table shaped like messages and fills it
+// with the rows next returns until io.EOF, all in one transaction. The
+// table is left detached and marked as restored, so retention ignores it.
+func (r TenantRepository) RestoreMessagePartition(ctx context.Context, table string, next func() (json.RawMessage, error)) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := fmt.Sprintf(`CREATE TABLE %s (LIKE messages INCLUDING DEFAULTS)`, table)
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return 0, fmt.Errorf("failed to create %s: %w", table, err)
+	}
+	query = fmt.Sprintf(`COMMENT ON TABLE %s IS 'restored from archive'`, table)
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return 0, fmt.Errorf("failed to mark %s as restored: %w", table, err)
+	}
+
+	insert := fmt.Sprintf(`INSERT INTO %s SELECT * FROM json_populate_recordset(NULL::%s, $1::json)`, table, table)
+	var restored int64
+	batch := make([]json.RawMessage, 0, restoreBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		data, err := json.Marshal(batch)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, insert, string(data)); err != nil {
+			return fmt.Errorf("failed to insert into %s: %w", table, err)
+		}
+		restored += int64(len(batch))
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		row, err := next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+		batch = append(batch, row)
+		if len(batch) == restoreBatchSize {
+			if err := flush(); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return 0, err
+	}
+	return restored, tx.Commit()
+}
+
+// AttachMessagePartition attaches table to a tenant's messages partition
+// for the messages created from from up to to; a nil from is unbounded.
+func (r TenantRepository) AttachMessagePartition(ctx context.Context, tenantID, table string, from, to *time.Time) error {
+	if to == nil {
+		return fmt.Errorf("partition %s has no upper bound", table)
+	}
+	lower := "MINVALUE"
+	if from != nil {
+		lower = fmt.Sprintf("'%s'", from.UTC().Format(time.RFC3339))
+	}
+	query := fmt.Sprintf(`ALTER TABLE %s ATTACH PARTITION %s FOR VALUES FROM (%s) TO ('%s')`,
+		PartitionTable(tenantID), table, lower, to.UTC().Format(time.RFC3339))
+	if _, err := r.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to attach %s: %w", table, err)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+)
+
+// ArchiveTenantMessages copies the tenant partition into messages_archive.
+// Rows already archived by a previous attempt are skipped.
+func (r TenantRepository) ArchiveTenantMessages(ctx context.Context, tenantID string) (int64, error) {
+	query := fmt.Sprintf(`
+		INSERT INTO messages_archive (id, tenant_id, payload, created_at)
+		SELECT id, tenant_id, payload, created_at FROM %s
+		ON CONFLICT (tenant_id, id) DO NOTHING
+	`, PartitionTable(tenantID))
+
+	res, err := r.db.ExecContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive messages: %w", err)
+	}
+	return res.RowsAffected()
+}
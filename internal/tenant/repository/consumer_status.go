@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"multi-tenant-service/package/structs"
+)
+
+// SetConsumerPaused pauses or resumes the consumer of a tenant. It reports
+// false when the tenant is missing or already in that state.
+func (r TenantRepository) SetConsumerPaused(ctx context.Context, tenantID string, paused bool) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		"UPDATE tenants SET consumer_paused = $1, updated_at = NOW() WHERE id = $2 AND consumer_paused <> $1",
+		paused, tenantID)
+	if err != nil {
+		return false, fmt.Errorf("failed to update consumer: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to update consumer: %w", err)
+	}
+	return rows == 1, nil
+}
+
+// RequestConsumerRestart asks the owner of a tenant to restart a consumer
+// started before now.
+func (r TenantRepository) RequestConsumerRestart(ctx context.Context, tenantID string) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE tenants SET consumer_restart_at = NOW() WHERE id = $1", tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to request consumer restart: %w", err)
+	}
+	return nil
+}
+
+// SaveConsumerStatus records the status of a consumer as reported by owner.
+func (r TenantRepository) SaveConsumerStatus(ctx context.Context, owner string, status structs.ConsumerStatus) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO tenant_consumer_status (tenant_id, owner, state, workers, prefetch, in_flight,
+			processed, failed, last_delivery_at, last_error, last_error_at, reported_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NOW())
+		ON CONFLICT (tenant_id) DO UPDATE
+		SET owner = EXCLUDED.owner, state = EXCLUDED.state, workers = EXCLUDED.workers,
+			prefetch = EXCLUDED.prefetch, in_flight = EXCLUDED.in_flight,
+			processed = EXCLUDED.processed, failed = EXCLUDED.failed,
+			last_delivery_at = EXCLUDED.last_delivery_at, last_error = EXCLUDED.last_error,
+			last_error_at = EXCLUDED.last_error_at, reported_at = EXCLUDED.reported_at
+	`, status.TenantID, owner, status.State, status.Workers, status.Prefetch, status.InFlight,
+		status.Processed, status.Failed, status.LastDeliveryAt, status.LastError, status.LastErrorAt)
+	if err != nil {
+		return fmt.Errorf("failed to save consumer status: %w", err)
+	}
+	return nil
+}
+
+// GetConsumerStatus returns the status last reported for a tenant's
+// consumer, or nil when no owner reported one.
+func (r TenantRepository) GetConsumerStatus(ctx context.Context, tenantID string) (*structs.ConsumerStatus, error) {
+	status := &structs.ConsumerStatus{}
+	var reportedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, `
+		SELECT tenant_id, owner, state, workers, prefetch, in_flight, processed, failed,
+			last_delivery_at, last_error, last_error_at, reported_at
+		FROM tenant_consumer_status WHERE tenant_id = $1
+	`, tenantID).Scan(&status.TenantID, &status.Owner, &status.State, &status.Workers, &status.Prefetch,
+		&status.InFlight, &status.Processed, &status.Failed, &status.LastDeliveryAt, &status.LastError,
+		&status.LastErrorAt, &reportedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consumer status: %w", err)
+	}
+	status.ReportedAt = &reportedAt.Time
+	return status, nil
+}
+
+// DeleteConsumerStatus forgets the status owner reported for a tenant it no
+// longer runs.
+func (r TenantRepository) DeleteConsumerStatus(ctx context.Context, tenantID, owner string) error {
+	_, err := r.db.ExecContext(ctx,
+		"DELETE FROM tenant_consumer_status WHERE tenant_id = $1 AND owner = $2", tenantID, owner)
+	if err != nil {
+		return fmt.Errorf("failed to delete consumer status: %w", err)
+	}
+	return nil
+}
@@ -7,11 +7,11 @@ import (
 
 func (r TenantRepository) CreateTenant(ctx context.Context, tenant structs.Tenant) error {
 	query := `
-		INSERT INTO tenants (id, name, concurrency_config)
-		VALUES ($1, $2, $3)
+		INSERT INTO tenants (id, name, concurrency_config, status, labels, settings)
+		VALUES ($1, $2, $3, $4, $5, $6)
 		RETURNING created_at, updated_at
 	`
-	err := r.db.QueryRowContext(ctx, query, tenant.ID, tenant.Name, tenant.ConcurrencyConfig).
+	err := r.db.QueryRowContext(ctx, query, tenant.ID, tenant.Name, tenant.ConcurrencyConfig, tenant.Status, tenant.Labels, tenant.Settings).
 		Scan(&tenant.CreatedAt, &tenant.UpdatedAt)
 	if err != nil {
 		return err
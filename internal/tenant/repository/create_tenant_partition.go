@@ -1,18 +1,240 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
+	"multi-tenant-service/package/structs"
 	"strings"
+	"time"
 )
 
+// PartitionTable returns the name of the messages partition of a tenant. The
+// message repository imports into it, so the name is shared from here.
+func PartitionTable(tenantID string) string {
+	return fmt.Sprintf("messages_tenant_%s", strings.Replace(tenantID, "-", "", -1))
+}
+
+// CreateTenantPartition creates the messages partition of a tenant. It is
+// itself partitioned by created_at; its default sub-partition holds messages
+// no time range was created for.
 func (r TenantRepository) CreateTenantPartition(tenantID string) error {
-	unixID := strings.Replace(tenantID, "-", "", -1)
+	table := PartitionTable(tenantID)
 	query := fmt.Sprintf(`
-		CREATE TABLE IF NOT EXISTS messages_tenant_%s 
-		PARTITION OF messages 
+		CREATE TABLE IF NOT EXISTS %s
+		PARTITION OF messages
 		FOR VALUES IN ('%s')
-	`, unixID, tenantID)
+		PARTITION BY RANGE (created_at)
+	`, table, tenantID)
+	if _, err := r.db.Exec(query); err != nil {
+		return err
+	}
 
+	query = fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s_default PARTITION OF %s DEFAULT`, table, table)
 	_, err := r.db.Exec(query)
 	return err
 }
+
+// SubPartitionTenantPartition turns the plain messages partition of a tenant
+// created before sub-partitioning into a partitioned one. The old table
+// becomes its sub-partition for everything created before until. It reports
+// false when the partition is missing or already partitioned.
+func (r TenantRepository) SubPartitionTenantPartition(ctx context.Context, tenantID string, until time.Time) (bool, error) {
+	table := PartitionTable(tenantID)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := LockPartition(ctx, tx, table); err != nil {
+		return false, err
+	}
+
+	// Only a partition still attached to messages is converted, so soft
+	// deleted tenants keep their partition untouched for restore
+	var kind string
+	err = tx.QueryRowContext(ctx, `
+		SELECT c.relkind FROM pg_class c
+		JOIN pg_inherits i ON i.inhrelid = c.oid
+		WHERE c.oid = to_regclass($1) AND i.inhparent = 'messages'::regclass
+	`, table).Scan(&kind)
+	if err == sql.ErrNoRows || (err == nil && kind != "r") {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect partition: %w", err)
+	}
+
+	statements := []string{
+		fmt.Sprintf(`ALTER TABLE messages DETACH PARTITION %s`, table),
+		fmt.Sprintf(`ALTER TABLE %s RENAME TO %s_legacy`, table, table),
+		fmt.Sprintf(`CREATE TABLE %s PARTITION OF messages FOR VALUES IN ('%s') PARTITION BY RANGE (created_at)`, table, tenantID),
+		fmt.Sprintf(`ALTER TABLE %s ATTACH PARTITION %s_legacy FOR VALUES FROM (MINVALUE) TO ('%s')`,
+			table, table, until.UTC().Format(time.RFC3339)),
+		fmt.Sprintf(`CREATE TABLE %s_default PARTITION OF %s DEFAULT`, table, table),
+	}
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return false, fmt.Errorf("failed to sub-partition %s: %w", table, err)
+		}
+	}
+	return true, tx.Commit()
+}
+
+// partitionBounds selects the FROM and TO of the partition bound expression
+// in column %[1]s. The bounds are read back from their SQL form and cast in
+// the same session, so the time zone they are printed in does not matter;
+// MINVALUE and DEFAULT give NULL.
+const partitionBounds = `
+	(regexp_match(%[1]s, 'FROM \(''([^'']+)''\)'))[1]::timestamptz,
+	(regexp_match(%[1]s, 'TO \(''([^'']+)''\)'))[1]::timestamptz`
+
+// ListMessagePartitions returns the sub-partitions of a tenant's messages
+// partition, oldest first and the default one last.
+func (r TenantRepository) ListMessagePartitions(ctx context.Context, tenantID string) ([]structs.MessagePartition, error) {
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(`
+		SELECT relname, bound = 'DEFAULT', %s
+		FROM (
+			SELECT c.relname, pg_get_expr(c.relpartbound, c.oid) AS bound
+			FROM pg_inherits i JOIN pg_class c ON c.oid = i.inhrelid
+			WHERE i.inhparent = to_regclass($1)
+		) partitions
+		ORDER BY 2, 4 NULLS LAST
+	`, fmt.Sprintf(partitionBounds, "bound")), PartitionTable(tenantID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list message partitions: %w", err)
+	}
+	return scanMessagePartitions(rows)
+}
+
+func scanMessagePartitions(rows *sql.Rows) ([]structs.MessagePartition, error) {
+	defer rows.Close()
+
+	partitions := []structs.MessagePartition{}
+	for rows.Next() {
+		var partition structs.MessagePartition
+		var from, to sql.NullTime
+		if err := rows.Scan(&partition.Name, &partition.Default, &from, &to); err != nil {
+			return nil, fmt.Errorf("failed to scan message partition: %w", err)
+		}
+		if from.Valid {
+			partition.From = &from.Time
+		}
+		if to.Valid {
+			partition.To = &to.Time
+		}
+		partitions = append(partitions, partition)
+	}
+	return partitions, rows.Err()
+}
+
+// messagePartitionTable names a sub-partition after the period it starts:
+// _pYYYYMM for a month from its first day, _pYYYYMMDD otherwise.
+func messagePartitionTable(tenantID string, from, to time.Time) string {
+	from = from.UTC()
+	if from.Day() == 1 && to.Sub(from) > 24*time.Hour {
+		return fmt.Sprintf("%s_p%s", PartitionTable(tenantID), from.Format("200601"))
+	}
+	return fmt.Sprintf("%s_p%s", PartitionTable(tenantID), from.Format("20060102"))
+}
+
+// LockPartition takes a transaction lock on the messages partition table,
+// so that replicas changing the same tenant's partitions, or importing into
+// it, take turns.
+func LockPartition(ctx context.Context, tx *sql.Tx, table string) error {
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, table); err != nil {
+		return fmt.Errorf("failed to lock %s: %w", table, err)
+	}
+	return nil
+}
+
+// CreateMessagePartition adds the sub-partition for messages created from
+// from up to to. Messages of that range already in the default
+// sub-partition are moved into it. A sub-partition that is already attached
+// is left alone, so replicas maintaining the same tenant do not fail.
+func (r TenantRepository) CreateMessagePartition(ctx context.Context, tenantID string, from, to time.Time) error {
+	table := PartitionTable(tenantID)
+	name := messagePartitionTable(tenantID, from, to)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := LockPartition(ctx, tx, table); err != nil {
+		return err
+	}
+	var attached bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM pg_inherits
+			WHERE inhrelid = to_regclass($1) AND inhparent = to_regclass($2)
+		)
+	`, name, table).Scan(&attached)
+	if err != nil {
+		return fmt.Errorf("failed to inspect message partition %s: %w", name, err)
+	}
+	if attached {
+		return nil
+	}
+
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (LIKE %s INCLUDING DEFAULTS)`, name, table)
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create message partition %s: %w", name, err)
+	}
+
+	query = fmt.Sprintf(`
+		WITH moved AS (
+			DELETE FROM %s_default WHERE created_at >= $1 AND created_at < $2 RETURNING *
+		)
+		INSERT INTO %s SELECT * FROM moved
+	`, table, name)
+	if _, err := tx.ExecContext(ctx, query, from, to); err != nil {
+		return fmt.Errorf("failed to move messages into %s: %w", name, err)
+	}
+
+	// Partition bounds cannot be bind parameters
+	query = fmt.Sprintf(`ALTER TABLE %s ATTACH PARTITION %s FOR VALUES FROM ('%s') TO ('%s')`,
+		table, name, from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339))
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to attach message partition %s: %w", name, err)
+	}
+	return tx.Commit()
+}
+
+// DropMessagePartition deletes a sub-partition with its messages.
+func (r TenantRepository) DropMessagePartition(ctx context.Context, name string) error {
+	if _, err := r.db.ExecContext(ctx, fmt.Sprintf(`DROP TABLE IF EXISTS %s`, name)); err != nil {
+		return fmt.Errorf("failed to drop message partition %s: %w", name, err)
+	}
+	return nil
+}
+
+// DetachMessagePartition takes a sub-partition out of the tenant partition.
+// Its table and messages are kept, and its bounds are recorded as the table
+// comment so it can still be archived and reattached.
+func (r TenantRepository) DetachMessagePartition(ctx context.Context, tenantID, name string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var bound string
+	err = tx.QueryRowContext(ctx,
+		`SELECT pg_get_expr(relpartbound, oid) FROM pg_class WHERE oid = to_regclass($1)`, name).Scan(&bound)
+	if err != nil {
+		return fmt.Errorf("failed to read bounds of %s: %w", name, err)
+	}
+
+	query := fmt.Sprintf(`ALTER TABLE %s DETACH PARTITION %s`, PartitionTable(tenantID), name)
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to detach message partition %s: %w", name, err)
+	}
+	query = fmt.Sprintf(`COMMENT ON TABLE %s IS '%s'`, name, strings.ReplaceAll(bound, "'", "''"))
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to record bounds of %s: %w", name, err)
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+)
+
+// DeleteMessageLeases removes the messages pulled from the tenant queue but
+// not acknowledged yet.
+func (r TenantRepository) DeleteMessageLeases(ctx context.Context, tenantID string) (int64, error) {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM message_leases WHERE tenant_id = $1", tenantID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete message leases: %w", err)
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"multi-tenant-service/package/structs"
+)
+
+const deletionJobColumns = `tenant_id, step, status, attempts, last_error, created_at, updated_at`
+
+func scanDeletionJob(row interface{ Scan(...interface{}) error }) (*structs.TenantDeletionJob, error) {
+	job := &structs.TenantDeletionJob{}
+	err := row.Scan(&job.TenantID, &job.Step, &job.Status, &job.Attempts,
+		&job.LastError, &job.CreatedAt, &job.UpdatedAt)
+	return job, err
+}
+
+// CreateDeletionJob starts a deletion job at the first step. An existing job
+// for the tenant is returned unchanged.
+func (r TenantRepository) CreateDeletionJob(ctx context.Context, tenantID string) (*structs.TenantDeletionJob, error) {
+	query := `
+		INSERT INTO tenant_deletion_jobs (tenant_id, step, status)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (tenant_id) DO UPDATE SET tenant_id = EXCLUDED.tenant_id
+		RETURNING ` + deletionJobColumns
+	job, err := scanDeletionJob(r.db.QueryRowContext(ctx, query,
+		tenantID, structs.DeletionSteps[0], structs.DeletionStatusRunning))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create deletion job: %w", err)
+	}
+	return job, nil
+}
+
+func (r TenantRepository) GetDeletionJob(ctx context.Context, tenantID string) (*structs.TenantDeletionJob, error) {
+	query := `SELECT ` + deletionJobColumns + ` FROM tenant_deletion_jobs WHERE tenant_id = $1`
+	job, err := scanDeletionJob(r.db.QueryRowContext(ctx, query, tenantID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("deletion job not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get deletion job: %w", err)
+	}
+	return job, nil
+}
+
+// UpdateDeletionJob records the step the job has reached. A non-empty lastErr
+// counts as a failed attempt of that step.
+func (r TenantRepository) UpdateDeletionJob(ctx context.Context, tenantID, step, status, lastErr string) error {
+	query := `
+		UPDATE tenant_deletion_jobs
+		SET step = $1, status = $2,
+			last_error = NULLIF($3, ''),
+			attempts = CASE WHEN $3 = '' THEN 0 ELSE attempts + 1 END,
+			updated_at = NOW()
+		WHERE tenant_id = $4
+	`
+	if _, err := r.db.ExecContext(ctx, query, step, status, lastErr, tenantID); err != nil {
+		return fmt.Errorf("failed to update deletion job: %w", err)
+	}
+	return nil
+}
+
+// ListUnfinishedDeletionJobs returns jobs that were interrupted or failed.
+func (r TenantRepository) ListUnfinishedDeletionJobs(ctx context.Context) ([]structs.TenantDeletionJob, error) {
+	query := `SELECT ` + deletionJobColumns + ` FROM tenant_deletion_jobs WHERE status <> $1`
+	rows, err := r.db.QueryContext(ctx, query, structs.DeletionStatusCompleted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deletion jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []structs.TenantDeletionJob
+	for rows.Next() {
+		job, err := scanDeletionJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan deletion job: %w", err)
+		}
+		jobs = append(jobs, *job)
+	}
+	return jobs, rows.Err()
+}
@@ -6,7 +6,7 @@ import (
 )
 
 func (r TenantRepository) DropTenantPartition(ctx context.Context, tenantID string) error {
-	query := fmt.Sprintf("DROP TABLE IF EXISTS messages_tenant_%s", tenantID)
-	_, err := r.db.Exec(query)
+	query := fmt.Sprintf("DROP TABLE IF EXISTS %s", PartitionTable(tenantID))
+	_, err := r.db.ExecContext(ctx, query)
 	return err
-}
\ No newline at end of file
+}
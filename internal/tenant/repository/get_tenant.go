@@ -7,16 +7,23 @@ import (
 	"multi-tenant-service/package/structs"
 )
 
-func (r TenantRepository) GetTenant(ctx context.Context, tenantID string) (*structs.Tenant, error) {
+const tenantColumns = `id, name, concurrency_config, status, labels, settings, created_at, updated_at, deleted_at,
+	consumer_paused, consumer_restart_at`
+
+func scanTenant(row interface{ Scan(...interface{}) error }) (*structs.Tenant, error) {
 	tenant := &structs.Tenant{}
-	query := `
-		SELECT id, name, concurrency_config, created_at, updated_at
-		FROM tenants WHERE id = $1
-	`
-	err :=r.db.QueryRowContext(ctx, query, tenantID).Scan(
-		&tenant.ID, &tenant.Name, &tenant.ConcurrencyConfig,
-		&tenant.CreatedAt, &tenant.UpdatedAt,
+	err := row.Scan(
+		&tenant.ID, &tenant.Name, &tenant.ConcurrencyConfig, &tenant.Status,
+		&tenant.Labels, &tenant.Settings,
+		&tenant.CreatedAt, &tenant.UpdatedAt, &tenant.DeletedAt,
+		&tenant.ConsumerPaused, &tenant.ConsumerRestartAt,
 	)
+	return tenant, err
+}
+
+func (r TenantRepository) GetTenant(ctx context.Context, tenantID string) (*structs.Tenant, error) {
+	query := `SELECT ` + tenantColumns + ` FROM tenants WHERE id = $1`
+	tenant, err := scanTenant(r.db.QueryRowContext(ctx, query, tenantID))
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("tenant not found")
 	}
@@ -25,4 +32,4 @@ func (r TenantRepository) GetTenant(ctx context.Context, tenantID string) (*stru
 	}
 
 	return tenant, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"multi-tenant-service/package/structs"
+	"time"
+)
+
+// Heartbeat records that a replica is alive.
+func (r TenantRepository) Heartbeat(ctx context.Context, replicaID string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO worker_replicas (id, heartbeat_at) VALUES ($1, NOW())
+		ON CONFLICT (id) DO UPDATE SET heartbeat_at = NOW()
+	`, replicaID)
+	if err != nil {
+		return fmt.Errorf("failed to record heartbeat: %w", err)
+	}
+	return nil
+}
+
+// ListLiveReplicas returns replicas that sent a heartbeat within ttl and
+// forgets the others. Heartbeats are stamped by the database clock, so the
+// cutoff is taken from it too.
+func (r TenantRepository) ListLiveReplicas(ctx context.Context, ttl time.Duration) ([]structs.WorkerReplica, error) {
+	_, err := r.db.ExecContext(ctx,
+		"DELETE FROM worker_replicas WHERE heartbeat_at < NOW() - $1::double precision * INTERVAL '1 millisecond'",
+		ttl.Milliseconds())
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove dead replicas: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT id, started_at, heartbeat_at FROM worker_replicas ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replicas: %w", err)
+	}
+	defer rows.Close()
+
+	var replicas []structs.WorkerReplica
+	for rows.Next() {
+		var replica structs.WorkerReplica
+		if err := rows.Scan(&replica.ID, &replica.StartedAt, &replica.HeartbeatAt); err != nil {
+			return nil, fmt.Errorf("failed to scan replica: %w", err)
+		}
+		replicas = append(replicas, replica)
+	}
+	return replicas, rows.Err()
+}
+
+func (r TenantRepository) RemoveReplica(ctx context.Context, replicaID string) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM tenant_leases WHERE owner = $1", replicaID); err != nil {
+		return fmt.Errorf("failed to release leases: %w", err)
+	}
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM worker_replicas WHERE id = $1", replicaID); err != nil {
+		return fmt.Errorf("failed to remove replica: %w", err)
+	}
+	return nil
+}
+
+// AcquireLease takes or renews the lease of a tenant. It only succeeds when
+// the lease is free, expired or already held by owner. The expiry is set and
+// compared on the database clock, so replicas with skewed clocks agree on it.
+func (r TenantRepository) AcquireLease(ctx context.Context, tenantID, owner string, ttl time.Duration) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		INSERT INTO tenant_leases (tenant_id, owner, acquired_at, expires_at)
+		VALUES ($1, $2, NOW(), NOW() + $3::double precision * INTERVAL '1 millisecond')
+		ON CONFLICT (tenant_id) DO UPDATE
+		SET owner = EXCLUDED.owner,
+			acquired_at = CASE WHEN tenant_leases.owner = EXCLUDED.owner
+				THEN tenant_leases.acquired_at ELSE NOW() END,
+			expires_at = EXCLUDED.expires_at
+		WHERE tenant_leases.owner = EXCLUDED.owner OR tenant_leases.expires_at < NOW()
+	`, tenantID, owner, ttl.Milliseconds())
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lease: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lease: %w", err)
+	}
+	return rows == 1, nil
+}
+
+func (r TenantRepository) ReleaseLease(ctx context.Context, tenantID, owner string) error {
+	_, err := r.db.ExecContext(ctx,
+		"DELETE FROM tenant_leases WHERE tenant_id = $1 AND owner = $2", tenantID, owner)
+	if err != nil {
+		return fmt.Errorf("failed to release lease: %w", err)
+	}
+	return nil
+}
+
+func (r TenantRepository) ListLeases(ctx context.Context) ([]structs.TenantLease, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT tenant_id, owner, acquired_at, expires_at FROM tenant_leases ORDER BY owner, tenant_id")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list leases: %w", err)
+	}
+	defer rows.Close()
+
+	var leases []structs.TenantLease
+	for rows.Next() {
+		var lease structs.TenantLease
+		if err := rows.Scan(&lease.TenantID, &lease.Owner, &lease.AcquiredAt, &lease.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("failed to scan lease: %w", err)
+		}
+		leases = append(leases, lease)
+	}
+	return leases, rows.Err()
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"multi-tenant-service/package/selector"
+	"multi-tenant-service/package/structs"
+	"strings"
+)
+
+func (r TenantRepository) ListTenants(ctx context.Context, req structs.RequestListTenant) ([]structs.Tenant, error) {
+	var conditions []string
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if req.Status != "" {
+		conditions = append(conditions, "status = "+arg(req.Status))
+	} else {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+
+	// Containment (@>) and key existence (?) are the operators the GIN index
+	// on labels can serve
+	for _, term := range req.Selector {
+		switch term.Operator {
+		case selector.OpEquals:
+			conditions = append(conditions, fmt.Sprintf("labels @> jsonb_build_object(%s::text, %s::text)", arg(term.Key), arg(term.Value)))
+		case selector.OpNotEquals:
+			conditions = append(conditions, fmt.Sprintf("NOT labels @> jsonb_build_object(%s::text, %s::text)", arg(term.Key), arg(term.Value)))
+		case selector.OpExists:
+			conditions = append(conditions, fmt.Sprintf("labels ? %s", arg(term.Key)))
+		case selector.OpDoesNotExist:
+			conditions = append(conditions, fmt.Sprintf("NOT labels ? %s", arg(term.Key)))
+		}
+	}
+
+	query := `SELECT ` + tenantColumns + ` FROM tenants WHERE ` +
+		strings.Join(conditions, " AND ") + ` ORDER BY created_at ASC`
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenants: %w", err)
+	}
+	defer rows.Close()
+
+	tenants := []structs.Tenant{}
+	for rows.Next() {
+		tenant, err := scanTenant(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan tenant: %w", err)
+		}
+		tenants = append(tenants, *tenant)
+	}
+	return tenants, rows.Err()
+}
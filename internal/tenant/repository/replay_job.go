@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"multi-tenant-service/package/structs"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const replayJobColumns = `id, tenant_id, status, from_time, to_time, filter, target, rate_limit,
+	cursor_created_at, cursor_id, total, scanned, replayed, failed, last_error,
+	created_at, updated_at, finished_at`
+
+func scanReplayJob(row interface{ Scan(...interface{}) error }) (*structs.ReplayJob, error) {
+	job := &structs.ReplayJob{}
+	var target []byte
+	var cursorCreatedAt sql.NullTime
+	var cursorID uuid.NullUUID
+	err := row.Scan(&job.ID, &job.TenantID, &job.Status, &job.From, &job.To, &job.Filter,
+		&target, &job.RateLimit, &cursorCreatedAt, &cursorID, &job.Total, &job.Scanned,
+		&job.Replayed, &job.Failed, &job.LastError, &job.CreatedAt, &job.UpdatedAt, &job.FinishedAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(target, &job.Target); err != nil {
+		return nil, fmt.Errorf("failed to decode replay target: %w", err)
+	}
+	if cursorCreatedAt.Valid && cursorID.Valid {
+		job.Cursor = &structs.MessageCursor{CreatedAt: cursorCreatedAt.Time, ID: cursorID.UUID}
+	}
+	return job, nil
+}
+
+func (r TenantRepository) CreateReplayJob(ctx context.Context, job structs.ReplayJob) (*structs.ReplayJob, error) {
+	target, err := json.Marshal(job.Target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode replay target: %w", err)
+	}
+	query := `
+		INSERT INTO replay_jobs (id, tenant_id, status, from_time, to_time, filter, target, rate_limit, total)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING ` + replayJobColumns
+	created, err := scanReplayJob(r.db.QueryRowContext(ctx, query, job.ID, job.TenantID, job.Status,
+		job.From, job.To, job.Filter, target, job.RateLimit, job.Total))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create replay job: %w", err)
+	}
+	return created, nil
+}
+
+func (r TenantRepository) GetReplayJob(ctx context.Context, tenantID, jobID string) (*structs.ReplayJob, error) {
+	query := `SELECT ` + replayJobColumns + ` FROM replay_jobs WHERE tenant_id = $1 AND id = $2`
+	job, err := scanReplayJob(r.db.QueryRowContext(ctx, query, tenantID, jobID))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("replay job not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get replay job: %w", err)
+	}
+	return job, nil
+}
+
+// ListReplayJobs returns the replay jobs of a tenant, newest first.
+func (r TenantRepository) ListReplayJobs(ctx context.Context, tenantID string, limit int) ([]structs.ReplayJob, error) {
+	query := `SELECT ` + replayJobColumns + ` FROM replay_jobs
+		WHERE tenant_id = $1 ORDER BY created_at DESC LIMIT $2`
+	return r.queryReplayJobs(ctx, query, tenantID, limit)
+}
+
+// ListUnfinishedReplayJobs returns the jobs that are waiting to start or
+// were interrupted while running.
+func (r TenantRepository) ListUnfinishedReplayJobs(ctx context.Context) ([]structs.ReplayJob, error) {
+	query := `SELECT ` + replayJobColumns + ` FROM replay_jobs WHERE status IN ($1, $2) ORDER BY created_at`
+	return r.queryReplayJobs(ctx, query, structs.ReplayStatusPending, structs.ReplayStatusRunning)
+}
+
+func (r TenantRepository) queryReplayJobs(ctx context.Context, query string, args ...interface{}) ([]structs.ReplayJob, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list replay jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []structs.ReplayJob{}
+	for rows.Next() {
+		job, err := scanReplayJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan replay job: %w", err)
+		}
+		jobs = append(jobs, *job)
+	}
+	return jobs, rows.Err()
+}
+
+// UpdateReplayProgress records the position a job has reached, adds to its
+// counters and moves a pending job to running. It returns the job status,
+// which is how a running job learns it was cancelled.
+func (r TenantRepository) UpdateReplayProgress(ctx context.Context, jobID string, cursor structs.MessageCursor, scanned, replayed, failed int64) (string, error) {
+	query := `
+		UPDATE replay_jobs
+		SET status = CASE WHEN status = $1 THEN $2 ELSE status END,
+			cursor_created_at = $3, cursor_id = $4,
+			scanned = scanned + $5, replayed = replayed + $6, failed = failed + $7,
+			updated_at = NOW()
+		WHERE id = $8
+		RETURNING status
+	`
+	var status string
+	err := r.db.QueryRowContext(ctx, query, structs.ReplayStatusPending, structs.ReplayStatusRunning,
+		cursor.CreatedAt, cursor.ID, scanned, replayed, failed, jobID).Scan(&status)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("replay job not found")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to update replay job: %w", err)
+	}
+	return status, nil
+}
+
+// FinishReplayJob moves a pending or running job to a final status. It
+// reports false when the job had already finished.
+func (r TenantRepository) FinishReplayJob(ctx context.Context, jobID, status, lastErr string) (bool, error) {
+	query := `
+		UPDATE replay_jobs
+		SET status = $1, last_error = NULLIF($2, ''), updated_at = NOW(), finished_at = $3
+		WHERE id = $4 AND status IN ($5, $6)
+	`
+	result, err := r.db.ExecContext(ctx, query, status, lastErr, time.Now(), jobID,
+		structs.ReplayStatusPending, structs.ReplayStatusRunning)
+	if err != nil {
+		return false, fmt.Errorf("failed to finish replay job: %w", err)
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to finish replay job: %w", err)
+	}
+	return updated > 0, nil
+}
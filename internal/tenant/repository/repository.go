@@ -2,8 +2,10 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"multi-tenant-service/package/connection/database"
 	"multi-tenant-service/package/structs"
+	"time"
 )
 
 type TenantRepository struct {
@@ -15,8 +17,49 @@ type ITenantRepository interface {
 	DropTenantPartition(ctx context.Context, tenantID string) error
 	DeleteTenant(ctx context.Context, tenantID string) error
 	CreateTenantPartition(tenantID string) error
+	SubPartitionTenantPartition(ctx context.Context, tenantID string, until time.Time) (bool, error)
+	ListMessagePartitions(ctx context.Context, tenantID string) ([]structs.MessagePartition, error)
+	CreateMessagePartition(ctx context.Context, tenantID string, from, to time.Time) error
+	DropMessagePartition(ctx context.Context, name string) error
+	DetachMessagePartition(ctx context.Context, tenantID, name string) error
+	ListDetachedMessagePartitions(ctx context.Context, tenantID string) ([]structs.MessagePartition, error)
+	ScanMessagePartition(ctx context.Context, table string, fn func(row json.RawMessage) error) error
+	RestoreMessagePartition(ctx context.Context, table string, next func() (json.RawMessage, error)) (int64, error)
+	AttachMessagePartition(ctx context.Context, tenantID, table string, from, to *time.Time) error
 	UpdateTenantConcurrency(ctx context.Context, tenantID string, workers int) error
+	UpdateTenantStatus(ctx context.Context, tenantID string, status string) error
+	TransitionTenantStatus(ctx context.Context, tenantID, from, to string) (bool, error)
 	GetTenant(ctx context.Context, tenantID string) (*structs.Tenant, error)
+	ListTenants(ctx context.Context, req structs.RequestListTenant) ([]structs.Tenant, error)
+	UpdateTenant(ctx context.Context, tenant structs.Tenant, expectedUpdatedAt *time.Time) (*structs.Tenant, error)
+	SoftDeleteTenant(ctx context.Context, tenantID string) error
+	RestoreTenant(ctx context.Context, tenantID string) error
+	ListExpiredTenants(ctx context.Context, cutoff time.Time) ([]string, error)
+	Heartbeat(ctx context.Context, replicaID string) error
+	ListLiveReplicas(ctx context.Context, ttl time.Duration) ([]structs.WorkerReplica, error)
+	RemoveReplica(ctx context.Context, replicaID string) error
+	AcquireLease(ctx context.Context, tenantID, owner string, ttl time.Duration) (bool, error)
+	ReleaseLease(ctx context.Context, tenantID, owner string) error
+	ListLeases(ctx context.Context) ([]structs.TenantLease, error)
+	SetConsumerPaused(ctx context.Context, tenantID string, paused bool) (bool, error)
+	RequestConsumerRestart(ctx context.Context, tenantID string) error
+	SaveConsumerStatus(ctx context.Context, owner string, status structs.ConsumerStatus) error
+	GetConsumerStatus(ctx context.Context, tenantID string) (*structs.ConsumerStatus, error)
+	DeleteConsumerStatus(ctx context.Context, tenantID, owner string) error
+	CreateScalingEvent(ctx context.Context, event structs.TenantScalingEvent) error
+	ListScalingEvents(ctx context.Context, tenantID string, limit int) ([]structs.TenantScalingEvent, error)
+	ArchiveTenantMessages(ctx context.Context, tenantID string) (int64, error)
+	DeleteMessageLeases(ctx context.Context, tenantID string) (int64, error)
+	CreateDeletionJob(ctx context.Context, tenantID string) (*structs.TenantDeletionJob, error)
+	GetDeletionJob(ctx context.Context, tenantID string) (*structs.TenantDeletionJob, error)
+	UpdateDeletionJob(ctx context.Context, tenantID, step, status, lastErr string) error
+	ListUnfinishedDeletionJobs(ctx context.Context) ([]structs.TenantDeletionJob, error)
+	CreateReplayJob(ctx context.Context, job structs.ReplayJob) (*structs.ReplayJob, error)
+	GetReplayJob(ctx context.Context, tenantID, jobID string) (*structs.ReplayJob, error)
+	ListReplayJobs(ctx context.Context, tenantID string, limit int) ([]structs.ReplayJob, error)
+	ListUnfinishedReplayJobs(ctx context.Context) ([]structs.ReplayJob, error)
+	UpdateReplayProgress(ctx context.Context, jobID string, cursor structs.MessageCursor, scanned, replayed, failed int64) (string, error)
+	FinishReplayJob(ctx context.Context, jobID, status, lastErr string) (bool, error)
 }
 
 
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"multi-tenant-service/package/structs"
+)
+
+func (r TenantRepository) CreateScalingEvent(ctx context.Context, event structs.TenantScalingEvent) error {
+	query := `
+		INSERT INTO tenant_scaling_events
+			(tenant_id, from_workers, to_workers, queue_depth, utilization, reason)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := r.db.ExecContext(ctx, query, event.TenantID, event.FromWorkers, event.ToWorkers,
+		event.QueueDepth, event.Utilization, event.Reason)
+	if err != nil {
+		return fmt.Errorf("failed to record scaling event: %w", err)
+	}
+	return nil
+}
+
+// ListScalingEvents returns the latest scaling events of a tenant, newest first.
+func (r TenantRepository) ListScalingEvents(ctx context.Context, tenantID string, limit int) ([]structs.TenantScalingEvent, error) {
+	query := `
+		SELECT id, tenant_id, from_workers, to_workers, queue_depth, utilization, reason, created_at
+		FROM tenant_scaling_events
+		WHERE tenant_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`
+	rows, err := r.db.QueryContext(ctx, query, tenantID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scaling events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []structs.TenantScalingEvent
+	for rows.Next() {
+		var event structs.TenantScalingEvent
+		if err := rows.Scan(&event.ID, &event.TenantID, &event.FromWorkers, &event.ToWorkers,
+			&event.QueueDepth, &event.Utilization, &event.Reason, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan scaling event: %w", err)
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
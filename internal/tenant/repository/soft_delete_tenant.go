@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"multi-tenant-service/package/structs"
+	"time"
+)
+
+// SoftDeleteTenant marks the tenant deleted, remembering its status for
+// restore, and detaches the tenant partition from messages. The partition
+// table and its rows are kept for restore. The status check is part of the
+// update, so of concurrent deletes only one succeeds.
+func (r TenantRepository) SoftDeleteTenant(ctx context.Context, tenantID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE tenants
+		SET status_before_delete = status, status = $1, deleted_at = NOW(), updated_at = NOW()
+		WHERE id = $2 AND deleted_at IS NULL AND status NOT IN ($1, $3)
+	`, structs.TenantStatusDeleted, tenantID, structs.TenantStatusDeleting)
+	if err != nil {
+		return fmt.Errorf("failed to delete tenant: %w", err)
+	}
+	if rows, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to delete tenant: %w", err)
+	} else if rows == 0 {
+		return tenantStateError(ctx, tx, tenantID, "tenant is already deleted")
+	}
+
+	query := fmt.Sprintf("ALTER TABLE messages DETACH PARTITION %s", PartitionTable(tenantID))
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to detach partition: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// RestoreTenant reattaches the tenant partition, clears deleted_at and puts
+// back the status the tenant had when it was deleted.
+func (r TenantRepository) RestoreTenant(ctx context.Context, tenantID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var deletedAt time.Time
+	err = tx.QueryRowContext(ctx, `
+		UPDATE tenants t
+		SET status = COALESCE(t.status_before_delete, $1), status_before_delete = NULL,
+			deleted_at = NULL, updated_at = NOW()
+		FROM (SELECT id, deleted_at FROM tenants WHERE id = $2 FOR UPDATE) old
+		WHERE t.id = old.id AND t.status = $3
+		RETURNING old.deleted_at
+	`, structs.TenantStatusActive, tenantID, structs.TenantStatusDeleted).Scan(&deletedAt)
+	if err == sql.ErrNoRows {
+		return tenantStateError(ctx, tx, tenantID, "tenant is not deleted")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to restore tenant: %w", err)
+	}
+
+	// A partition detached before created_at became part of the messages
+	// key may still hold rows without it. Those rows were stored before the
+	// delete, so they get its time rather than the restore time, which
+	// would move them past newer messages and out of retention's reach
+	query := fmt.Sprintf(`
+		UPDATE %s SET created_at = $1 WHERE created_at IS NULL
+	`, PartitionTable(tenantID))
+	if _, err := tx.ExecContext(ctx, query, deletedAt); err != nil {
+		return fmt.Errorf("failed to prepare partition: %w", err)
+	}
+	query = fmt.Sprintf("ALTER TABLE %s ALTER COLUMN created_at SET NOT NULL", PartitionTable(tenantID))
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to prepare partition: %w", err)
+	}
+
+	query = fmt.Sprintf("ALTER TABLE messages ATTACH PARTITION %s FOR VALUES IN ('%s')",
+		PartitionTable(tenantID), tenantID)
+	if _, err := tx.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to attach partition: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// tenantStateError explains why a status change matched no row: the tenant
+// does not exist, or it is in a status that does not allow the change.
+func tenantStateError(ctx context.Context, tx *sql.Tx, tenantID, conflict string) error {
+	var exists bool
+	if err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM tenants WHERE id = $1)", tenantID).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to get tenant: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("tenant not found")
+	}
+	return errors.New(conflict)
+}
+
+// ListExpiredTenants returns soft-deleted tenants deleted before the cutoff.
+func (r TenantRepository) ListExpiredTenants(ctx context.Context, cutoff time.Time) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT id FROM tenants WHERE status = $1 AND deleted_at < $2",
+		structs.TenantStatusDeleted, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list expired tenants: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan tenant: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"multi-tenant-service/package/structs"
+	"time"
+)
+
+// UpdateTenant writes name, labels and settings. When expectedUpdatedAt is set
+// the row is only updated if it has not changed since it was read.
+func (r TenantRepository) UpdateTenant(ctx context.Context, tenant structs.Tenant, expectedUpdatedAt *time.Time) (*structs.Tenant, error) {
+	query := `
+		UPDATE tenants SET name = $1, labels = $2, settings = $3, updated_at = NOW()
+		WHERE id = $4 AND ($5::timestamptz IS NULL OR updated_at = $5)
+		RETURNING ` + tenantColumns
+	updated, err := scanTenant(r.db.QueryRowContext(ctx, query,
+		tenant.Name, tenant.Labels, tenant.Settings, tenant.ID, expectedUpdatedAt))
+	if err != sql.ErrNoRows {
+		if err != nil {
+			return nil, fmt.Errorf("failed to update tenant: %w", err)
+		}
+		return updated, nil
+	}
+
+	// No row means the tenant is gone or was modified concurrently
+	if _, err := r.GetTenant(ctx, tenant.ID.String()); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("tenant was modified")
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+)
+
+func (r TenantRepository) UpdateTenantStatus(ctx context.Context, tenantID string, status string) error {
+	res, err := r.db.ExecContext(ctx,
+		"UPDATE tenants SET status = $1, updated_at = NOW() WHERE id = $2",
+		status, tenantID)
+	if err != nil {
+		return fmt.Errorf("failed to update status: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update status: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("tenant not found")
+	}
+	return nil
+}
+
+// TransitionTenantStatus moves the tenant from status from to status to in a
+// single statement. It reports false when the tenant is not in status from,
+// so concurrent transitions cannot both succeed.
+func (r TenantRepository) TransitionTenantStatus(ctx context.Context, tenantID, from, to string) (bool, error) {
+	res, err := r.db.ExecContext(ctx,
+		"UPDATE tenants SET status = $1, updated_at = NOW() WHERE id = $2 AND status = $3",
+		to, tenantID, from)
+	if err != nil {
+		return false, fmt.Errorf("failed to update status: %w", err)
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to update status: %w", err)
+	}
+	return rows == 1, nil
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"multi-tenant-service/package/archive"
+	"multi-tenant-service/package/structs"
+	"regexp"
+)
+
+// validTableName matches the table names a restore may create.
+var validTableName = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,62}$`)
+
+// ListDetachedMessagePartitions returns the sub-partitions retention
+// detached from a tenant's messages, with whether they are archived.
+func (tu *TenantUsecase) ListDetachedMessagePartitions(ctx context.Context, tenantID string) ([]structs.MessagePartition, error) {
+	partitions, err := tu.repository.ListDetachedMessagePartitions(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range partitions {
+		archived, err := tu.archives.Exists(ctx, archive.ManifestKey(tenantID, partitions[i].Name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to check archive of %s: %w", partitions[i].Name, err)
+		}
+		partitions[i].Archived = archived
+	}
+	return partitions, nil
+}
+
+// ArchiveMessagePartition writes a detached sub-partition of a tenant to the
+// archive storage. The manifest is written last, so a partition counts as
+// archived only once all of its files are stored.
+func (tu *TenantUsecase) ArchiveMessagePartition(ctx context.Context, tenantID, table string) (*archive.Manifest, error) {
+	partitions, err := tu.repository.ListDetachedMessagePartitions(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	var partition *structs.MessagePartition
+	for i := range partitions {
+		if partitions[i].Name == table {
+			partition = &partitions[i]
+		}
+	}
+	if partition == nil {
+		return nil, fmt.Errorf("detached partition not found")
+	}
+
+	w := archive.NewWriter(tu.archives, archive.Manifest{
+		TenantID: tenantID,
+		Table:    partition.Name,
+		From:     partition.From,
+		To:       partition.To,
+	}, tu.cfg.Archive.RowsPerFile)
+	err = tu.repository.ScanMessagePartition(ctx, partition.Name, func(row json.RawMessage) error {
+		return w.Write(ctx, row)
+	})
+	if err != nil {
+		w.Abort()
+		return nil, err
+	}
+	manifest, err := w.Close(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Archived %d messages of partition %s of tenant %s", manifest.Rows, partition.Name, tenantID)
+	return manifest, nil
+}
+
+// RestoreMessagePartition loads an archived sub-partition into a new table,
+// named as, or after the partition when as is empty. Every file is checked
+// against the manifest, and nothing is kept when one does not match. With
+// attach the table becomes a sub-partition of the tenant again.
+func (tu *TenantUsecase) RestoreMessagePartition(ctx context.Context, tenantID, table, as string, attach bool) (*archive.Manifest, error) {
+	manifest, err := archive.ReadManifest(ctx, tu.archives, archive.ManifestKey(tenantID, table))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	if as == "" {
+		as = manifest.Table
+	}
+	if !validTableName.MatchString(as) {
+		return nil, fmt.Errorf("invalid table name %q", as)
+	}
+
+	r := archive.NewReader(tu.archives, manifest)
+	defer r.Close()
+	restored, err := tu.repository.RestoreMessagePartition(ctx, as, func() (json.RawMessage, error) {
+		return r.Next(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if attach {
+		if err := tu.repository.AttachMessagePartition(ctx, tenantID, as, manifest.From, manifest.To); err != nil {
+			return nil, err
+		}
+	}
+
+	log.Printf("Restored %d messages of partition %s of tenant %s into %s", restored, table, tenantID, as)
+	return manifest, nil
+}
+
+// archiveExpiredPartitions archives the detached sub-partitions of a tenant
+// that are not archived yet and drops them afterwards unless retention
+// detaches. A partition whose archive failed stays detached and is retried
+// on the next pass.
+func (tu *TenantUsecase) archiveExpiredPartitions(ctx context.Context, tenant *structs.Tenant) error {
+	tenantID := tenant.ID.String()
+	partitions, err := tu.ListDetachedMessagePartitions(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	for _, partition := range partitions {
+		if !partition.Archived {
+			if _, err := tu.ArchiveMessagePartition(ctx, tenantID, partition.Name); err != nil {
+				return fmt.Errorf("failed to archive %s: %w", partition.Name, err)
+			}
+		}
+		if tenant.Settings.Retention.Action != structs.RetentionActionDetach {
+			if err := tu.repository.DropMessagePartition(ctx, partition.Name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
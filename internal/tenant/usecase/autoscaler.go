@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"multi-tenant-service/metrics"
+	"multi-tenant-service/package/structs"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RunAutoscaler periodically resizes the consumers of this process for
+// tenants that enable autoscaling, until ctx is cancelled. It does nothing
+// unless autoscaler.enabled is set.
+func (tu *TenantUsecase) RunAutoscaler(ctx context.Context) {
+	if !tu.cfg.Autoscaler.Enabled {
+		return
+	}
+
+	interval := tu.cfg.Autoscaler.Interval
+	if interval <= 0 {
+		interval = 15 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lastScaled := make(map[string]time.Time)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			tu.autoscale(ctx, lastScaled)
+		}
+	}
+}
+
+func (tu *TenantUsecase) autoscale(ctx context.Context, lastScaled map[string]time.Time) {
+	tu.mu.RLock()
+	consumers := make(map[string]*TenantConsumer, len(tu.consumers))
+	for tenantID, consumer := range tu.consumers {
+		consumers[tenantID] = consumer
+	}
+	tu.mu.RUnlock()
+
+	for tenantID, consumer := range consumers {
+		settings := consumer.Settings.Load().Autoscale
+		if !settings.Enabled {
+			continue
+		}
+		if time.Since(lastScaled[tenantID]) < tu.cfg.Autoscaler.Cooldown {
+			continue
+		}
+
+		info, err := tu.mq.InspectQueue(ctx, fmt.Sprintf("tenant_%s_queue", tenantID))
+		if err != nil {
+			log.Printf("Failed to inspect queue for tenant %s: %v", tenantID, err)
+			continue
+		}
+
+		active, _ := consumer.WorkerPool.Usage()
+		workers := int(atomic.LoadInt64(&consumer.Workers))
+		utilization := float64(active) / float64(workers)
+		metrics.TenantQueueDepth.WithLabelValues(tenantID).Set(float64(info.Messages))
+		metrics.TenantWorkers.WithLabelValues(tenantID).Set(float64(workers))
+
+		target, reason := tu.scaleTarget(settings, workers, active, info.Messages)
+		if target == workers {
+			continue
+		}
+
+		if err := tu.UpdateTenantConcurrency(ctx, tenantID, target); err != nil {
+			log.Printf("Failed to scale tenant %s to %d workers: %v", tenantID, target, err)
+			continue
+		}
+		lastScaled[tenantID] = time.Now()
+
+		direction := "up"
+		if target < workers {
+			direction = "down"
+		}
+		metrics.TenantScalingDecisionsTotal.WithLabelValues(tenantID, direction, reason).Inc()
+		metrics.TenantWorkers.WithLabelValues(tenantID).Set(float64(target))
+		log.Printf("Scaled tenant %s %s from %d to %d workers (%s, depth %d, utilization %.2f)",
+			tenantID, direction, workers, target, reason, info.Messages, utilization)
+
+		event := structs.TenantScalingEvent{
+			TenantID:    uuid.MustParse(tenantID),
+			FromWorkers: workers,
+			ToWorkers:   target,
+			QueueDepth:  info.Messages,
+			Utilization: utilization,
+			Reason:      reason,
+		}
+		if err := tu.repository.CreateScalingEvent(ctx, event); err != nil {
+			log.Printf("Failed to record scaling event for tenant %s: %v", tenantID, err)
+		}
+	}
+}
+
+// scaleTarget picks the worker count for a tenant. A backlog above the target
+// per worker scales up to clear it; an empty queue with mostly idle workers
+// halves the pool. The result always stays within the tenant's bounds.
+func (tu *TenantUsecase) scaleTarget(settings structs.AutoscaleSettings, workers, active, depth int) (int, string) {
+	backlog := tu.cfg.Autoscaler.TargetBacklog
+	if backlog <= 0 {
+		backlog = 10
+	}
+
+	target, reason := workers, structs.ScalingReasonBounds
+	switch {
+	case depth > workers*backlog:
+		target = int(math.Ceil(float64(depth) / float64(backlog)))
+		reason = structs.ScalingReasonBacklog
+	case depth == 0 && active*2 < workers:
+		target = max(active, workers/2)
+		reason = structs.ScalingReasonIdle
+	}
+	return min(max(target, settings.MinWorkers), settings.MaxWorkers), reason
+}
+
+func (tu *TenantUsecase) ListScalingEvents(ctx context.Context, tenantID string, limit int) ([]structs.TenantScalingEvent, error) {
+	if _, err := tu.repository.GetTenant(ctx, tenantID); err != nil {
+		return nil, err
+	}
+	return tu.repository.ListScalingEvents(ctx, tenantID, limit)
+}
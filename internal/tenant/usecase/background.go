@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+)
+
+// Start runs the consumer reconciler and the background tenant jobs until
+// ctx is cancelled or Shutdown stops them.
+func (tu *TenantUsecase) Start(ctx context.Context) {
+	tu.goBackground(ctx, tu.RunConsumers)
+	tu.goBackground(ctx, tu.RunPurgeWorker)
+	tu.goBackground(ctx, tu.RunPartitionMaintenance)
+	tu.goBackground(ctx, tu.RunAutoscaler)
+}
+
+// goBackground runs fn in a goroutine that Shutdown cancels and waits for.
+// The context fn gets is cancelled with ctx or on Shutdown, so work started
+// by a request passes a context without cancel to outlive it. It reports
+// false, without running fn, once Shutdown has begun.
+func (tu *TenantUsecase) goBackground(ctx context.Context, fn func(ctx context.Context)) bool {
+	tu.bgMu.Lock()
+	defer tu.bgMu.Unlock()
+	if tu.stopping {
+		return false
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(tu.bgCtx, cancel)
+	tu.bg.Add(1)
+	go func() {
+		defer tu.bg.Done()
+		defer cancel()
+		defer stop()
+		fn(ctx)
+	}()
+	return true
+}
+
+// isStopping reports whether Shutdown has begun.
+func (tu *TenantUsecase) isStopping() bool {
+	tu.bgMu.Lock()
+	defer tu.bgMu.Unlock()
+	return tu.stopping
+}
+
+// stopBackground cancels the background goroutines and waits until they
+// have returned or ctx is done. Nothing is started in the background after.
+func (tu *TenantUsecase) stopBackground(ctx context.Context) error {
+	tu.bgMu.Lock()
+	tu.stopping = true
+	tu.bgMu.Unlock()
+	tu.bgCancel()
+
+	done := make(chan struct{})
+	go func() {
+		tu.bg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("background jobs did not stop: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,271 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"multi-tenant-service/package/structs"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ConsumerStats are kept per tenant while the process owns it, so they
+// survive consumer restarts, pauses and resizes.
+type ConsumerStats struct {
+	Processed    atomic.Int64
+	Failed       atomic.Int64
+	lastDelivery atomic.Int64 // unix nanoseconds
+
+	mu          sync.Mutex
+	lastError   string
+	lastErrorAt time.Time
+}
+
+func (s *ConsumerStats) recordDelivery() {
+	s.lastDelivery.Store(time.Now().UnixNano())
+}
+
+func (s *ConsumerStats) recordResult(err error) {
+	if err == nil {
+		s.Processed.Add(1)
+		return
+	}
+	s.Failed.Add(1)
+	s.mu.Lock()
+	s.lastError = err.Error()
+	s.lastErrorAt = time.Now()
+	s.mu.Unlock()
+}
+
+// stats returns the stats of a tenant, creating them on first use.
+func (tu *TenantUsecase) stats(tenantID string) *ConsumerStats {
+	tu.statsMu.Lock()
+	defer tu.statsMu.Unlock()
+	stats, ok := tu.consumerStats[tenantID]
+	if !ok {
+		stats = &ConsumerStats{}
+		tu.consumerStats[tenantID] = stats
+	}
+	return stats
+}
+
+// replicaName identifies this process as the owner of consumer statuses.
+func (tu *TenantUsecase) replicaName() string {
+	if leases := tu.leases(); leases != nil {
+		return leases.replicaID
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "replica"
+	}
+	return fmt.Sprintf("%s-%d", hostname, os.Getpid())
+}
+
+// GetConsumerStatus describes the tenant's consumer as its owner runs it.
+// The owner answers from memory; other processes return the status the
+// owner last reported on a reconcile pass.
+func (tu *TenantUsecase) GetConsumerStatus(ctx context.Context, tenantID string) (*structs.ConsumerStatus, error) {
+	tenant, err := tu.repository.GetTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	var status *structs.ConsumerStatus
+	if tu.owns(tenantID) {
+		status = tu.localConsumerStatus(tenant)
+	} else if status, err = tu.repository.GetConsumerStatus(ctx, tenantID); err != nil {
+		return nil, err
+	} else if status == nil {
+		status = &structs.ConsumerStatus{
+			TenantID: tenant.ID,
+			State:    structs.ConsumerStateStopped,
+			Workers:  tenant.ConcurrencyConfig,
+		}
+	}
+	// Reported before the owner applied a pause or resume, the flag wins
+	if tenant.ConsumerPaused {
+		status.State = structs.ConsumerStatePaused
+	} else if status.State == structs.ConsumerStatePaused {
+		status.State = structs.ConsumerStateStopped
+	}
+
+	info, err := tu.mq.InspectQueue(ctx, fmt.Sprintf("tenant_%s_queue", tenantID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect queue: %w", err)
+	}
+	status.QueueDepth = info.Messages
+	status.QueueConsumers = info.Consumers
+	return status, nil
+}
+
+// localConsumerStatus describes the consumer this process runs for tenant.
+func (tu *TenantUsecase) localConsumerStatus(tenant *structs.Tenant) *structs.ConsumerStatus {
+	tenantID := tenant.ID.String()
+	status := &structs.ConsumerStatus{
+		TenantID: tenant.ID,
+		State:    structs.ConsumerStateStopped,
+		Owner:    tu.replicaName(),
+		Workers:  tenant.ConcurrencyConfig,
+	}
+
+	tu.mu.RLock()
+	consumer, running := tu.consumers[tenantID]
+	tu.mu.RUnlock()
+
+	switch {
+	case running:
+		status.State = structs.ConsumerStateRunning
+		select {
+		case <-consumer.done:
+			status.State = structs.ConsumerStateStopped
+		default:
+		}
+		status.Workers = int(atomic.LoadInt64(&consumer.Workers))
+		status.Prefetch = consumer.Prefetch
+		consumer.pendingMu.Lock()
+		status.InFlight = len(consumer.pending)
+		consumer.pendingMu.Unlock()
+	case tenant.ConsumerPaused:
+		status.State = structs.ConsumerStatePaused
+	}
+
+	stats := tu.stats(tenantID)
+	status.Processed = stats.Processed.Load()
+	status.Failed = stats.Failed.Load()
+	if nanos := stats.lastDelivery.Load(); nanos != 0 {
+		at := time.Unix(0, nanos)
+		status.LastDeliveryAt = &at
+	}
+	stats.mu.Lock()
+	if stats.lastError != "" {
+		at := stats.lastErrorAt
+		status.LastError = stats.lastError
+		status.LastErrorAt = &at
+	}
+	stats.mu.Unlock()
+	return status
+}
+
+// reportConsumerStatuses records the status of the consumers of the owned
+// tenants for the other processes. Tenants no longer owned lose their
+// stats and their reported status.
+func (tu *TenantUsecase) reportConsumerStatuses(ctx context.Context, owned map[string]*structs.Tenant) {
+	owner := tu.replicaName()
+	for _, tenant := range owned {
+		if err := tu.repository.SaveConsumerStatus(ctx, owner, *tu.localConsumerStatus(tenant)); err != nil {
+			log.Printf("Failed to report consumer status of tenant %s: %v", tenant.ID, err)
+		}
+	}
+
+	tu.statsMu.Lock()
+	var released []string
+	for tenantID := range tu.consumerStats {
+		if owned[tenantID] == nil {
+			released = append(released, tenantID)
+			delete(tu.consumerStats, tenantID)
+		}
+	}
+	tu.statsMu.Unlock()
+
+	for _, tenantID := range released {
+		if err := tu.repository.DeleteConsumerStatus(ctx, tenantID, owner); err != nil {
+			log.Printf("Failed to delete consumer status of tenant %s: %v", tenantID, err)
+		}
+	}
+}
+
+// RestartConsumer drains the running consumer and starts it again with the
+// stored tenant config. It also revives a consumer whose delivery channel
+// was closed by the broker. The restart is recorded on the tenant, so when
+// another process owns the tenant, that one restarts it on its next
+// reconcile pass.
+func (tu *TenantUsecase) RestartConsumer(ctx context.Context, tenantID string) error {
+	tenant, err := tu.repository.GetTenant(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if tenant.Status != structs.TenantStatusActive || tenant.ConsumerPaused ||
+		tenant.Settings.Delivery == structs.DeliveryPull {
+		return fmt.Errorf("consumer is not running")
+	}
+	if err := tu.repository.RequestConsumerRestart(ctx, tenantID); err != nil {
+		return err
+	}
+
+	tu.mu.Lock()
+	consumer, running := tu.consumers[tenantID]
+	if !running {
+		tu.mu.Unlock()
+		log.Printf("Requested restart of consumer for tenant %s", tenantID)
+		return nil
+	}
+	delete(tu.consumers, tenantID)
+	tu.mu.Unlock()
+
+	log.Printf("Restarting consumer for tenant %s", tenantID)
+	return tu.restartTenantConsumer(ctx, tenantID, consumer)
+}
+
+// PauseConsumer stops taking deliveries for the tenant and lets in-flight
+// messages finish. Publishing is unaffected; messages wait in the queue.
+// The pause is stored on the tenant and lasts until ResumeConsumer; when
+// another process owns the tenant, that one stops its consumer on its next
+// reconcile pass.
+func (tu *TenantUsecase) PauseConsumer(ctx context.Context, tenantID string) error {
+	if _, err := tu.repository.GetTenant(ctx, tenantID); err != nil {
+		return err
+	}
+	paused, err := tu.repository.SetConsumerPaused(ctx, tenantID, true)
+	if err != nil {
+		return err
+	}
+	if !paused {
+		return fmt.Errorf("consumer is not running")
+	}
+
+	tu.mu.Lock()
+	consumer, running := tu.consumers[tenantID]
+	delete(tu.consumers, tenantID)
+	tu.mu.Unlock()
+
+	if running {
+		drainCtx, cancel := context.WithTimeout(ctx, tu.cfg.Server.ShutdownTimeout)
+		defer cancel()
+		tu.drainTenantConsumer(drainCtx, tenantID, consumer)
+	}
+
+	log.Printf("Paused consumer for tenant %s", tenantID)
+	return nil
+}
+
+// ResumeConsumer lifts a pause. The consumer starts here when this process
+// owns the tenant, and otherwise on the owner's next reconcile pass.
+func (tu *TenantUsecase) ResumeConsumer(ctx context.Context, tenantID string) error {
+	resumed, err := tu.repository.SetConsumerPaused(ctx, tenantID, false)
+	if err != nil {
+		return err
+	}
+	if !resumed {
+		if _, err := tu.repository.GetTenant(ctx, tenantID); err != nil {
+			return err
+		}
+		return fmt.Errorf("consumer is not paused")
+	}
+
+	tenant, err := tu.repository.GetTenant(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	// A suspended tenant or one owned elsewhere stays without a consumer
+	if tenant.Status != structs.TenantStatusActive || !tu.owns(tenantID) {
+		return nil
+	}
+	if err := tu.startTenantConsumer(ctx, tenant); err != nil {
+		return fmt.Errorf("failed to start consumer: %w", err)
+	}
+
+	log.Printf("Resumed consumer for tenant %s", tenantID)
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"multi-tenant-service/package/broker"
 	"multi-tenant-service/package/structs"
 
 	"github.com/google/uuid"
@@ -15,12 +16,18 @@ func (tu *TenantUsecase) CreateTenant(ctx context.Context, req structs.CreateTen
 	if req.ConcurrencyConfig == 0 {
 		req.ConcurrencyConfig = 3
 	}
+	if err := tu.validateSettings(req.Settings); err != nil {
+		return nil, err
+	}
 
 	// Insert tenant into database
 	tenant := &structs.Tenant{
 		ID:                tenantID,
 		Name:              req.Name,
 		ConcurrencyConfig: req.ConcurrencyConfig,
+		Status:            structs.TenantStatusActive,
+		Labels:            req.Labels,
+		Settings:          req.Settings,
 	}
 
 	if err := tu.repository.CreateTenant(ctx, *tenant); err != nil {
@@ -32,10 +39,17 @@ func (tu *TenantUsecase) CreateTenant(ctx context.Context, req structs.CreateTen
 		log.Printf("Warning: failed to create partition for tenant %s: %v", tenantID.String(), err)
 		return nil, fmt.Errorf("failed to create partition: %w", err)
 	}
+	if err := tu.ensurePartitions(ctx, tenant); err != nil {
+		log.Printf("Warning: failed to create time partitions for tenant %s: %v", tenantID.String(), err)
+	}
 
-	// Create RabbitMQ queue and start consumer
-	if err := tu.startTenantConsumer(ctx, tenantID.String(), req.ConcurrencyConfig); err != nil {
-		return nil, fmt.Errorf("failed to start consumer: %w", err)
+	// Create the queue and start the consumer unless another process owns the tenant
+	if tu.owns(tenant.ID.String()) {
+		if err := tu.startTenantConsumer(ctx, tenant); err != nil {
+			return nil, fmt.Errorf("failed to start consumer: %w", err)
+		}
+	} else if _, err := tu.mq.DeclareQueue(ctx, fmt.Sprintf("tenant_%s_queue", tenant.ID), broker.QueueOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to declare queue: %w", err)
 	}
 
 	return tenant, nil
@@ -4,29 +4,101 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"multi-tenant-service/package/structs"
+	"time"
 )
 
-
-func (tu *TenantUsecase) DeleteTenant(ctx context.Context, tenantID string) error {
-	tu.mu.Lock()
-	defer tu.mu.Unlock()
+// DeleteTenant marks the tenant deleted, detaches its partition and stops its
+// consumer. The tenant can be restored until the grace period expires.
+func (tu *TenantUsecase) DeleteTenant(ctx context.Context, tenantID string) (*structs.Tenant, error) {
+	if err := tu.repository.SoftDeleteTenant(ctx, tenantID); err != nil {
+		return nil, err
+	}
 
 	// Stop consumer
-	if consumer, exists := tu.consumers[tenantID]; exists {
-		close(consumer.StopChan)
-		tu.mqClient.CloseChannel(fmt.Sprintf("tenant_%s", tenantID))
-		delete(tu.consumers, tenantID)
+	tu.releaseConsumer(ctx, tenantID)
+
+	return tu.repository.GetTenant(ctx, tenantID)
+}
+
+// RestoreTenant reattaches the partition of a soft-deleted tenant, puts back
+// the status it had before the delete and restarts its consumer if that
+// status is active.
+func (tu *TenantUsecase) RestoreTenant(ctx context.Context, tenantID string) (*structs.Tenant, error) {
+	if err := tu.repository.RestoreTenant(ctx, tenantID); err != nil {
+		return nil, err
+	}
+
+	tenant, err := tu.repository.GetTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if tenant.Status == structs.TenantStatusActive && tu.owns(tenantID) {
+		if err := tu.startTenantConsumer(ctx, tenant); err != nil {
+			return nil, fmt.Errorf("failed to start consumer: %w", err)
+		}
+	}
+
+	log.Printf("Restored tenant %s", tenantID)
+	return tenant, nil
+}
+
+func (tu *TenantUsecase) GetDeletionJob(ctx context.Context, tenantID string) (*structs.TenantDeletionJob, error) {
+	return tu.repository.GetDeletionJob(ctx, tenantID)
+}
+
+// RunPurgeWorker hard-deletes soft-deleted tenants whose grace period has
+// expired until ctx is cancelled.
+func (tu *TenantUsecase) RunPurgeWorker(ctx context.Context) {
+	interval := tu.cfg.Tenant.PurgeInterval
+	if interval <= 0 {
+		interval = 10 * time.Minute
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		tu.purgeExpiredTenants(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (tu *TenantUsecase) purgeExpiredTenants(ctx context.Context) {
+	cutoff := time.Now().Add(-tu.cfg.Tenant.DeletionGracePeriod)
+	tenantIDs, err := tu.repository.ListExpiredTenants(ctx, cutoff)
+	if err != nil {
+		log.Printf("Failed to list expired tenants: %v", err)
+		return
+	}
+
+	for _, tenantID := range tenantIDs {
+		if !tu.owns(tenantID) {
+			continue
+		}
+		if err := tu.purgeTenant(ctx, tenantID); err != nil {
+			log.Printf("Failed to purge tenant %s: %v", tenantID, err)
+		}
 	}
+}
 
-	// Delete from database
-	if err := tu.repository.DeleteTenant(ctx, tenantID); err != nil {
-		return fmt.Errorf("failed to delete tenant: %w", err)
+// purgeTenant hands a tenant over to the persisted deletion job.
+func (tu *TenantUsecase) purgeTenant(ctx context.Context, tenantID string) error {
+	if err := tu.repository.UpdateTenantStatus(ctx, tenantID, structs.TenantStatusDeleting); err != nil {
+		return err
 	}
 
-	// Drop partition table
-	if err := tu.repository.DropTenantPartition(ctx, tenantID); err != nil {
-		log.Printf("Warning: failed to drop partition for tenant %s: %v", tenantID, err)
+	if _, err := tu.repository.CreateDeletionJob(ctx, tenantID); err != nil {
+		return err
 	}
 
+	log.Printf("Purging tenant %s", tenantID)
+	tu.startDeletionJob(tenantID)
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"multi-tenant-service/package/broker"
+	"multi-tenant-service/package/rules"
+	"multi-tenant-service/package/structs"
+	"time"
+)
+
+const (
+	maxDeletionAttempts = 5
+	deletionRetryDelay  = 5 * time.Second
+)
+
+// ResumeDeletionJobs restarts deletion jobs that were interrupted by a crash
+// or a restart. It is called once on startup.
+func (tu *TenantUsecase) ResumeDeletionJobs(ctx context.Context) error {
+	jobs, err := tu.repository.ListUnfinishedDeletionJobs(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		if !tu.owns(job.TenantID.String()) {
+			continue
+		}
+		log.Printf("Resuming deletion of tenant %s at step %s", job.TenantID, job.Step)
+		tu.startDeletionJob(job.TenantID.String())
+	}
+	return nil
+}
+
+// startDeletionJob runs the deletion job of a tenant unless it is already
+// running in this process.
+func (tu *TenantUsecase) startDeletionJob(tenantID string) {
+	tu.mu.Lock()
+	if tu.deletions[tenantID] {
+		tu.mu.Unlock()
+		return
+	}
+	tu.deletions[tenantID] = true
+	tu.mu.Unlock()
+
+	done := func() {
+		tu.mu.Lock()
+		delete(tu.deletions, tenantID)
+		tu.mu.Unlock()
+	}
+	// An interrupted job resumes on the next start
+	if !tu.goBackground(context.Background(), func(ctx context.Context) {
+		defer done()
+		tu.runDeletionJob(ctx, tenantID)
+	}) {
+		done()
+	}
+}
+
+// runDeletionJob executes the remaining steps of the job, persisting progress
+// after each one so an interrupted job continues where it stopped.
+func (tu *TenantUsecase) runDeletionJob(ctx context.Context, tenantID string) {
+	job, err := tu.repository.GetDeletionJob(ctx, tenantID)
+	if err != nil {
+		log.Printf("Failed to load deletion job for tenant %s: %v", tenantID, err)
+		return
+	}
+
+	start := len(structs.DeletionSteps)
+	for i, step := range structs.DeletionSteps {
+		if step == job.Step {
+			start = i
+			break
+		}
+	}
+
+	for i := start; i < len(structs.DeletionSteps); i++ {
+		step := structs.DeletionSteps[i]
+		if err := tu.runDeletionStepWithRetry(ctx, tenantID, step); err != nil {
+			log.Printf("Deletion of tenant %s failed at step %s: %v", tenantID, step, err)
+			return
+		}
+
+		next, status := structs.DeletionStepDone, structs.DeletionStatusCompleted
+		if i+1 < len(structs.DeletionSteps) {
+			next, status = structs.DeletionSteps[i+1], structs.DeletionStatusRunning
+		}
+		if err := tu.repository.UpdateDeletionJob(ctx, tenantID, next, status, ""); err != nil {
+			log.Printf("Failed to record deletion progress for tenant %s: %v", tenantID, err)
+			return
+		}
+	}
+
+	log.Printf("Deleted tenant %s", tenantID)
+}
+
+func (tu *TenantUsecase) runDeletionStepWithRetry(ctx context.Context, tenantID, step string) error {
+	var err error
+	for attempt := 1; attempt <= maxDeletionAttempts; attempt++ {
+		if err = tu.runDeletionStep(ctx, tenantID, step); err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			// Interrupted by shutdown, not failed; the job resumes on start
+			return ctx.Err()
+		}
+
+		status := structs.DeletionStatusRunning
+		if attempt == maxDeletionAttempts {
+			status = structs.DeletionStatusFailed
+		}
+		if uerr := tu.repository.UpdateDeletionJob(ctx, tenantID, step, status, err.Error()); uerr != nil {
+			log.Printf("Failed to record deletion error for tenant %s: %v", tenantID, uerr)
+		}
+		if attempt == maxDeletionAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * deletionRetryDelay):
+		}
+	}
+	return err
+}
+
+func (tu *TenantUsecase) runDeletionStep(ctx context.Context, tenantID, step string) error {
+	queueName := fmt.Sprintf("tenant_%s_queue", tenantID)
+
+	switch step {
+	case structs.DeletionStepPurgeQueue:
+		// Declaring first keeps the purge idempotent when the queue is already gone
+		if _, err := tu.mq.DeclareQueue(ctx, queueName, broker.QueueOptions{}); err != nil {
+			return fmt.Errorf("failed to declare queue: %w", err)
+		}
+		purged, err := tu.mq.PurgeQueue(ctx, queueName)
+		if err != nil {
+			return fmt.Errorf("failed to purge queue: %w", err)
+		}
+		log.Printf("Purged %d messages from queue %s", purged, queueName)
+		leased, err := tu.repository.DeleteMessageLeases(ctx, tenantID)
+		if err != nil {
+			return err
+		}
+		if leased > 0 {
+			log.Printf("Deleted %d pulled messages of tenant %s", leased, tenantID)
+		}
+
+	case structs.DeletionStepDeleteQueue:
+		if err := tu.mq.DeleteQueue(ctx, queueName); err != nil {
+			return fmt.Errorf("failed to delete queue: %w", err)
+		}
+		extraQueues := []string{deadLetterQueue(tenantID)}
+		if tenant, err := tu.repository.GetTenant(ctx, tenantID); err == nil {
+			if engine, err := rules.New(tenant.Settings.Rules); err == nil {
+				for _, subscription := range engine.Routes() {
+					extraQueues = append(extraQueues, rules.SubscriptionQueue(tenantID, subscription))
+				}
+			}
+		}
+		for _, name := range extraQueues {
+			err := tu.mq.DeleteQueue(ctx, name)
+			if err != nil && !errors.Is(err, broker.ErrQueueNotFound) {
+				return fmt.Errorf("failed to delete queue %s: %w", name, err)
+			}
+		}
+
+	case structs.DeletionStepArchiveData:
+		archived, err := tu.repository.ArchiveTenantMessages(ctx, tenantID)
+		if err != nil {
+			return err
+		}
+		log.Printf("Archived %d messages of tenant %s", archived, tenantID)
+
+	case structs.DeletionStepDropPartition:
+		if err := tu.repository.DropTenantPartition(ctx, tenantID); err != nil {
+			return fmt.Errorf("failed to drop partition: %w", err)
+		}
+
+	case structs.DeletionStepDeleteRow:
+		if err := tu.repository.DeleteTenant(ctx, tenantID); err != nil {
+			return fmt.Errorf("failed to delete tenant: %w", err)
+		}
+	}
+	return nil
+}
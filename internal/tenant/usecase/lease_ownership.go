@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"context"
+	"hash/fnv"
+	"log"
+	"multi-tenant-service/internal/tenant/repository"
+	"multi-tenant-service/package/structs"
+	"sync"
+	"time"
+)
+
+// LeaseOwnership assigns tenants to replicas through leases in Postgres.
+// Every replica heartbeats into worker_replicas and ranks the live replicas
+// per tenant with rendezvous hashing, so only the tenants whose top replica
+// changed move when a replica joins or leaves. A replica consumes a tenant
+// only while it holds the tenant's lease, which keeps each tenant on exactly
+// one replica and its concurrency honoured cluster-wide. Leases of a replica
+// that stops heartbeating expire after TTL and are taken over by the others.
+// The replica stops owning a lease at its expiry unless it was renewed, so a
+// replica cut off from Postgres gives its tenants up before anyone else can
+// take them.
+type LeaseOwnership struct {
+	repository repository.ITenantRepository
+	replicaID  string
+	ttl        time.Duration
+
+	mu sync.RWMutex
+	// held maps the tenants whose lease this replica holds to the lease
+	// expiry, taken before the lease was written so it is never later than
+	// the stored one
+	held      map[string]time.Time
+	releasing map[string]bool
+}
+
+func NewLeaseOwnership(repo repository.ITenantRepository, replicaID string, ttl time.Duration) *LeaseOwnership {
+	return &LeaseOwnership{
+		repository: repo,
+		replicaID:  replicaID,
+		ttl:        ttl,
+		held:       make(map[string]time.Time),
+		releasing:  make(map[string]bool),
+	}
+}
+
+func (l *LeaseOwnership) Owns(tenantID string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	expiry, ok := l.held[tenantID]
+	return ok && time.Now().Before(expiry)
+}
+
+// NextExpiry returns the earliest expiry of the held leases, when one is
+// held.
+func (l *LeaseOwnership) NextExpiry() (time.Time, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	var next time.Time
+	for _, expiry := range l.held {
+		if next.IsZero() || expiry.Before(next) {
+			next = expiry
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// Acquire heartbeats, then takes or renews the leases of the tenants ranked
+// to this replica. Held leases of tenants that moved elsewhere stop being
+// owned and are handed back by Release once their consumers are drained, as
+// are leases that failed to renew. Until then they are still renewed, so the
+// next owner cannot start while the old workers run. When the heartbeat fails
+// nothing is renewed and the held leases stop being owned at their expiry.
+func (l *LeaseOwnership) Acquire(ctx context.Context, tenantIDs []string) error {
+	if err := l.repository.Heartbeat(ctx, l.replicaID); err != nil {
+		return err
+	}
+	replicas, err := l.repository.ListLiveReplicas(ctx, l.ttl)
+	if err != nil {
+		return err
+	}
+
+	held := make(map[string]time.Time)
+	for _, tenantID := range tenantIDs {
+		if rendezvousOwner(tenantID, replicas) != l.replicaID {
+			continue
+		}
+		expiry := time.Now().Add(l.ttl)
+		ok, err := l.repository.AcquireLease(ctx, tenantID, l.replicaID, l.ttl)
+		if err != nil {
+			log.Printf("Failed to acquire lease for tenant %s: %v", tenantID, err)
+			continue
+		}
+		if ok {
+			held[tenantID] = expiry
+		}
+	}
+
+	l.mu.RLock()
+	var releasing []string
+	for tenantID := range l.releasing {
+		if _, ok := held[tenantID]; !ok {
+			releasing = append(releasing, tenantID)
+		}
+	}
+	l.mu.RUnlock()
+	lost := make(map[string]bool)
+	for _, tenantID := range releasing {
+		ok, err := l.repository.AcquireLease(ctx, tenantID, l.replicaID, l.ttl)
+		if err != nil {
+			log.Printf("Failed to renew released lease for tenant %s: %v", tenantID, err)
+		} else if !ok {
+			// Expired and taken over already; nothing left to hand back
+			lost[tenantID] = true
+		}
+	}
+
+	l.mu.Lock()
+	for tenantID := range l.held {
+		if _, ok := held[tenantID]; !ok {
+			l.releasing[tenantID] = true
+		}
+	}
+	for tenantID := range held {
+		delete(l.releasing, tenantID)
+	}
+	for tenantID := range lost {
+		delete(l.releasing, tenantID)
+	}
+	l.held = held
+	l.mu.Unlock()
+	return nil
+}
+
+// Release hands back the leases Acquire dropped, except those of tenants
+// busy reports, whose consumers are still draining. They are kept for a
+// later call.
+func (l *LeaseOwnership) Release(ctx context.Context, busy func(tenantID string) bool) error {
+	l.mu.RLock()
+	var releasing []string
+	for tenantID := range l.releasing {
+		releasing = append(releasing, tenantID)
+	}
+	l.mu.RUnlock()
+
+	for _, tenantID := range releasing {
+		if busy(tenantID) {
+			continue
+		}
+		if err := l.repository.ReleaseLease(ctx, tenantID, l.replicaID); err != nil {
+			return err
+		}
+		l.mu.Lock()
+		delete(l.releasing, tenantID)
+		l.mu.Unlock()
+	}
+	return nil
+}
+
+// Leave gives up every lease and deregisters the replica so the others can
+// take its tenants over without waiting for the leases to expire.
+func (l *LeaseOwnership) Leave(ctx context.Context) error {
+	l.mu.Lock()
+	l.held = make(map[string]time.Time)
+	l.releasing = make(map[string]bool)
+	l.mu.Unlock()
+	return l.repository.RemoveReplica(ctx, l.replicaID)
+}
+
+// rendezvousOwner returns the replica with the highest hash for tenantID.
+func rendezvousOwner(tenantID string, replicas []structs.WorkerReplica) string {
+	var owner string
+	var best uint64
+	for _, replica := range replicas {
+		h := fnv.New64a()
+		h.Write([]byte(tenantID))
+		h.Write([]byte{0})
+		h.Write([]byte(replica.ID))
+		if score := h.Sum64(); owner == "" || score > best {
+			owner, best = replica.ID, score
+		}
+	}
+	return owner
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"multi-tenant-service/package/structs"
+)
+
+func (tu *TenantUsecase) ListTenant(ctx context.Context, req structs.RequestListTenant) ([]structs.Tenant, error) {
+	return tu.repository.ListTenants(ctx, req)
+}
+
+// BulkUpdateConcurrency sets the worker count of every tenant matched by the
+// selector through UpdateTenantConcurrency.
+func (tu *TenantUsecase) BulkUpdateConcurrency(ctx context.Context, req structs.RequestListTenant, workers int) ([]structs.BulkTenantResult, error) {
+	if workers < 1 {
+		return nil, fmt.Errorf("workers must be at least 1")
+	}
+	return tu.bulk(ctx, req, func(tenantID string) error {
+		return tu.UpdateTenantConcurrency(ctx, tenantID, workers)
+	})
+}
+
+func (tu *TenantUsecase) BulkSuspend(ctx context.Context, req structs.RequestListTenant) ([]structs.BulkTenantResult, error) {
+	req.Status = structs.TenantStatusActive
+	return tu.bulk(ctx, req, func(tenantID string) error {
+		return tu.SuspendTenant(ctx, tenantID)
+	})
+}
+
+func (tu *TenantUsecase) BulkResume(ctx context.Context, req structs.RequestListTenant) ([]structs.BulkTenantResult, error) {
+	req.Status = structs.TenantStatusSuspended
+	return tu.bulk(ctx, req, func(tenantID string) error {
+		return tu.ResumeTenant(ctx, tenantID)
+	})
+}
+
+// bulk applies fn to each matched tenant and reports the outcome per tenant
+// instead of stopping at the first failure.
+func (tu *TenantUsecase) bulk(ctx context.Context, req structs.RequestListTenant, fn func(tenantID string) error) ([]structs.BulkTenantResult, error) {
+	tenants, err := tu.repository.ListTenants(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]structs.BulkTenantResult, 0, len(tenants))
+	for _, tenant := range tenants {
+		result := structs.BulkTenantResult{TenantID: tenant.ID.String(), Success: true}
+		if err := fn(result.TenantID); err != nil {
+			result.Success = false
+			result.Error = err.Error()
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
@@ -0,0 +1,185 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"multi-tenant-service/package/structs"
+	"time"
+)
+
+// ListMessagePartitions returns the time sub-partitions of a tenant's
+// messages.
+func (tu *TenantUsecase) ListMessagePartitions(ctx context.Context, tenantID string) ([]structs.MessagePartition, error) {
+	if _, err := tu.repository.GetTenant(ctx, tenantID); err != nil {
+		return nil, err
+	}
+	return tu.repository.ListMessagePartitions(ctx, tenantID)
+}
+
+// RunPartitionMaintenance keeps the sub-partitions of the tenants owned by
+// this process created ahead of time and applies their retention, until ctx
+// is cancelled.
+func (tu *TenantUsecase) RunPartitionMaintenance(ctx context.Context) {
+	ticker := time.NewTicker(tu.cfg.Partitions.MaintenanceInterval)
+	defer ticker.Stop()
+
+	for {
+		tu.maintainPartitions(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (tu *TenantUsecase) maintainPartitions(ctx context.Context) {
+	tenants, err := tu.repository.ListTenants(ctx, structs.RequestListTenant{})
+	if err != nil {
+		log.Printf("Failed to list tenants for partition maintenance: %v", err)
+		return
+	}
+
+	for _, tenant := range tenants {
+		tenantID := tenant.ID.String()
+		if !tu.owns(tenantID) || tenant.Status == structs.TenantStatusDeleting {
+			continue
+		}
+		if err := tu.ensurePartitions(ctx, &tenant); err != nil {
+			log.Printf("Failed to create partitions for tenant %s: %v", tenantID, err)
+		}
+		if err := tu.applyRetention(ctx, &tenant); err != nil {
+			log.Printf("Failed to apply retention for tenant %s: %v", tenantID, err)
+		}
+	}
+}
+
+// ensurePartitions creates the sub-partitions of a tenant from the end of
+// the newest one up to partitions.premake periods past the current one. A
+// partition from before sub-partitioning is converted first.
+func (tu *TenantUsecase) ensurePartitions(ctx context.Context, tenant *structs.Tenant) error {
+	tenantID := tenant.ID.String()
+	interval := tu.partitionInterval(tenant)
+	now := time.Now()
+
+	partitions, err := tu.repository.ListMessagePartitions(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if len(partitions) == 0 {
+		// A partitioned tenant partition always has its default
+		// sub-partition, so this one is a plain table or missing
+		converted, err := tu.repository.SubPartitionTenantPartition(ctx, tenantID, periodStart(now, interval))
+		if err != nil || !converted {
+			return err
+		}
+		log.Printf("Sub-partitioned messages of tenant %s", tenantID)
+		if partitions, err = tu.repository.ListMessagePartitions(ctx, tenantID); err != nil {
+			return err
+		}
+	}
+
+	// Continue after the newest range, so a gap left while nothing ran is
+	// filled and its messages leave the default sub-partition
+	var next time.Time
+	for _, partition := range partitions {
+		if partition.To != nil && partition.To.After(next) {
+			next = *partition.To
+		}
+	}
+	if next.IsZero() {
+		next = periodStart(now, interval)
+	}
+
+	end := periodStart(now, interval)
+	for i := 0; i <= tu.cfg.Partitions.Premake; i++ {
+		end = nextPeriod(end, interval)
+	}
+	for next.Before(end) {
+		to := nextPeriod(periodStart(next, interval), interval)
+		if err := tu.repository.CreateMessagePartition(ctx, tenantID, next, to); err != nil {
+			return err
+		}
+		next = to
+	}
+	return nil
+}
+
+// applyRetention drops or detaches the sub-partitions of a tenant whose
+// messages are all older than its retention, archiving them first when
+// retention asks for it. The default sub-partition is never removed.
+func (tu *TenantUsecase) applyRetention(ctx context.Context, tenant *structs.Tenant) error {
+	retention := tenant.Settings.Retention
+	if retention.Days <= 0 {
+		return nil
+	}
+
+	tenantID := tenant.ID.String()
+	partitions, err := tu.repository.ListMessagePartitions(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -retention.Days)
+	for _, partition := range partitions {
+		if partition.Default || partition.To == nil || partition.To.After(cutoff) {
+			continue
+		}
+		// Archived partitions are detached first and dropped once archived
+		if retention.Action == structs.RetentionActionDetach || retention.Archive {
+			err = tu.repository.DetachMessagePartition(ctx, tenantID, partition.Name)
+		} else {
+			err = tu.repository.DropMessagePartition(ctx, partition.Name)
+		}
+		if err != nil {
+			return err
+		}
+		log.Printf("Retention removed partition %s of tenant %s", partition.Name, tenantID)
+	}
+
+	if retention.Archive {
+		return tu.archiveExpiredPartitions(ctx, tenant)
+	}
+	return nil
+}
+
+func (tu *TenantUsecase) partitionInterval(tenant *structs.Tenant) string {
+	if tenant.Settings.Partitioning.Interval != "" {
+		return tenant.Settings.Partitioning.Interval
+	}
+	return tu.cfg.Partitions.Interval
+}
+
+func validPartitionSettings(settings structs.TenantSettings) error {
+	switch settings.Partitioning.Interval {
+	case "", structs.PartitionIntervalDaily, structs.PartitionIntervalMonthly:
+	default:
+		return fmt.Errorf("partition interval must be daily or monthly")
+	}
+	switch settings.Retention.Action {
+	case "", structs.RetentionActionDrop, structs.RetentionActionDetach:
+	default:
+		return fmt.Errorf("retention action must be drop or detach")
+	}
+	return nil
+}
+
+// periodStart returns the start of the UTC day or month t is in.
+func periodStart(t time.Time, interval string) time.Time {
+	t = t.UTC()
+	if interval == structs.PartitionIntervalMonthly {
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// nextPeriod returns the start of the day or month after the one starting
+// at start.
+func nextPeriod(start time.Time, interval string) time.Time {
+	if interval == structs.PartitionIntervalMonthly {
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
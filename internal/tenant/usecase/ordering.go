@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"hash/fnv"
+	"multi-tenant-service/package/broker"
+	"multi-tenant-service/package/processor"
+	"time"
+)
+
+// dispatchOrdered hands a delivery with an ordering key to its lane. Keys are
+// hashed onto one lane per prefetch slot, so a key always lands on the same
+// lane and a lane processes one message at a time. Lanes still take a slot
+// from the worker pool per message, which keeps the tenant's worker count
+// the bound on concurrency. The lane buffers hold at most prefetch messages
+// in total, so sending never blocks the consume loop.
+func (tu *TenantUsecase) dispatchOrdered(ctx context.Context, tenantID string, consumer *TenantConsumer, key string, msg broker.Delivery) {
+	if consumer.lanes == nil {
+		consumer.lanes = make([]chan broker.Delivery, max(consumer.Prefetch, 1))
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	index := int(h.Sum32() % uint32(len(consumer.lanes)))
+
+	if consumer.lanes[index] == nil {
+		consumer.lanes[index] = make(chan broker.Delivery, len(consumer.lanes))
+		go tu.runLane(ctx, tenantID, consumer, consumer.lanes[index])
+	}
+	consumer.lanes[index] <- msg
+}
+
+func (tu *TenantUsecase) runLane(ctx context.Context, tenantID string, consumer *TenantConsumer, lane chan broker.Delivery) {
+	requeue := func(msg broker.Delivery) {
+		if consumer.untrack(msg.DeliveryTag) {
+			msg.Nack(true)
+		}
+		consumer.inflight.Done()
+	}
+
+	for {
+		select {
+		case msg := <-lane:
+			if stopping(consumer) || !consumer.WorkerPool.Acquire(consumer.StopChan) {
+				requeue(msg)
+				continue
+			}
+			tu.processOrdered(ctx, tenantID, consumer, msg)
+			consumer.WorkerPool.Release()
+			consumer.inflight.Done()
+
+		case <-consumer.done:
+			// The consume loop has exited, so nothing more is sent
+			for {
+				select {
+				case msg := <-lane:
+					requeue(msg)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// processOrdered processes a keyed delivery, retrying in place so that a
+// failing message does not let later messages with the same key overtake it.
+// Once the attempts run out it is settled like any other message. A consumer
+// that stops meanwhile requeues it rather than retrying it through the queue.
+func (tu *TenantUsecase) processOrdered(ctx context.Context, tenantID string, consumer *TenantConsumer, msg broker.Delivery) {
+	attempt := deliveryAttempt(msg)
+	for {
+		err := tu.processMessage(ctx, tenantID, consumer, msg)
+		if processor.OutcomeOf(err) != processor.OutcomeRetry || attempt >= tu.maxDeliveryAttempts() {
+			tu.handleDelivery(ctx, tenantID, consumer, msg, err)
+			return
+		}
+
+		select {
+		case <-time.After(time.Duration(attempt) * time.Second):
+			consumer.Stats.recordResult(err)
+		case <-consumer.StopChan:
+			// Hand the message back instead of holding up the drain. A requeue
+			// keeps its position, ahead of the later messages with the same
+			// key, where a republished retry would go to the back of the queue
+			if consumer.untrack(msg.DeliveryTag) {
+				consumer.Stats.recordResult(err)
+				msg.Nack(true)
+			}
+			return
+		}
+
+		attempt++
+		headers := make(map[string]interface{}, len(msg.Headers)+1)
+		for k, v := range msg.Headers {
+			headers[k] = v
+		}
+		headers[headerAttempts] = int64(attempt)
+		msg.Headers = headers
+	}
+}
+
+func stopping(consumer *TenantConsumer) bool {
+	select {
+	case <-consumer.StopChan:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,35 @@
+package usecase
+
+import "hash/fnv"
+
+// Ownership decides which tenants the consumers of this process serve.
+type Ownership interface {
+	Owns(tenantID string) bool
+}
+
+// ConsumeAll runs consumers for every tenant, as a single combined process does.
+type ConsumeAll struct{}
+
+func (ConsumeAll) Owns(string) bool { return true }
+
+// ConsumeNone runs no consumers, for API-only processes.
+type ConsumeNone struct{}
+
+func (ConsumeNone) Owns(string) bool { return false }
+
+// HashShard splits tenants across a fixed number of worker replicas by
+// hashing the tenant ID. Replica Index of Count owns a tenant when the hash
+// falls into its shard, so every tenant is consumed by exactly one replica.
+type HashShard struct {
+	Index int
+	Count int
+}
+
+func (s HashShard) Owns(tenantID string) bool {
+	if s.Count <= 1 {
+		return true
+	}
+	h := fnv.New32a()
+	h.Write([]byte(tenantID))
+	return int(h.Sum32()%uint32(s.Count)) == s.Index
+}
@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"multi-tenant-service/package/broker"
+	"multi-tenant-service/package/processor"
+	"multi-tenant-service/package/rules"
+	"multi-tenant-service/package/structs"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	headerAttempts = "x-attempts"
+	headerError    = "x-error"
+
+	// Retries wait retryBaseDelay, doubling with every attempt up to
+	// maxRetryDelay, so a failing stage does not spin on the queue.
+	retryBaseDelay = time.Second
+	maxRetryDelay  = 5 * time.Minute
+)
+
+// defaultPipeline keeps the behaviour of tenants without a pipeline.
+var defaultPipeline = []structs.PipelineStage{{Type: "persist"}}
+
+func deadLetterQueue(tenantID string) string {
+	return fmt.Sprintf("tenant_%s_dlq", tenantID)
+}
+
+// persistMessage is the persist stage: it stores the payload in the tenant's
+// messages partition. Replayed messages are stored already and are skipped.
+func (tu *TenantUsecase) persistMessage(ctx context.Context, msg *processor.Message) error {
+	if msg.Replayed {
+		return nil
+	}
+	tenantID, err := uuid.Parse(msg.TenantID)
+	if err != nil {
+		return processor.DeadLetter(err)
+	}
+	return tu.msgRepo.InsertMessage(ctx, structs.CreateMessageRequest{
+		TenantID: tenantID,
+		Payload:  msg.Payload,
+	})
+}
+
+// buildPipeline builds the tenant pipeline, led by the tenant rules when
+// they run at consume time.
+func (tu *TenantUsecase) buildPipeline(settings *structs.TenantSettings) (processor.Pipeline, error) {
+	stages := settings.Pipeline
+	if len(stages) == 0 {
+		stages = defaultPipeline
+	}
+	pipeline, err := tu.processors.Build(stages)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pipeline: %w", err)
+	}
+
+	if len(settings.Rules) > 0 && settings.RulesPhase == rules.PhaseConsume {
+		engine, err := rules.New(settings.Rules)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rules: %w", err)
+		}
+		stage := processor.Stage{Name: "rules", Processor: processor.Func(func(ctx context.Context, msg *processor.Message) error {
+			return tu.applyRules(ctx, engine, msg)
+		})}
+		pipeline = append(processor.Pipeline{stage}, pipeline...)
+	}
+	return pipeline, nil
+}
+
+// applyRules is the rules stage of a consume-time pipeline. Routed copies are
+// only sent on the first attempt so retries do not duplicate them.
+func (tu *TenantUsecase) applyRules(ctx context.Context, engine *rules.Engine, msg *processor.Message) error {
+	result, err := engine.Apply(msg.Payload, msg.Headers)
+	if err != nil {
+		return processor.DeadLetter(err)
+	}
+	msg.Payload, msg.Headers = result.Payload, result.Headers
+
+	if msg.Attempt <= 1 {
+		tenantID, err := uuid.Parse(msg.TenantID)
+		if err != nil {
+			return processor.DeadLetter(err)
+		}
+		body, err := json.Marshal(structs.CreateMessageRequest{TenantID: tenantID, Payload: msg.Payload})
+		if err != nil {
+			return processor.DeadLetter(err)
+		}
+		for _, subscription := range result.Routes {
+			queueName := rules.SubscriptionQueue(msg.TenantID, subscription)
+			if _, err := tu.mq.DeclareQueue(ctx, queueName, broker.QueueOptions{}); err != nil {
+				return err
+			}
+			if err := tu.mq.Publish(ctx, queueName, broker.Message{
+				ContentType: "application/json",
+				Body:        body,
+				Headers:     msg.Headers,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if result.Dropped {
+		return processor.ErrDrop
+	}
+	return nil
+}
+
+// storeSettings hands new settings and the pipeline built from them to a
+// running consumer. A pipeline that fails to build leaves the previous one
+// in place.
+func (tu *TenantUsecase) storeSettings(tenantID string, consumer *TenantConsumer, settings *structs.TenantSettings) {
+	consumer.Settings.Store(settings)
+	pipeline, err := tu.buildPipeline(settings)
+	if err != nil {
+		log.Printf("Keeping previous pipeline for tenant %s: %v", tenantID, err)
+		return
+	}
+	consumer.Pipeline.Store(&pipeline)
+}
+
+func (tu *TenantUsecase) maxDeliveryAttempts() int {
+	if tu.cfg.Tenant.MaxDeliveryAttempts <= 0 {
+		return 5
+	}
+	return tu.cfg.Tenant.MaxDeliveryAttempts
+}
+
+// settleMessage acks, retries or dead-letters a delivery according to the
+// pipeline error. Retries are published again with the attempt count in a
+// header and delayed by retryDelay; once the attempts run out the message is
+// dead-lettered.
+func (tu *TenantUsecase) settleMessage(ctx context.Context, tenantID string, msg broker.Delivery, err error) {
+	outcome := processor.OutcomeOf(err)
+	attempt := deliveryAttempt(msg)
+	if outcome == processor.OutcomeRetry && attempt >= tu.maxDeliveryAttempts() {
+		outcome = processor.OutcomeDeadLetter
+	}
+	if err != nil {
+		log.Printf("Failed to process message for tenant %s (attempt %d, %s): %v", tenantID, attempt, outcome, err)
+	}
+
+	var queueName string
+	republished := msg.Message
+	republished.Headers = make(map[string]interface{}, len(msg.Headers)+2)
+	for key, value := range msg.Headers {
+		republished.Headers[key] = value
+	}
+
+	switch outcome {
+	case processor.OutcomeAck:
+		msg.Ack()
+		return
+	case processor.OutcomeRetry:
+		queueName = fmt.Sprintf("tenant_%s_queue", tenantID)
+		republished.Headers[headerAttempts] = int64(attempt + 1)
+		republished.Delay = retryDelay(attempt)
+	case processor.OutcomeDeadLetter:
+		queueName = deadLetterQueue(tenantID)
+		republished.Headers[headerAttempts] = int64(attempt)
+		republished.Headers[headerError] = err.Error()
+		if msg.ReplyTo != "" {
+			tu.sendReply(ctx, tenantID, msg, structs.MessageReply{Error: err.Error()})
+		}
+	}
+
+	if err := tu.mq.Publish(context.WithoutCancel(ctx), queueName, republished); err != nil {
+		log.Printf("Failed to publish message to %s, requeueing: %v", queueName, err)
+		msg.Nack(true)
+		return
+	}
+	msg.Ack()
+}
+
+// retryDelay is how long the retry after the given attempt waits.
+func retryDelay(attempt int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+// deliveryAttempt returns which delivery of the message this is, counting
+// from 1. Brokers decode the header into different integer types.
+func deliveryAttempt(msg broker.Delivery) int {
+	switch v := msg.Headers[headerAttempts].(type) {
+	case int:
+		return v
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case float64:
+		return int(v)
+	}
+	return 1
+}
+
+// deliveryReplayed reports whether a replay job published the message.
+func deliveryReplayed(msg broker.Delivery) bool {
+	replayed, _ := msg.Headers[structs.HeaderReplayed].(bool)
+	return replayed
+}
+
+// sendReply answers a request on the reply queue it names. A reply that
+// cannot be sent is only logged; the requester then times out.
+func (tu *TenantUsecase) sendReply(ctx context.Context, tenantID string, msg broker.Delivery, reply structs.MessageReply) {
+	reply.CorrelationID = msg.CorrelationID
+	body, err := json.Marshal(reply)
+	if err != nil {
+		log.Printf("Failed to marshal reply for tenant %s: %v", tenantID, err)
+		return
+	}
+	err = tu.mq.Publish(context.WithoutCancel(ctx), msg.ReplyTo, broker.Message{
+		ContentType:   "application/json",
+		Body:          body,
+		CorrelationID: msg.CorrelationID,
+	})
+	if err != nil {
+		log.Printf("Failed to send reply for tenant %s to %s: %v", tenantID, msg.ReplyTo, err)
+	}
+}
@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"multi-tenant-service/package/structs"
+	"sync/atomic"
+	"time"
+)
+
+func (tu *TenantUsecase) SetOwnership(ownership Ownership) {
+	tu.mu.Lock()
+	tu.ownership = ownership
+	tu.mu.Unlock()
+}
+
+// EnableLeases makes this process coordinate tenant ownership with the other
+// replicas through leases, identified by replicaID.
+func (tu *TenantUsecase) EnableLeases(replicaID string) {
+	tu.SetOwnership(NewLeaseOwnership(tu.repository, replicaID, tu.leaseTTL()))
+}
+
+func (tu *TenantUsecase) leaseTTL() time.Duration {
+	if tu.cfg.Worker.LeaseTTL <= 0 {
+		return 90 * time.Second
+	}
+	return tu.cfg.Worker.LeaseTTL
+}
+
+// leases returns the lease ownership in use, or nil when tenants are
+// assigned without coordination.
+func (tu *TenantUsecase) leases() *LeaseOwnership {
+	tu.mu.RLock()
+	defer tu.mu.RUnlock()
+	leases, _ := tu.ownership.(*LeaseOwnership)
+	return leases
+}
+
+func (tu *TenantUsecase) owns(tenantID string) bool {
+	tu.mu.RLock()
+	defer tu.mu.RUnlock()
+	return tu.ownership.Owns(tenantID)
+}
+
+// RunConsumers keeps the consumers of this process in line with the active
+// tenants it owns until ctx is cancelled. Tenants created, suspended or
+// deleted, and consumers paused or restarted, through another process are
+// picked up on the next pass, which also reports the consumer statuses.
+func (tu *TenantUsecase) RunConsumers(ctx context.Context) {
+	interval := tu.cfg.Worker.ReconcileInterval
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := tu.reconcileConsumers(ctx); err != nil {
+			log.Printf("Failed to reconcile consumers: %v", err)
+		}
+		if err := tu.resumeReplayJobs(ctx); err != nil {
+			log.Printf("Failed to resume replay jobs: %v", err)
+		}
+
+		// Reconcile again when a held lease expires without being renewed,
+		// which stops its consumer
+		var expired <-chan time.Time
+		if leases := tu.leases(); leases != nil {
+			if next, ok := leases.NextExpiry(); ok {
+				expired = time.After(time.Until(next))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-expired:
+		}
+	}
+}
+
+func (tu *TenantUsecase) reconcileConsumers(ctx context.Context) error {
+	tenants, err := tu.repository.ListTenants(ctx, structs.RequestListTenant{Status: structs.TenantStatusActive})
+	if err != nil {
+		// Consumers whose lease expired meanwhile still have to stop
+		tu.stopUnownedConsumers(ctx)
+		return err
+	}
+
+	leases := tu.leases()
+	var leaseErr error
+	if leases != nil {
+		tenantIDs := make([]string, len(tenants))
+		for i := range tenants {
+			tenantIDs[i] = tenants[i].ID.String()
+		}
+		// Without renewal the held leases stay owned until they expire;
+		// consumers of expired ones are still stopped below
+		leaseErr = leases.Acquire(ctx, tenantIDs)
+	}
+
+	owned := make(map[string]*structs.Tenant)
+	wanted := make(map[string]bool)
+	for i := range tenants {
+		tenant := &tenants[i]
+		tenantID := tenant.ID.String()
+		if !tu.owns(tenantID) {
+			continue
+		}
+		owned[tenantID] = tenant
+		if tenant.Settings.Delivery == structs.DeliveryPull || tenant.ConsumerPaused {
+			continue
+		}
+		wanted[tenantID] = true
+
+		tu.applyRequestedRestart(ctx, tenant)
+		if err := tu.startTenantConsumer(ctx, tenant); err != nil {
+			log.Printf("Failed to start consumer for tenant %s: %v", tenantID, err)
+			continue
+		}
+		if err := tu.syncTenantConsumer(ctx, tenant); err != nil {
+			log.Printf("Failed to update consumer for tenant %s: %v", tenantID, err)
+		}
+	}
+
+	tu.releaseConsumers(ctx, func(tenantID string) bool { return !wanted[tenantID] })
+	tu.reportConsumerStatuses(ctx, owned)
+
+	if leaseErr != nil {
+		return leaseErr
+	}
+	if leases != nil {
+		// Leases of tenants still draining are kept until a later pass
+		return leases.Release(ctx, tu.isDraining)
+	}
+	return nil
+}
+
+// stopUnownedConsumers stops the consumers of tenants this process no
+// longer owns, such as tenants whose lease expired.
+func (tu *TenantUsecase) stopUnownedConsumers(ctx context.Context) {
+	tu.releaseConsumers(ctx, func(tenantID string) bool { return !tu.ownership.Owns(tenantID) })
+}
+
+// releaseConsumers drains in the background the consumers release selects;
+// release is called with tu.mu held.
+// Their workers finish before the tenant's lease is handed back, so the next
+// owner does not get the requeued messages while they still run here.
+func (tu *TenantUsecase) releaseConsumers(ctx context.Context, release func(tenantID string) bool) {
+	tu.mu.Lock()
+	released := make(map[string]*TenantConsumer)
+	for tenantID, consumer := range tu.consumers {
+		if release(tenantID) {
+			released[tenantID] = consumer
+			delete(tu.consumers, tenantID)
+		}
+	}
+	tu.mu.Unlock()
+
+	for tenantID, consumer := range released {
+		log.Printf("Releasing consumer for tenant %s", tenantID)
+		tu.drainInBackground(ctx, tenantID, consumer)
+	}
+}
+
+// applyRequestedRestart drains the tenant's consumer when a restart was
+// requested after it started, so the reconcile pass starts a new one.
+func (tu *TenantUsecase) applyRequestedRestart(ctx context.Context, tenant *structs.Tenant) {
+	if tenant.ConsumerRestartAt == nil {
+		return
+	}
+	tenantID := tenant.ID.String()
+	tu.mu.Lock()
+	consumer, running := tu.consumers[tenantID]
+	if !running || !consumer.startedAt.Before(*tenant.ConsumerRestartAt) {
+		tu.mu.Unlock()
+		return
+	}
+	delete(tu.consumers, tenantID)
+	tu.mu.Unlock()
+
+	log.Printf("Restarting consumer for tenant %s", tenantID)
+	tu.drainInBackground(ctx, tenantID, consumer)
+}
+
+// syncTenantConsumer applies settings and concurrency changed through another
+// process to the running consumer.
+func (tu *TenantUsecase) syncTenantConsumer(ctx context.Context, tenant *structs.Tenant) error {
+	tenantID := tenant.ID.String()
+	tu.mu.RLock()
+	consumer, exists := tu.consumers[tenantID]
+	tu.mu.RUnlock()
+	if !exists {
+		return nil
+	}
+
+	tu.storeSettings(tenantID, consumer, &tenant.Settings)
+	if atomic.LoadInt64(&consumer.Workers) == int64(tenant.ConcurrencyConfig) {
+		return nil
+	}
+	return tu.resizeTenantConsumer(ctx, tenantID, tenant.ConcurrencyConfig)
+}
+
+func (tu *TenantUsecase) ListAssignments(ctx context.Context) (*structs.TenantAssignment, error) {
+	replicas, err := tu.repository.ListLiveReplicas(ctx, tu.leaseTTL())
+	if err != nil {
+		return nil, err
+	}
+	leases, err := tu.repository.ListLeases(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &structs.TenantAssignment{Replicas: replicas, Leases: leases}, nil
+}
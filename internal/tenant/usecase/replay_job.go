@@ -0,0 +1,404 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	rm "multi-tenant-service/internal/message/repository"
+	"multi-tenant-service/package/broker"
+	"multi-tenant-service/package/processor"
+	"multi-tenant-service/package/rules"
+	"multi-tenant-service/package/structs"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	replayBatchSize    = 100
+	maxReplayRateLimit = 10000
+	maxReplayAttempts  = 5
+	replayRetryDelay   = time.Second
+	replayJobsLimit    = 50
+)
+
+// replayPublisher sends one stored message to the target of a replay job.
+type replayPublisher func(ctx context.Context, msg structs.Message) error
+
+// CreateReplayJob records a replay of the tenant's stored messages and starts
+// it when this process owns the tenant. Otherwise the owning process picks
+// it up on its next reconcile pass.
+func (tu *TenantUsecase) CreateReplayJob(ctx context.Context, tenantID string, req structs.CreateReplayJobRequest) (*structs.ReplayJob, error) {
+	tenant, err := tu.repository.GetTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case tenant.ID == uuid.Nil || tenant.Status == structs.TenantStatusDeleted:
+		return nil, fmt.Errorf("tenant not found")
+	case tenant.Status == structs.TenantStatusDeleting:
+		return nil, fmt.Errorf("tenant is being deleted")
+	}
+
+	if req.Target.Type == "" {
+		req.Target.Type = structs.ReplayTargetQueue
+	}
+	if req.To.IsZero() {
+		req.To = time.Now()
+	}
+	if !req.From.Before(req.To) {
+		return nil, fmt.Errorf("from must be before to")
+	}
+	if req.RateLimit < 0 || req.RateLimit > maxReplayRateLimit {
+		return nil, fmt.Errorf("rate_limit must be between 0 and %d", maxReplayRateLimit)
+	}
+	if req.Filter != "" {
+		if _, err := rules.Compile(req.Filter); err != nil {
+			return nil, fmt.Errorf("invalid filter: %v", err)
+		}
+	}
+	if err := tu.validateReplayTarget(req.Target); err != nil {
+		return nil, err
+	}
+
+	total, err := tu.msgRepo.CountMessagesBetween(ctx, tenant.ID, req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+	job, err := tu.repository.CreateReplayJob(ctx, structs.ReplayJob{
+		ID:        uuid.New(),
+		TenantID:  tenant.ID,
+		Status:    structs.ReplayStatusPending,
+		From:      req.From,
+		To:        req.To,
+		Filter:    req.Filter,
+		Target:    req.Target,
+		RateLimit: req.RateLimit,
+		Total:     total,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if tu.owns(tenantID) {
+		// The job outlives the request, until Shutdown stops it
+		tu.startReplayJob(context.WithoutCancel(ctx), *job)
+	}
+	return job, nil
+}
+
+func (tu *TenantUsecase) GetReplayJob(ctx context.Context, tenantID, jobID string) (*structs.ReplayJob, error) {
+	return tu.repository.GetReplayJob(ctx, tenantID, jobID)
+}
+
+// ListReplayJobs returns the latest replay jobs of a tenant.
+func (tu *TenantUsecase) ListReplayJobs(ctx context.Context, tenantID string) ([]structs.ReplayJob, error) {
+	return tu.repository.ListReplayJobs(ctx, tenantID, replayJobsLimit)
+}
+
+// CancelReplayJob stops a pending or running job. Messages already replayed
+// stay published.
+func (tu *TenantUsecase) CancelReplayJob(ctx context.Context, tenantID, jobID string) (*structs.ReplayJob, error) {
+	job, err := tu.repository.GetReplayJob(ctx, tenantID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job.Finished() {
+		return nil, fmt.Errorf("replay job already finished")
+	}
+	cancelled, err := tu.repository.FinishReplayJob(ctx, jobID, structs.ReplayStatusCancelled, "")
+	if err != nil {
+		return nil, err
+	}
+	if !cancelled {
+		return nil, fmt.Errorf("replay job already finished")
+	}
+
+	// A job running in another process stops at its next progress update
+	tu.mu.RLock()
+	cancel := tu.replays[jobID]
+	tu.mu.RUnlock()
+	if cancel != nil {
+		cancel()
+	}
+	return tu.repository.GetReplayJob(ctx, tenantID, jobID)
+}
+
+// resumeReplayJobs starts the unfinished jobs of the tenants this process
+// owns: new jobs created by another process, and jobs interrupted by a
+// restart, which continue after the last message they recorded.
+func (tu *TenantUsecase) resumeReplayJobs(ctx context.Context) error {
+	jobs, err := tu.repository.ListUnfinishedReplayJobs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		if tu.owns(job.TenantID.String()) {
+			tu.startReplayJob(ctx, job)
+		}
+	}
+	return nil
+}
+
+// startReplayJob runs a job in the background unless it is already running
+// in this process. Shutdown interrupts the job without finishing it and
+// waits for it to record its progress, so it resumes on the next start.
+func (tu *TenantUsecase) startReplayJob(ctx context.Context, job structs.ReplayJob) {
+	jobID := job.ID.String()
+	tu.mu.Lock()
+	defer tu.mu.Unlock()
+	if _, running := tu.replays[jobID]; running {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	started := tu.goBackground(ctx, func(ctx context.Context) {
+		defer func() {
+			tu.mu.Lock()
+			delete(tu.replays, jobID)
+			tu.mu.Unlock()
+			cancel()
+		}()
+		tu.runReplayJob(ctx, job)
+	})
+	if !started {
+		cancel()
+		return
+	}
+	tu.replays[jobID] = cancel
+}
+
+// runReplayJob reads the job's time range in keyset order and publishes
+// every message that passes the filter, recording the cursor after each
+// batch. When ctx is cancelled it stops after recording its progress.
+func (tu *TenantUsecase) runReplayJob(ctx context.Context, job structs.ReplayJob) {
+	jobID := job.ID.String()
+	fail := func(err error) {
+		log.Printf("Replay job %s of tenant %s failed: %v", jobID, job.TenantID, err)
+		if _, ferr := tu.repository.FinishReplayJob(context.WithoutCancel(ctx), jobID, structs.ReplayStatusFailed, err.Error()); ferr != nil {
+			log.Printf("Failed to record failure of replay job %s: %v", jobID, ferr)
+		}
+	}
+
+	var filter *rules.Expr
+	if job.Filter != "" {
+		var err error
+		if filter, err = rules.Compile(job.Filter); err != nil {
+			fail(fmt.Errorf("invalid filter: %v", err))
+			return
+		}
+	}
+	publish, err := tu.newReplayPublisher(ctx, job)
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	cursor := structs.MessageCursor{CreatedAt: job.From}
+	if job.Cursor != nil {
+		cursor = *job.Cursor
+	}
+	batchSize := replayBatchSize
+	var limiter <-chan time.Time
+	if job.RateLimit > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(job.RateLimit))
+		defer ticker.Stop()
+		limiter = ticker.C
+		// Keeps progress, and so cancellation, checked about once a second
+		batchSize = min(batchSize, job.RateLimit)
+	}
+
+	// Marks the job running, unless it was cancelled before it started
+	status, err := tu.repository.UpdateReplayProgress(ctx, jobID, cursor, 0, 0, 0)
+	if err != nil {
+		log.Printf("Failed to start replay job %s: %v", jobID, err)
+		return
+	}
+	if status != structs.ReplayStatusRunning {
+		return
+	}
+	log.Printf("Replaying messages of tenant %s (job %s)", job.TenantID, jobID)
+
+	// The job is done after a second pass, which picks up messages that
+	// committed late during the first
+	tail := rm.NewMessageTail(tu.msgRepo, job.TenantID, cursor)
+	passes := 0
+	for {
+		var messages []structs.Message
+		var caughtUp bool
+		err := retryReplay(ctx, func() error {
+			var err error
+			messages, caughtUp, err = tail.Next(ctx, batchSize)
+			return err
+		})
+		if err != nil {
+			if ctx.Err() == nil {
+				fail(err)
+			}
+			return
+		}
+
+		var scanned, replayed, failed int64
+		var publishErr error
+		for _, msg := range messages {
+			if !msg.CreatedAt.Before(job.To) {
+				continue
+			}
+			if filter == nil || filter.Match(map[string]interface{}{
+				"payload": msg.Payload,
+				"headers": map[string]interface{}{},
+			}) {
+				if limiter != nil {
+					select {
+					case <-limiter:
+					case <-ctx.Done():
+					}
+				}
+				if ctx.Err() != nil {
+					break
+				}
+				err := retryReplay(ctx, func() error { return publish(ctx, msg) })
+				if err != nil && processor.OutcomeOf(err) == processor.OutcomeDeadLetter {
+					log.Printf("Replay job %s skipped message %s: %v", jobID, msg.ID, err)
+					failed++
+				} else if err != nil {
+					publishErr = err
+					break
+				} else {
+					replayed++
+				}
+			}
+			scanned++
+			if position := (structs.MessageCursor{CreatedAt: msg.CreatedAt, ID: msg.ID}); cursor.Before(position) {
+				cursor = position
+			}
+		}
+		if !caughtUp && !tail.Cursor().CreatedAt.Before(job.To) {
+			tail.Rescan()
+			caughtUp = true
+		}
+		if caughtUp {
+			passes++
+		}
+		done := passes == 2
+
+		status, err := tu.repository.UpdateReplayProgress(context.WithoutCancel(ctx), jobID, cursor, scanned, replayed, failed)
+		if err != nil {
+			// The job stays running and resumes from its last recorded cursor
+			log.Printf("Failed to record progress of replay job %s: %v", jobID, err)
+			return
+		}
+		switch {
+		case status != structs.ReplayStatusRunning:
+			log.Printf("Replay job %s of tenant %s is %s", jobID, job.TenantID, status)
+			return
+		case ctx.Err() != nil:
+			return
+		case publishErr != nil:
+			fail(publishErr)
+			return
+		case done:
+			if _, err := tu.repository.FinishReplayJob(ctx, jobID, structs.ReplayStatusCompleted, ""); err != nil {
+				log.Printf("Failed to complete replay job %s: %v", jobID, err)
+			}
+			log.Printf("Replay job %s of tenant %s completed", jobID, job.TenantID)
+			return
+		}
+	}
+}
+
+// retryReplay retries fn on errors that are not marked permanent.
+func retryReplay(ctx context.Context, fn func() error) error {
+	var err error
+	for attempt := 1; attempt <= maxReplayAttempts; attempt++ {
+		if err = fn(); err == nil || processor.OutcomeOf(err) == processor.OutcomeDeadLetter {
+			return err
+		}
+		if attempt < maxReplayAttempts {
+			select {
+			case <-time.After(time.Duration(attempt) * replayRetryDelay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	return err
+}
+
+func (tu *TenantUsecase) validateReplayTarget(target structs.ReplayTarget) error {
+	switch target.Type {
+	case structs.ReplayTargetQueue:
+		return nil
+	case structs.ReplayTargetSubscription:
+		if !rules.ValidSubscription(target.Subscription) {
+			return fmt.Errorf("invalid subscription %q", target.Subscription)
+		}
+		return nil
+	case structs.ReplayTargetWebhook:
+		if _, err := tu.newReplayWebhook(target, ""); err != nil {
+			return fmt.Errorf("invalid webhook: %v", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("target must be queue, subscription or webhook")
+}
+
+// newReplayPublisher returns the function that sends messages to the job's
+// target. Queued copies carry the replay headers; webhooks get them as HTTP
+// headers.
+func (tu *TenantUsecase) newReplayPublisher(ctx context.Context, job structs.ReplayJob) (replayPublisher, error) {
+	if job.Target.Type == structs.ReplayTargetWebhook {
+		webhook, err := tu.newReplayWebhook(job.Target, job.ID.String())
+		if err != nil {
+			return nil, processor.DeadLetter(fmt.Errorf("invalid webhook: %w", err))
+		}
+		return func(ctx context.Context, msg structs.Message) error {
+			return webhook.Process(ctx, &processor.Message{
+				TenantID: msg.TenantID.String(),
+				Payload:  msg.Payload,
+				Attempt:  1,
+			})
+		}, nil
+	}
+
+	queueName := fmt.Sprintf("tenant_%s_queue", job.TenantID)
+	if job.Target.Type == structs.ReplayTargetSubscription {
+		queueName = rules.SubscriptionQueue(job.TenantID.String(), job.Target.Subscription)
+	}
+	if _, err := tu.mq.DeclareQueue(ctx, queueName, broker.QueueOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to declare queue: %w", err)
+	}
+	return func(ctx context.Context, msg structs.Message) error {
+		body, err := json.Marshal(structs.CreateMessageRequest{TenantID: msg.TenantID, Payload: msg.Payload})
+		if err != nil {
+			return processor.DeadLetter(fmt.Errorf("failed to marshal message: %w", err))
+		}
+		return tu.mq.Publish(ctx, queueName, broker.Message{
+			ContentType: "application/json",
+			Body:        body,
+			Headers: map[string]interface{}{
+				structs.HeaderReplayed:  true,
+				structs.HeaderReplayJob: job.ID.String(),
+			},
+		})
+	}, nil
+}
+
+// newReplayWebhook builds the webhook stage of the registry, so replay targets
+// are held to the same address policy as pipeline webhooks.
+func (tu *TenantUsecase) newReplayWebhook(target structs.ReplayTarget, jobID string) (processor.Processor, error) {
+	headers := make(map[string]string, len(target.Headers)+2)
+	for key, value := range target.Headers {
+		headers[key] = value
+	}
+	headers["X-Replayed"] = "true"
+	if jobID != "" {
+		headers["X-Replay-Job"] = jobID
+	}
+	config, err := json.Marshal(map[string]interface{}{"url": target.URL, "headers": headers})
+	if err != nil {
+		return nil, err
+	}
+	return tu.processors.New("webhook", config)
+}
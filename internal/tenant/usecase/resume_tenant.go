@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"multi-tenant-service/package/structs"
+)
+
+// ResumeTenant restarts consumption with the stored concurrency_config.
+func (tu *TenantUsecase) ResumeTenant(ctx context.Context, tenantID string) error {
+	resumed, err := tu.repository.TransitionTenantStatus(ctx, tenantID, structs.TenantStatusSuspended, structs.TenantStatusActive)
+	if err != nil {
+		return err
+	}
+	if !resumed {
+		// Tell a missing tenant apart from one in another status
+		if _, err := tu.repository.GetTenant(ctx, tenantID); err != nil {
+			return err
+		}
+		return fmt.Errorf("tenant is not suspended")
+	}
+
+	tenant, err := tu.repository.GetTenant(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	if tu.owns(tenantID) {
+		if err := tu.startTenantConsumer(ctx, tenant); err != nil {
+			// Back to suspended rather than active without a consumer
+			if _, rerr := tu.repository.TransitionTenantStatus(ctx, tenantID, structs.TenantStatusActive, structs.TenantStatusSuspended); rerr != nil {
+				log.Printf("Failed to suspend tenant %s again: %v", tenantID, rerr)
+			}
+			return fmt.Errorf("failed to start consumer: %w", err)
+		}
+	}
+
+	log.Printf("Resumed tenant %s with %d workers", tenantID, tenant.ConcurrencyConfig)
+	return nil
+}
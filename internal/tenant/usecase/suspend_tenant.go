@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"multi-tenant-service/package/structs"
+)
+
+// SuspendTenant stops the tenant consumer but keeps its queue, so messages
+// published while suspended stay in RabbitMQ until the tenant is resumed.
+func (tu *TenantUsecase) SuspendTenant(ctx context.Context, tenantID string) error {
+	suspended, err := tu.repository.TransitionTenantStatus(ctx, tenantID, structs.TenantStatusActive, structs.TenantStatusSuspended)
+	if err != nil {
+		return err
+	}
+	if !suspended {
+		// Tell a missing tenant apart from one in another status
+		if _, err := tu.repository.GetTenant(ctx, tenantID); err != nil {
+			return err
+		}
+		return fmt.Errorf("tenant is not active")
+	}
+
+	tu.releaseConsumer(ctx, tenantID)
+
+	log.Printf("Suspended tenant %s", tenantID)
+	return nil
+}
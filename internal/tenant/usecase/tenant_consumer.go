@@ -5,64 +5,96 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"multi-tenant-service/package/broker"
+	"multi-tenant-service/package/processor"
 	"multi-tenant-service/package/structs"
-
-	amqp "github.com/rabbitmq/amqp091-go"
+	"sync"
+	"time"
 )
 
-func (tu *TenantUsecase) startTenantConsumer(ctx context.Context, tenantID string, workers int) error {
-	channelName := fmt.Sprintf("tenant_%s", tenantID)
+func (tu *TenantUsecase) startTenantConsumer(ctx context.Context, tenant *structs.Tenant) error {
+	tenantID := tenant.ID.String()
+	workers := tenant.ConcurrencyConfig
 	queueName := fmt.Sprintf("tenant_%s_queue", tenantID)
 
-	// Create channel
-	ch, err := tu.mqClient.CreateChannel(channelName)
-	if err != nil {
-		return err
+	// Pull-mode tenants consume their queue through the pull API
+	if tenant.Settings.Delivery == structs.DeliveryPull {
+		return nil
+	}
+
+	// Serialize starts so the API and the reconciler never subscribe twice
+	tu.startMu.Lock()
+	defer tu.startMu.Unlock()
+
+	// A consumer still draining holds on to its workers; the next reconcile
+	// pass starts the new one once it is done
+	tu.mu.RLock()
+	_, running := tu.consumers[tenantID]
+	draining := tu.draining[tenantID] > 0
+	tu.mu.RUnlock()
+	if running || draining || tenant.ConsumerPaused || tu.isStopping() {
+		return nil
 	}
 
 	// Declare queue
-	_, err = tu.mqClient.DeclareQueue(ch, queueName)
+	if _, err := tu.mq.DeclareQueue(ctx, queueName, broker.QueueOptions{}); err != nil {
+		return err
+	}
+	if _, err := tu.mq.DeclareQueue(ctx, deadLetterQueue(tenantID), broker.QueueOptions{}); err != nil {
+		return err
+	}
+
+	pipeline, err := tu.buildPipeline(&tenant.Settings)
 	if err != nil {
 		return err
 	}
 
+	// Prefetch enough for the autoscaler to grow the pool without resubscribing
+	prefetch := workers
+	if autoscale := tenant.Settings.Autoscale; autoscale.Enabled && autoscale.MaxWorkers > prefetch {
+		prefetch = autoscale.MaxWorkers
+	}
+
+	sub, err := tu.mq.Consume(ctx, queueName, broker.ConsumeOptions{
+		Tag:      fmt.Sprintf("consumer_%s", tenantID),
+		Prefetch: prefetch,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start consuming: %w", err)
+	}
+
+	// The consumer outlives the request that started it
+	consumeCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+
 	// Create consumer
 	consumer := &TenantConsumer{
-		Channel:    ch,
-		StopChan:   make(chan bool),
+		Subscription: sub,
+		StopChan:     make(chan bool),
 		Workers:    int64(workers),
-		WorkerPool: make(chan struct{}, workers),
-	}
-
-	// Initialize worker pool
-	for i := 0; i < workers; i++ {
-		consumer.WorkerPool <- struct{}{}
+		WorkerPool: NewWorkerPool(workers),
+		Prefetch:   prefetch,
+		cancel:     cancel,
+		done:       make(chan struct{}),
+		pending:    make(map[uint64]broker.Delivery),
+		Stats:      tu.stats(tenantID),
+		startedAt:  time.Now(),
 	}
+	consumer.Settings.Store(&tenant.Settings)
+	consumer.Pipeline.Store(&pipeline)
 
 	tu.mu.Lock()
 	tu.consumers[tenantID] = consumer
 	tu.mu.Unlock()
 
 	// Start consuming messages
-	go tu.consumeMessages(ctx, tenantID, consumer, queueName)
+	go tu.consumeMessages(consumeCtx, tenantID, consumer)
 
 	return nil
 }
 
-func (tm *TenantUsecase) consumeMessages(ctx context.Context, tenantID string, consumer *TenantConsumer, queueName string) {
-	msgs, err := consumer.Channel.Consume(
-		queueName,
-		fmt.Sprintf("consumer_%s", tenantID),
-		false, // auto-ack
-		false, // exclusive
-		false, // no-local
-		false, // no-wait
-		nil,   // args
-	)
-	if err != nil {
-		log.Printf("Failed to start consuming for tenant %s: %v", tenantID, err)
-		return
-	}
+func (tm *TenantUsecase) consumeMessages(ctx context.Context, tenantID string, consumer *TenantConsumer) {
+	msgs := consumer.Subscription.Deliveries()
+	defer close(consumer.done)
 
 	for {
 		select {
@@ -75,49 +107,252 @@ func (tm *TenantUsecase) consumeMessages(ctx context.Context, tenantID string, c
 				return
 			}
 
+			consumer.Stats.recordDelivery()
+
+			// Messages with an ordering key wait for their lane instead
+			if key, _ := msg.Headers[structs.HeaderOrderingKey].(string); key != "" {
+				consumer.inflight.Add(1)
+				consumer.track(msg)
+				tm.dispatchOrdered(ctx, tenantID, consumer, key, msg)
+				continue
+			}
+
 			// Get worker from pool
-			<-consumer.WorkerPool
+			if !consumer.WorkerPool.Acquire(consumer.StopChan) {
+				msg.Nack(true)
+				log.Printf("Stopping consumer for tenant %s", tenantID)
+				return
+			}
+
+			consumer.inflight.Add(1)
+			consumer.track(msg)
 
 			// Process message in goroutine
-			go func(msg amqp.Delivery) {
-				defer func() {
-					consumer.WorkerPool <- struct{}{} // Return worker to pool
-				}()
-
-				if err := tm.processMessage(ctx, tenantID, msg); err != nil {
-					log.Printf("Failed to process message for tenant %s: %v", tenantID, err)
-					msg.Nack(false, true) // Requeue message
-				} else {
-					msg.Ack(false)
-				}
+			go func(msg broker.Delivery) {
+				defer consumer.inflight.Done()
+				defer consumer.WorkerPool.Release() // Return worker to pool
+
+				tm.handleDelivery(ctx, tenantID, consumer, msg, tm.processMessage(ctx, tenantID, consumer, msg))
 			}(msg)
 		}
 	}
 }
 
-func (tu *TenantUsecase) processMessage(ctx context.Context, tenantID string, msg amqp.Delivery) error {
+// handleDelivery records the result of processing a delivery and settles it.
+func (tu *TenantUsecase) handleDelivery(ctx context.Context, tenantID string, consumer *TenantConsumer, msg broker.Delivery, err error) {
+	if !consumer.untrack(msg.DeliveryTag) {
+		// Already requeued by a shutdown that gave up waiting
+		return
+	}
+	if processor.OutcomeOf(err) == processor.OutcomeAck {
+		consumer.Stats.recordResult(nil)
+	} else {
+		consumer.Stats.recordResult(err)
+	}
+	tu.settleMessage(ctx, tenantID, msg, err)
+}
+
+// processMessage decodes a delivery and runs it through the tenant pipeline.
+func (tu *TenantUsecase) processMessage(ctx context.Context, tenantID string, consumer *TenantConsumer, msg broker.Delivery) error {
 	// Parse message
 	var messageReq structs.CreateMessageRequest
 	if err := json.Unmarshal(msg.Body, &messageReq); err != nil {
-		return fmt.Errorf("failed to unmarshal message: %w", err)
+		return processor.DeadLetter(fmt.Errorf("failed to unmarshal message: %w", err))
+	}
+
+	// Apply tenant defaults for fields the publisher left out
+	for key, value := range consumer.Settings.Load().Defaults {
+		if messageReq.Payload == nil {
+			messageReq.Payload = map[string]interface{}{}
+		}
+		if _, ok := messageReq.Payload[key]; !ok {
+			messageReq.Payload[key] = value
+		}
+	}
+
+	// Stages get their own headers, so what rules set there does not follow
+	// the delivery into a retry
+	headers := make(map[string]interface{}, len(msg.Headers))
+	for key, value := range msg.Headers {
+		headers[key] = value
+	}
+
+	pipeline := *consumer.Pipeline.Load()
+	processed := &processor.Message{
+		TenantID: tenantID,
+		Payload:  messageReq.Payload,
+		Headers:  headers,
+		Attempt:  deliveryAttempt(msg),
+		Replayed: deliveryReplayed(msg),
+	}
+	if err := pipeline.Process(ctx, processed); err != nil {
+		return err
+	}
+	if msg.ReplyTo != "" {
+		reply := processed.Reply
+		if reply == nil {
+			reply = processed.Payload
+		}
+		tu.sendReply(ctx, tenantID, msg, structs.MessageReply{Payload: reply})
 	}
-	tu.msgRepo.InsertMessage(ctx, messageReq)
 	log.Printf("Processed message for tenant %s", tenantID)
 	return nil
 }
 
+// releaseConsumer stops the tenant consumer without touching the queue. It
+// is drained in the background, so its in-flight messages finish or are
+// requeued, and Shutdown waits for it.
+func (tu *TenantUsecase) releaseConsumer(ctx context.Context, tenantID string) {
+	tu.mu.Lock()
+	consumer, exists := tu.consumers[tenantID]
+	delete(tu.consumers, tenantID)
+	tu.mu.Unlock()
+	if exists {
+		tu.drainInBackground(ctx, tenantID, consumer)
+	}
+}
+
+func (c *TenantConsumer) track(msg broker.Delivery) {
+	c.pendingMu.Lock()
+	c.pending[msg.DeliveryTag] = msg
+	c.pendingMu.Unlock()
+}
+
+// untrack reports whether the delivery was still pending, so exactly one of
+// the worker and requeuePending settles it.
+func (c *TenantConsumer) untrack(tag uint64) bool {
+	c.pendingMu.Lock()
+	defer c.pendingMu.Unlock()
+	_, ok := c.pending[tag]
+	delete(c.pending, tag)
+	return ok
+}
+
+func (c *TenantConsumer) requeuePending() int {
+	c.pendingMu.Lock()
+	pending := c.pending
+	c.pending = make(map[uint64]broker.Delivery)
+	c.pendingMu.Unlock()
+
+	for _, msg := range pending {
+		msg.Nack(true)
+	}
+	return len(pending)
+}
+
+// drainTenantConsumer stops taking deliveries and waits until ctx is done for
+// in-flight messages to be acked. Messages still running at the deadline are
+// requeued before the channel is closed.
+func (tu *TenantUsecase) drainTenantConsumer(ctx context.Context, tenantID string, consumer *TenantConsumer) {
+	defer tu.markDraining(tenantID)()
+
+	if err := consumer.Subscription.Cancel(); err != nil {
+		log.Printf("Failed to cancel consumer for tenant %s: %v", tenantID, err)
+	}
+	close(consumer.StopChan)
+
+	drained := make(chan struct{})
+	go func() {
+		<-consumer.done
+		consumer.inflight.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		count := consumer.requeuePending()
+		log.Printf("Requeued %d unfinished messages for tenant %s", count, tenantID)
+	}
+
+	consumer.cancel()
+	if err := consumer.Subscription.Close(); err != nil {
+		log.Printf("Failed to close consumer for tenant %s: %v", tenantID, err)
+	}
+}
+
+// drainInBackground drains a consumer the caller already removed from
+// tu.consumers without holding up the caller. Shutdown waits for the drain
+// rather than cutting it short, and once Shutdown has begun it drains before
+// returning, as Shutdown no longer sees the consumer.
+func (tu *TenantUsecase) drainInBackground(ctx context.Context, tenantID string, consumer *TenantConsumer) {
+	// Marked right away, so nothing starts in its place before the drain runs
+	done := tu.markDraining(tenantID)
+	drain := func(ctx context.Context) {
+		defer done()
+		drainCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), tu.cfg.Server.ShutdownTimeout)
+		defer cancel()
+		tu.drainTenantConsumer(drainCtx, tenantID, consumer)
+	}
+	if !tu.goBackground(context.WithoutCancel(ctx), drain) {
+		drain(ctx)
+	}
+}
+
+// markDraining counts a consumer of the tenant as draining until the
+// returned function is called. Meanwhile no new consumer starts for the
+// tenant and its lease is not handed back, so the old workers never overlap
+// the next consumer, here or on another replica. Callers must not hold tu.mu.
+func (tu *TenantUsecase) markDraining(tenantID string) func() {
+	tu.mu.Lock()
+	tu.draining[tenantID]++
+	tu.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			tu.mu.Lock()
+			defer tu.mu.Unlock()
+			if tu.draining[tenantID]--; tu.draining[tenantID] <= 0 {
+				delete(tu.draining, tenantID)
+			}
+		})
+	}
+}
+
+// isDraining reports whether a consumer of the tenant is still draining.
+func (tu *TenantUsecase) isDraining(tenantID string) bool {
+	tu.mu.RLock()
+	defer tu.mu.RUnlock()
+	return tu.draining[tenantID] > 0
+}
+
+// Shutdown stops the reconciler and the background jobs, drains every
+// consumer in parallel, giving in-flight messages until the ctx deadline to
+// finish, and then withdraws its consumer statuses and hands back tenant
+// leases. Nothing started by the usecase is running once it returns.
 func (tm *TenantUsecase) Shutdown(ctx context.Context) error {
+	// Stopped first so no consumer is started after the snapshot below
+	if err := tm.stopBackground(ctx); err != nil {
+		log.Printf("Failed to stop background jobs: %v", err)
+	}
+
 	tm.mu.Lock()
-	defer tm.mu.Unlock()
+	consumers := tm.consumers
+	tm.consumers = make(map[string]*TenantConsumer)
+	ownership := tm.ownership
+	tm.mu.Unlock()
 
 	log.Println("Shutting down tenant consumers...")
 
-	for tenantID, consumer := range tm.consumers {
-		log.Printf("Stopping consumer for tenant %s", tenantID)
-		close(consumer.StopChan)
-		tm.mqClient.CloseChannel(fmt.Sprintf("tenant_%s", tenantID))
+	var wg sync.WaitGroup
+	for tenantID, consumer := range consumers {
+		wg.Add(1)
+		go func(tenantID string, consumer *TenantConsumer) {
+			defer wg.Done()
+			log.Printf("Stopping consumer for tenant %s", tenantID)
+			tm.drainTenantConsumer(ctx, tenantID, consumer)
+		}(tenantID, consumer)
 	}
+	wg.Wait()
 
-	tm.consumers = make(map[string]*TenantConsumer)
+	// The drain may have used up ctx; statuses and leases still need
+	// handing back
+	leaveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	tm.reportConsumerStatuses(leaveCtx, nil)
+	if leases, ok := ownership.(*LeaseOwnership); ok {
+		return leases.Leave(leaveCtx)
+	}
 	return nil
 }
\ No newline at end of file
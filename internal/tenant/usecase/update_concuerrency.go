@@ -2,24 +2,59 @@ package usecase
 
 import (
 	"context"
+	"log"
 	"sync/atomic"
 )
 
+// UpdateTenantConcurrency stores the new worker count and returns without
+// waiting for the consumer. A consumer of this process is resized in the
+// background, since growing it may have to drain it first; consumers of
+// other processes pick the count up on their next reconcile pass.
 func (tu *TenantUsecase) UpdateTenantConcurrency(ctx context.Context, tenantID string, workers int) error {
-	tu.mu.Lock()
-	defer tu.mu.Unlock()
-
 	if err := tu.repository.UpdateTenantConcurrency(ctx, tenantID, workers); err != nil {
 		return err
 	}
 
-	// Update consumer if exists
-	if consumer, exists := tu.consumers[tenantID]; exists {
-		atomic.StoreInt64(&consumer.Workers, int64(workers))
-		consumer.WorkerPool = make(chan struct{}, workers)
-		for i := 0; i < workers; i++ {
-			consumer.WorkerPool <- struct{}{}
+	tu.goBackground(context.WithoutCancel(ctx), func(ctx context.Context) {
+		if err := tu.resizeTenantConsumer(ctx, tenantID, workers); err != nil {
+			log.Printf("Failed to resize consumer for tenant %s: %v", tenantID, err)
 		}
-	}
+	})
 	return nil
-}
\ No newline at end of file
+}
+
+// resizeTenantConsumer applies a new worker count to the running consumer.
+// The pool is resized in place; growing past the prefetch the subscription
+// was opened with drains it and subscribes again.
+func (tu *TenantUsecase) resizeTenantConsumer(ctx context.Context, tenantID string, workers int) error {
+	tu.mu.Lock()
+	consumer, exists := tu.consumers[tenantID]
+	if !exists {
+		tu.mu.Unlock()
+		return nil
+	}
+	atomic.StoreInt64(&consumer.Workers, int64(workers))
+	consumer.WorkerPool.Resize(workers)
+	if workers <= consumer.Prefetch {
+		tu.mu.Unlock()
+		return nil
+	}
+	delete(tu.consumers, tenantID)
+	tu.mu.Unlock()
+
+	return tu.restartTenantConsumer(ctx, tenantID, consumer)
+}
+
+// restartTenantConsumer drains a consumer the caller already removed from
+// tu.consumers and starts a new one from the stored tenant.
+func (tu *TenantUsecase) restartTenantConsumer(ctx context.Context, tenantID string, consumer *TenantConsumer) error {
+	drainCtx, cancel := context.WithTimeout(ctx, tu.cfg.Server.ShutdownTimeout)
+	tu.drainTenantConsumer(drainCtx, tenantID, consumer)
+	cancel()
+
+	tenant, err := tu.repository.GetTenant(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	return tu.startTenantConsumer(ctx, tenant)
+}
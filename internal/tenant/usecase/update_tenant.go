@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"multi-tenant-service/package/rules"
+	"multi-tenant-service/package/structs"
+	"strings"
+	"time"
+)
+
+// UpdateTenant applies a partial update. ifMatch is the updated_at the client
+// last saw; the update fails if the tenant has changed since. The new settings
+// are handed to the running consumer without restarting it.
+func (tu *TenantUsecase) UpdateTenant(ctx context.Context, tenantID string, req structs.UpdateTenantRequest, ifMatch *time.Time) (*structs.Tenant, error) {
+	tenant, err := tu.repository.GetTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	if ifMatch != nil && !tenant.UpdatedAt.Equal(*ifMatch) {
+		return nil, fmt.Errorf("tenant was modified")
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, fmt.Errorf("name must not be empty")
+		}
+		tenant.Name = name
+	}
+	if req.Labels != nil {
+		tenant.Labels = *req.Labels
+	}
+	if req.Settings != nil {
+		if err := tu.validateSettings(*req.Settings); err != nil {
+			return nil, err
+		}
+		tenant.Settings = *req.Settings
+	}
+
+	updated, err := tu.repository.UpdateTenant(ctx, *tenant, &tenant.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	tu.mu.RLock()
+	if consumer, exists := tu.consumers[tenantID]; exists {
+		tu.storeSettings(tenantID, consumer, &updated.Settings)
+	}
+	tu.mu.RUnlock()
+
+	// Switching the delivery mode stops or starts the consumer. A stopped
+	// consumer finishes its in-flight messages first.
+	if updated.Settings.Delivery == structs.DeliveryPull {
+		tu.mu.Lock()
+		consumer, exists := tu.consumers[tenantID]
+		delete(tu.consumers, tenantID)
+		tu.mu.Unlock()
+		if exists {
+			tu.drainInBackground(ctx, tenantID, consumer)
+		}
+	} else if updated.Status == structs.TenantStatusActive && tu.owns(tenantID) {
+		if err := tu.startTenantConsumer(ctx, updated); err != nil {
+			log.Printf("Failed to start consumer for tenant %s: %v", tenantID, err)
+		}
+	}
+
+	log.Printf("Updated tenant %s", tenantID)
+	return updated, nil
+}
+
+
+func (tu *TenantUsecase) validateSettings(settings structs.TenantSettings) error {
+	if settings.Retention.Days < 0 || settings.Limits.MaxPayloadBytes < 0 {
+		return fmt.Errorf("settings must not be negative")
+	}
+	if autoscale := settings.Autoscale; autoscale.Enabled &&
+		(autoscale.MinWorkers < 1 || autoscale.MaxWorkers < autoscale.MinWorkers) {
+		return fmt.Errorf("autoscale bounds are invalid")
+	}
+	if err := validPartitionSettings(settings); err != nil {
+		return err
+	}
+	if settings.Delivery != "" && settings.Delivery != structs.DeliveryPush && settings.Delivery != structs.DeliveryPull {
+		return fmt.Errorf("delivery must be push or pull")
+	}
+	if !rules.ValidPhase(settings.RulesPhase) {
+		return fmt.Errorf("invalid rules: unknown phase %q", settings.RulesPhase)
+	}
+	if _, err := rules.New(settings.Rules); err != nil {
+		return fmt.Errorf("invalid rules: %w", err)
+	}
+	if _, err := tu.buildPipeline(&settings); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DryRunRules shows what the rules do to a sample message without publishing
+// it. The request's rules are used when given, otherwise the tenant's.
+func (tu *TenantUsecase) DryRunRules(ctx context.Context, tenantID string, req structs.RulesDryRunRequest) (*rules.Result, error) {
+	tenant, err := tu.repository.GetTenant(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	defs := tenant.Settings.Rules
+	if req.Rules != nil {
+		defs = *req.Rules
+	}
+	engine, err := rules.New(defs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid rules: %w", err)
+	}
+	return engine.Apply(req.Payload, req.Headers)
+}
\ No newline at end of file
@@ -3,46 +3,134 @@ package usecase
 import (
 	"context"
 	"multi-tenant-service/internal/tenant/repository"
-	rabbitmq "multi-tenant-service/package/rabbit-mq"
+	"multi-tenant-service/package/archive"
+	"multi-tenant-service/package/config"
+	"multi-tenant-service/package/broker"
+	"multi-tenant-service/package/processor"
+	"multi-tenant-service/package/rules"
 	"multi-tenant-service/package/structs"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	rm "multi-tenant-service/internal/message/repository"
-
-	amqp "github.com/rabbitmq/amqp091-go"
 )
 
 type TenantUsecase struct {
 	repository repository.ITenantRepository
 	msgRepo    rm.IMessageRepository
-	mqClient *rabbitmq.Client
+	mq         broker.Broker
+	cfg       *config.Config
+	processors *processor.Registry
+	archives   archive.Store
 	consumers map[string]*TenantConsumer
+	// draining counts the consumers of each tenant still being drained
+	draining  map[string]int
+	deletions map[string]bool
+	// replays cancels the replay jobs running in this process, by job ID
+	replays map[string]context.CancelFunc
+	ownership Ownership
 	mu        sync.RWMutex
+	startMu   sync.Mutex
+
+	statsMu       sync.Mutex
+	consumerStats map[string]*ConsumerStats
+
+	// bg tracks the goroutines started through goBackground; bgCtx is
+	// cancelled and stopping set when Shutdown begins
+	bgMu     sync.Mutex
+	bg       sync.WaitGroup
+	bgCtx    context.Context
+	bgCancel context.CancelFunc
+	stopping bool
 }
 
 type TenantConsumer struct {
-	Channel    *amqp.Channel
+	Subscription broker.Subscription
 	StopChan   chan bool
 	Workers    int64
-	WorkerPool chan struct{}
+	WorkerPool *WorkerPool
+	// Prefetch is the broker prefetch the subscription was opened with; the
+	// pool cannot usefully grow beyond it without resubscribing.
+	Prefetch int
+	// Settings is swapped when the tenant is updated so changes apply live
+	Settings atomic.Pointer[structs.TenantSettings]
+	// Pipeline is rebuilt from Settings.Pipeline whenever Settings changes
+	Pipeline atomic.Pointer[processor.Pipeline]
+	Stats    *ConsumerStats
+	// startedAt is compared with restarts requested on the tenant
+	startedAt time.Time
+
+	// cancel aborts in-flight processing; done is closed when the consume
+	// loop has exited, after which inflight only goes down.
+	cancel    context.CancelFunc
+	done      chan struct{}
+	inflight  sync.WaitGroup
+	pendingMu sync.Mutex
+	pending   map[uint64]broker.Delivery
+	// lanes serialize messages sharing an ordering key; only the consume
+	// loop touches the slice
+	lanes []chan broker.Delivery
 }
 
 
 type ITenantUsecase interface {
 	CreateTenant(ctx context.Context, req structs.CreateTenantRequest) (*structs.Tenant, error) 
-	DeleteTenant(ctx context.Context, tenantID string) error
+	DeleteTenant(ctx context.Context, tenantID string) (*structs.Tenant, error)
+	RestoreTenant(ctx context.Context, tenantID string) (*structs.Tenant, error)
+	GetDeletionJob(ctx context.Context, tenantID string) (*structs.TenantDeletionJob, error)
+	CreateReplayJob(ctx context.Context, tenantID string, req structs.CreateReplayJobRequest) (*structs.ReplayJob, error)
+	GetReplayJob(ctx context.Context, tenantID, jobID string) (*structs.ReplayJob, error)
+	ListReplayJobs(ctx context.Context, tenantID string) ([]structs.ReplayJob, error)
+	CancelReplayJob(ctx context.Context, tenantID, jobID string) (*structs.ReplayJob, error)
+	ResumeDeletionJobs(ctx context.Context) error
+	ListMessagePartitions(ctx context.Context, tenantID string) ([]structs.MessagePartition, error)
+	ListDetachedMessagePartitions(ctx context.Context, tenantID string) ([]structs.MessagePartition, error)
+	ArchiveMessagePartition(ctx context.Context, tenantID, table string) (*archive.Manifest, error)
+	RestoreMessagePartition(ctx context.Context, tenantID, table, as string, attach bool) (*archive.Manifest, error)
+	SetOwnership(ownership Ownership)
+	EnableLeases(replicaID string)
+	Start(ctx context.Context)
+	ListScalingEvents(ctx context.Context, tenantID string, limit int) ([]structs.TenantScalingEvent, error)
+	ListAssignments(ctx context.Context) (*structs.TenantAssignment, error)
+	Shutdown(ctx context.Context) error
 	GetTenant(ctx context.Context, tenantID string) (*structs.Tenant, error)
+	UpdateTenant(ctx context.Context, tenantID string, req structs.UpdateTenantRequest, ifMatch *time.Time) (*structs.Tenant, error)
+	DryRunRules(ctx context.Context, tenantID string, req structs.RulesDryRunRequest) (*rules.Result, error)
+	GetConsumerStatus(ctx context.Context, tenantID string) (*structs.ConsumerStatus, error)
+	RestartConsumer(ctx context.Context, tenantID string) error
+	PauseConsumer(ctx context.Context, tenantID string) error
+	ResumeConsumer(ctx context.Context, tenantID string) error
 	UpdateTenantConcurrency(ctx context.Context, tenantID string, workers int) error
-	// ListTenant(req structs.RequestListTenant) (structs.ResponseListTenant, error)
+	SuspendTenant(ctx context.Context, tenantID string) error
+	ResumeTenant(ctx context.Context, tenantID string) error
+	ListTenant(ctx context.Context, req structs.RequestListTenant) ([]structs.Tenant, error)
+	BulkUpdateConcurrency(ctx context.Context, req structs.RequestListTenant, workers int) ([]structs.BulkTenantResult, error)
+	BulkSuspend(ctx context.Context, req structs.RequestListTenant) ([]structs.BulkTenantResult, error)
+	BulkResume(ctx context.Context, req structs.RequestListTenant) ([]structs.BulkTenantResult, error)
 }
 
 
+// NewTenantUsecase registers the persist stage in processors, so tenant
+// pipelines can be built from it and from any stage added at startup.
 func NewTenantUsecase(tenantRepo repository.ITenantRepository,
-	msgRepo rm.IMessageRepository, mqClient *rabbitmq.Client) ITenantUsecase {
-	return &TenantUsecase{
+	msgRepo rm.IMessageRepository, mq broker.Broker, cfg *config.Config,
+	processors *processor.Registry, archives archive.Store) ITenantUsecase {
+	tu := &TenantUsecase{
 		repository: tenantRepo,
 		msgRepo   : msgRepo,
-		mqClient  : mqClient,
+		mq        : mq,
+		cfg       : cfg,
+		processors: processors,
+		archives:   archives,
 		consumers: make(map[string]*TenantConsumer),
+		draining:  make(map[string]int),
+		deletions: make(map[string]bool),
+		replays:   make(map[string]context.CancelFunc),
+		consumerStats: make(map[string]*ConsumerStats),
+		ownership: ConsumeAll{},
 	}
+	tu.bgCtx, tu.bgCancel = context.WithCancel(context.Background())
+	processors.RegisterHandler("persist", processor.Func(tu.persistMessage))
+	return tu
 }
\ No newline at end of file
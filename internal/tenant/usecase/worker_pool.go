@@ -0,0 +1,70 @@
+package usecase
+
+import "sync"
+
+// WorkerPool bounds how many messages of a tenant are processed at once.
+// Unlike a buffered channel it can be resized while workers hold slots.
+type WorkerPool struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	size   int
+	active int
+}
+
+func NewWorkerPool(size int) *WorkerPool {
+	p := &WorkerPool{size: size}
+	p.cond = sync.NewCond(&p.mu)
+	return p
+}
+
+// Acquire blocks until a slot is free. It returns false if stop is closed
+// first.
+func (p *WorkerPool) Acquire(stop <-chan bool) bool {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-stop:
+			p.mu.Lock()
+			p.cond.Broadcast()
+			p.mu.Unlock()
+		case <-done:
+		}
+	}()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for p.active >= p.size {
+		select {
+		case <-stop:
+			return false
+		default:
+		}
+		p.cond.Wait()
+	}
+	p.active++
+	return true
+}
+
+func (p *WorkerPool) Release() {
+	p.mu.Lock()
+	p.active--
+	p.mu.Unlock()
+	p.cond.Signal()
+}
+
+// Resize changes the number of slots. Shrinking lets running workers finish
+// and hands out no new slots until active drops below the new size.
+func (p *WorkerPool) Resize(size int) {
+	p.mu.Lock()
+	p.size = size
+	p.mu.Unlock()
+	p.cond.Broadcast()
+}
+
+// Usage returns the busy and total slot counts.
+func (p *WorkerPool) Usage() (active, size int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.active, p.size
+}
@@ -1,18 +1,25 @@
 package main
 
 import (
+	"fmt"
 	"log"
+	"multi-tenant-service/cmd/archive"
 	"multi-tenant-service/cmd/migrate"
 	rm "multi-tenant-service/internal/message/repository"
 	um "multi-tenant-service/internal/message/usecase"
 	"multi-tenant-service/internal/tenant/repository"
 	"multi-tenant-service/internal/tenant/usecase"
+	pkgarchive "multi-tenant-service/package/archive"
+	"multi-tenant-service/package/broker"
 	"multi-tenant-service/package/config"
 	"multi-tenant-service/package/connection/database"
+	"multi-tenant-service/package/notifier"
+	"multi-tenant-service/package/processor"
 	rabbitmq "multi-tenant-service/package/rabbit-mq"
 	"os"
 
 	api "multi-tenant-service/cmd/api"
+	"multi-tenant-service/cmd/worker"
 
 	"github.com/urfave/cli/v2"
 )
@@ -30,27 +37,110 @@ func main()  {
 		
 	}
 
-	mqClient, err := rabbitmq.NewClient(cfg.RabbitMQ.URL)
+	mq, err := newBroker(cfg, dbConn)
 	if err != nil {
-		log.Println("ERROR INIT RABBITMQ", err)
+		log.Fatalf("Failed to connect to broker: %v", err)
+	}
+
+	messageNotifier, err := newNotifier(cfg, dbConn)
+	if err != nil {
+		log.Fatalf("Failed to start message notifier: %v", err)
 	}
 
 	tenantRepo := repository.NewTenantRepository(dbConn)
-	messageRepo := rm.NewMessageRepository(dbConn)
+	messageRepo := rm.NewMessageRepository(dbConn, messageNotifier)
+
+	messageUsecase := um.NewMessageUsecase(messageRepo, tenantRepo, mq, cfg, messageNotifier)
+	// Custom pipeline stages are registered here before tenants are started
+	processors := processor.NewRegistry()
+	processors.Register("webhook", processor.NewWebhookFactory(processor.WebhookPolicy{
+		AllowedHosts: cfg.Webhooks.AllowedHosts,
+		AllowPrivate: cfg.Webhooks.AllowPrivate,
+	}))
+
+	archives, err := newArchiveStore(cfg)
+	if err != nil {
+		log.Fatalf("Failed to configure archive storage: %v", err)
+	}
 
-	messageUsecase := um.NewMessageUsecase(messageRepo, tenantRepo, mqClient)
-	tenantUsecase := usecase.NewTenantUsecase(tenantRepo, messageRepo, mqClient)
+	tenantUsecase := usecase.NewTenantUsecase(tenantRepo, messageRepo, mq, cfg, processors, archives)
 
 	cmds := []*cli.Command{}
 	cmds = append(cmds, api.ServeAPI(tenantUsecase, messageUsecase, cfg)...)
+	cmds = append(cmds, worker.NewWorker(tenantUsecase, cfg)...)
 	cmds = append(cmds, migrate.NewMigrate(cfg)...)
+	cmds = append(cmds, archive.NewArchive(tenantUsecase)...)
 
 	app := &cli.App{
 		Name:     "messaging-system",
 		Commands: cmds,
 	}
 
-	if err := app.Run(os.Args); err != nil {
+	// Commands return once their consumers are drained, so the broker and the
+	// DB pool are only closed after the last message is settled
+	err = app.Run(os.Args)
+	if closeErr := messageUsecase.Close(); closeErr != nil {
+		log.Printf("Failed to close message usecase: %v", closeErr)
+	}
+	if closeErr := messageNotifier.Close(); closeErr != nil {
+		log.Printf("Failed to close message notifier: %v", closeErr)
+	}
+	if closeErr := mq.Close(); closeErr != nil {
+		log.Printf("Failed to close broker: %v", closeErr)
+	}
+	if closeErr := dbConn.Close(); closeErr != nil {
+		log.Printf("Failed to close database: %v", closeErr)
+	}
+	if err != nil {
 		panic(err)
 	}
-}
\ No newline at end of file
+}
+
+// newBroker connects the queue backend selected in the config.
+func newBroker(cfg *config.Config, db *database.DB) (broker.Broker, error) {
+	switch cfg.Broker.Driver {
+	case "memory":
+		return broker.NewMemoryBroker(), nil
+	case "postgres":
+		return broker.NewPostgresBroker(db.DB, cfg.Database.URL, broker.PostgresOptions{
+			VisibilityTimeout: cfg.Broker.VisibilityTimeout,
+			PollInterval:      cfg.Broker.PollInterval,
+		})
+	case "", "rabbitmq":
+		client, err := rabbitmq.NewClient(cfg.RabbitMQ.URL)
+		if err != nil {
+			return nil, err
+		}
+		return rabbitmq.NewBroker(client), nil
+	}
+	return nil, fmt.Errorf("unknown broker driver %q", cfg.Broker.Driver)
+}
+
+
+// newNotifier creates the notifier that wakes message streams.
+func newNotifier(cfg *config.Config, db *database.DB) (notifier.Notifier, error) {
+	switch cfg.Stream.Notifier {
+	case "", "local":
+		return notifier.NewLocal(), nil
+	case "postgres":
+		return notifier.NewPostgres(db.DB, cfg.Database.URL)
+	}
+	return nil, fmt.Errorf("unknown stream notifier %q", cfg.Stream.Notifier)
+}
+
+// newArchiveStore creates the storage archived partitions are written to.
+func newArchiveStore(cfg *config.Config) (pkgarchive.Store, error) {
+	switch cfg.Archive.Storage {
+	case "", "local":
+		return pkgarchive.NewLocalStore(cfg.Archive.Directory), nil
+	case "s3":
+		return pkgarchive.NewS3Store(pkgarchive.S3Options{
+			Endpoint:  cfg.Archive.S3.Endpoint,
+			Region:    cfg.Archive.S3.Region,
+			Bucket:    cfg.Archive.S3.Bucket,
+			AccessKey: cfg.Archive.S3.AccessKey,
+			SecretKey: cfg.Archive.S3.SecretKey,
+		})
+	}
+	return nil, fmt.Errorf("unknown archive storage %q", cfg.Archive.Storage)
+}
@@ -21,8 +21,33 @@ var (
 		},
 		[]string{"method", "path"},
 	)
+
+	TenantScalingDecisionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "tenant_scaling_decisions_total",
+			Help: "Worker count changes made by the autoscaler",
+		},
+		[]string{"tenant_id", "direction", "reason"},
+	)
+
+	TenantWorkers = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "tenant_workers",
+			Help: "Current worker count of a tenant consumer",
+		},
+		[]string{"tenant_id"},
+	)
+
+	TenantQueueDepth = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "tenant_queue_depth",
+			Help: "Messages waiting in a tenant queue when last sampled",
+		},
+		[]string{"tenant_id"},
+	)
 )
 
 func Register() {
-	prometheus.MustRegister(HttpRequestsTotal, HttpRequestDuration)
+	prometheus.MustRegister(HttpRequestsTotal, HttpRequestDuration,
+		TenantScalingDecisionsTotal, TenantWorkers, TenantQueueDepth)
 }
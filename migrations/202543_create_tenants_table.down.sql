@@ -1 +1,4 @@
-DROP TABLE IF EXISTS tenants;
\ No newline at end of file
+-- This version shipped with only a down migration, which now lives in the
+-- migration before it. It is kept so databases already at this version can
+-- still be migrated, and does nothing.
+SELECT 1;
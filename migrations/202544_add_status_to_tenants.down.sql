@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tenants_status;
+
+ALTER TABLE tenants DROP COLUMN IF EXISTS status;
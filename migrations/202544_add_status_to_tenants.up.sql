@@ -0,0 +1,3 @@
+ALTER TABLE tenants ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'active';
+
+CREATE INDEX idx_tenants_status ON tenants (status);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS messages_archive;
+
+DROP TABLE IF EXISTS tenant_deletion_jobs;
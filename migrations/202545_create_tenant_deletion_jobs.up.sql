@@ -0,0 +1,21 @@
+CREATE TABLE tenant_deletion_jobs (
+    tenant_id UUID PRIMARY KEY,
+    step VARCHAR(32) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'running',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_tenant_deletion_jobs_status ON tenant_deletion_jobs (status);
+
+-- Messages of deleted tenants are kept here before their partition is dropped
+CREATE TABLE messages_archive (
+    id UUID NOT NULL,
+    tenant_id UUID NOT NULL,
+    payload JSONB,
+    created_at TIMESTAMPTZ,
+    archived_at TIMESTAMPTZ DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, id)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tenants_deleted_at;
+
+ALTER TABLE tenants DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,3 @@
+ALTER TABLE tenants ADD COLUMN deleted_at TIMESTAMPTZ;
+
+CREATE INDEX idx_tenants_deleted_at ON tenants (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE tenants ADD COLUMN labels JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE tenants ADD COLUMN settings JSONB NOT NULL DEFAULT '{}';
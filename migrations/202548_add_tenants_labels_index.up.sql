@@ -0,0 +1 @@
+CREATE INDEX idx_tenants_labels ON tenants USING GIN (labels);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS queue_jobs;
+
+DROP TABLE IF EXISTS queue_registry;
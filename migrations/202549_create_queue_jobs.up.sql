@@ -0,0 +1,20 @@
+-- Tables backing the postgres broker driver
+CREATE TABLE queue_registry (
+    name VARCHAR(255) PRIMARY KEY,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE TABLE queue_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    queue VARCHAR(255) NOT NULL REFERENCES queue_registry (name) ON DELETE CASCADE,
+    body BYTEA NOT NULL,
+    content_type VARCHAR(255) NOT NULL DEFAULT '',
+    headers JSONB NOT NULL DEFAULT '{}',
+    correlation_id VARCHAR(255) NOT NULL DEFAULT '',
+    reply_to VARCHAR(255) NOT NULL DEFAULT '',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    visible_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_queue_jobs_queue_visible_at ON queue_jobs (queue, visible_at, id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS tenant_leases;
+
+DROP TABLE IF EXISTS worker_replicas;
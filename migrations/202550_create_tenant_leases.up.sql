@@ -0,0 +1,14 @@
+CREATE TABLE worker_replicas (
+    id VARCHAR(255) PRIMARY KEY,
+    started_at TIMESTAMPTZ DEFAULT NOW(),
+    heartbeat_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE tenant_leases (
+    tenant_id UUID PRIMARY KEY,
+    owner VARCHAR(255) NOT NULL,
+    acquired_at TIMESTAMPTZ DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_tenant_leases_owner ON tenant_leases (owner);
@@ -0,0 +1,12 @@
+CREATE TABLE tenant_scaling_events (
+    id BIGSERIAL PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    from_workers INTEGER NOT NULL,
+    to_workers INTEGER NOT NULL,
+    queue_depth INTEGER NOT NULL,
+    utilization DOUBLE PRECISION NOT NULL,
+    reason VARCHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_tenant_scaling_events_tenant ON tenant_scaling_events (tenant_id, created_at DESC);
@@ -0,0 +1,9 @@
+-- Last message each gateway client acknowledged on a tenant stream
+CREATE TABLE gateway_cursors (
+    subject VARCHAR(255) NOT NULL,
+    tenant_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    message_id UUID NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (subject, tenant_id)
+);
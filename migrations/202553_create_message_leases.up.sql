@@ -0,0 +1,14 @@
+-- Messages leased through the pull API, taken off the tenant queue until
+-- they are acknowledged
+CREATE TABLE message_leases (
+    message_id UUID PRIMARY KEY,
+    receipt_handle UUID NOT NULL UNIQUE,
+    tenant_id UUID NOT NULL,
+    body BYTEA NOT NULL,
+    headers JSONB,
+    receive_count INTEGER NOT NULL DEFAULT 1,
+    visible_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW()
+);
+
+CREATE INDEX idx_message_leases_tenant_visible ON message_leases (tenant_id, visible_at);
@@ -0,0 +1,25 @@
+-- Jobs that republish stored messages of a tenant. The cursor is the keyset
+-- position of the last message read, so a resumed job continues after it.
+CREATE TABLE replay_jobs (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    from_time TIMESTAMPTZ NOT NULL,
+    to_time TIMESTAMPTZ NOT NULL,
+    filter TEXT NOT NULL DEFAULT '',
+    target JSONB NOT NULL,
+    rate_limit INTEGER NOT NULL DEFAULT 0,
+    cursor_created_at TIMESTAMPTZ,
+    cursor_id UUID,
+    total BIGINT NOT NULL DEFAULT 0,
+    scanned BIGINT NOT NULL DEFAULT 0,
+    replayed BIGINT NOT NULL DEFAULT 0,
+    failed BIGINT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW(),
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_replay_jobs_tenant_created ON replay_jobs (tenant_id, created_at DESC);
+CREATE INDEX idx_replay_jobs_status ON replay_jobs (status);
@@ -0,0 +1,13 @@
+-- Tenant partitions are range-partitioned by created_at, and every unique
+-- key of a partitioned table has to include its partition columns
+--
+-- The new key no longer keeps an id unique per tenant by itself: the same id
+-- could be stored twice with different created_at values. No index can
+-- enforce it either, since every unique index on a tenant partition has to
+-- include created_at as well. Published messages take their id from
+-- uuid_generate_v4(), so only inserts that keep the caller's ids can repeat
+-- one, and those check the tenant's ids before they store anything.
+UPDATE messages SET created_at = NOW() WHERE created_at IS NULL;
+ALTER TABLE messages ALTER COLUMN created_at SET NOT NULL;
+ALTER TABLE messages DROP CONSTRAINT messages_pkey;
+ALTER TABLE messages ADD PRIMARY KEY (tenant_id, id, created_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS tenant_consumer_status;
+ALTER TABLE tenants DROP COLUMN IF EXISTS consumer_restart_at;
+ALTER TABLE tenants DROP COLUMN IF EXISTS consumer_paused;
@@ -0,0 +1,21 @@
+-- Consumer pauses and restarts are requested on the tenant row and applied
+-- by the replica that owns the tenant on its next reconcile pass
+ALTER TABLE tenants ADD COLUMN consumer_paused BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE tenants ADD COLUMN consumer_restart_at TIMESTAMPTZ;
+
+-- The status of each tenant consumer as last reported by its owner, so any
+-- replica can answer status requests
+CREATE TABLE tenant_consumer_status (
+    tenant_id UUID PRIMARY KEY REFERENCES tenants (id) ON DELETE CASCADE,
+    owner VARCHAR(255) NOT NULL,
+    state VARCHAR(20) NOT NULL,
+    workers INTEGER NOT NULL,
+    prefetch INTEGER NOT NULL,
+    in_flight INTEGER NOT NULL,
+    processed BIGINT NOT NULL,
+    failed BIGINT NOT NULL,
+    last_delivery_at TIMESTAMPTZ,
+    last_error TEXT NOT NULL DEFAULT '',
+    last_error_at TIMESTAMPTZ,
+    reported_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1 @@
+ALTER TABLE queue_registry DROP COLUMN IF EXISTS expires_at;
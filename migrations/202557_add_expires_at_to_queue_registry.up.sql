@@ -0,0 +1,3 @@
+-- Auto-delete queues of the postgres broker expire unless their consumer
+-- keeps renewing them; NULL marks a durable queue
+ALTER TABLE queue_registry ADD COLUMN IF NOT EXISTS expires_at TIMESTAMPTZ;
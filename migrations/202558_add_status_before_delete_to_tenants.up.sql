@@ -0,0 +1,3 @@
+-- The status a tenant had when it was soft-deleted, so a restore brings a
+-- suspended tenant back suspended
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS status_before_delete VARCHAR(20);
@@ -0,0 +1,302 @@
+// Package archive writes message partitions to gzip-compressed NDJSON files
+// with a manifest, and reads them back.
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path"
+	"time"
+)
+
+// FormatNDJSONGzip is one JSON object per line, gzip-compressed.
+const FormatNDJSONGzip = "ndjson.gz"
+
+const manifestFile = "manifest.json"
+
+// Manifest describes an archived partition. It is written after all of its
+// files, so an archive without a manifest is incomplete.
+type Manifest struct {
+	TenantID string `json:"tenant_id"`
+	// Table is the name of the archived partition.
+	Table string `json:"table"`
+	// From and To are the created_at range of the partition; a nil From is
+	// unbounded.
+	From      *time.Time `json:"from,omitempty"`
+	To        *time.Time `json:"to,omitempty"`
+	Format    string     `json:"format"`
+	Rows      int64      `json:"rows"`
+	Files     []File     `json:"files"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// File is one compressed file of an archive.
+type File struct {
+	Key   string `json:"key"`
+	Rows  int64  `json:"rows"`
+	Bytes int64  `json:"bytes"`
+	// SHA256 is the hex digest of the compressed file.
+	SHA256 string `json:"sha256"`
+}
+
+// Prefix returns the key under which the files of a partition are stored.
+func Prefix(tenantID, table string) string {
+	return path.Join(tenantID, table)
+}
+
+// ManifestKey returns the key of the manifest of a partition.
+func ManifestKey(tenantID, table string) string {
+	return path.Join(Prefix(tenantID, table), manifestFile)
+}
+
+// ReadManifest loads the manifest stored under key.
+func ReadManifest(ctx context.Context, store Store, key string) (*Manifest, error) {
+	r, err := store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	var manifest Manifest
+	if err := json.NewDecoder(r).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest %s: %w", key, err)
+	}
+	if manifest.Format != FormatNDJSONGzip {
+		return nil, fmt.Errorf("unsupported archive format %q", manifest.Format)
+	}
+	return &manifest, nil
+}
+
+// Writer splits rows into files of at most rowsPerFile rows. Each file is
+// compressed into a temporary file first, so its size and checksum are known
+// before it is uploaded.
+type Writer struct {
+	store       Store
+	manifest    Manifest
+	rowsPerFile int64
+
+	tmp   *os.File
+	gz    *gzip.Writer
+	hash  hash.Hash
+	count *countingWriter
+	rows  int64
+}
+
+// NewWriter starts an archive of the partition described by manifest; its
+// format, rows and files are filled in by the writer.
+func NewWriter(store Store, manifest Manifest, rowsPerFile int) *Writer {
+	manifest.Format = FormatNDJSONGzip
+	manifest.Rows = 0
+	manifest.Files = []File{}
+	if rowsPerFile < 1 {
+		rowsPerFile = 1
+	}
+	return &Writer{store: store, manifest: manifest, rowsPerFile: int64(rowsPerFile)}
+}
+
+// Write appends one row, a JSON object.
+func (w *Writer) Write(ctx context.Context, row json.RawMessage) error {
+	if w.tmp == nil {
+		if err := w.open(); err != nil {
+			return err
+		}
+	}
+	if _, err := w.gz.Write(row); err != nil {
+		return fmt.Errorf("failed to compress row: %w", err)
+	}
+	if _, err := w.gz.Write([]byte{'\n'}); err != nil {
+		return fmt.Errorf("failed to compress row: %w", err)
+	}
+	w.rows++
+	w.manifest.Rows++
+	if w.rows >= w.rowsPerFile {
+		return w.flush(ctx)
+	}
+	return nil
+}
+
+// Close uploads the last file and then the manifest.
+func (w *Writer) Close(ctx context.Context) (*Manifest, error) {
+	if w.tmp != nil {
+		if err := w.flush(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	w.manifest.CreatedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(w.manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	key := ManifestKey(w.manifest.TenantID, w.manifest.Table)
+	if err := w.store.Put(ctx, key, bytes.NewReader(data), int64(len(data))); err != nil {
+		return nil, fmt.Errorf("failed to store manifest: %w", err)
+	}
+	manifest := w.manifest
+	return &manifest, nil
+}
+
+// Abort discards the file being written. Files already uploaded stay, but
+// without a manifest they are not an archive.
+func (w *Writer) Abort() {
+	if w.tmp != nil {
+		w.tmp.Close()
+		os.Remove(w.tmp.Name())
+		w.tmp = nil
+	}
+}
+
+func (w *Writer) open() error {
+	tmp, err := os.CreateTemp("", "archive-*.ndjson.gz")
+	if err != nil {
+		return fmt.Errorf("failed to create archive file: %w", err)
+	}
+	w.tmp = tmp
+	w.hash = sha256.New()
+	w.count = &countingWriter{}
+	w.gz = gzip.NewWriter(io.MultiWriter(tmp, w.hash, w.count))
+	w.rows = 0
+	return nil
+}
+
+func (w *Writer) flush(ctx context.Context) error {
+	defer w.Abort()
+
+	if err := w.gz.Close(); err != nil {
+		return fmt.Errorf("failed to compress archive file: %w", err)
+	}
+	if _, err := w.tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	file := File{
+		Key:    path.Join(Prefix(w.manifest.TenantID, w.manifest.Table), fmt.Sprintf("part-%05d.%s", len(w.manifest.Files)+1, FormatNDJSONGzip)),
+		Rows:   w.rows,
+		Bytes:  w.count.n,
+		SHA256: hex.EncodeToString(w.hash.Sum(nil)),
+	}
+	if err := w.store.Put(ctx, file.Key, w.tmp, file.Bytes); err != nil {
+		return fmt.Errorf("failed to store %s: %w", file.Key, err)
+	}
+	w.manifest.Files = append(w.manifest.Files, file)
+	return nil
+}
+
+// Reader returns the rows of an archive in the order they were written. Each
+// file is checked against the checksum and row count in the manifest once
+// it has been read to the end.
+type Reader struct {
+	store    Store
+	manifest *Manifest
+	next     int
+
+	file  File
+	body  io.ReadCloser
+	gz    *gzip.Reader
+	lines *bufio.Reader
+	hash  hash.Hash
+	rows  int64
+}
+
+func NewReader(store Store, manifest *Manifest) *Reader {
+	return &Reader{store: store, manifest: manifest}
+}
+
+// Next returns the next row, or io.EOF after the last one.
+func (r *Reader) Next(ctx context.Context) (json.RawMessage, error) {
+	for {
+		if r.lines == nil {
+			if r.next >= len(r.manifest.Files) {
+				return nil, io.EOF
+			}
+			if err := r.open(ctx, r.manifest.Files[r.next]); err != nil {
+				return nil, err
+			}
+			r.next++
+		}
+
+		line, err := r.lines.ReadBytes('\n')
+		if len(line) > 0 && line[len(line)-1] == '\n' {
+			line = line[:len(line)-1]
+		}
+		if len(line) > 0 {
+			r.rows++
+			return json.RawMessage(line), nil
+		}
+		if errors.Is(err, io.EOF) {
+			if err := r.verify(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", r.file.Key, err)
+		}
+	}
+}
+
+// Close releases the file being read.
+func (r *Reader) Close() error {
+	if r.body == nil {
+		return nil
+	}
+	err := r.body.Close()
+	r.body, r.gz, r.lines = nil, nil, nil
+	return err
+}
+
+func (r *Reader) open(ctx context.Context, file File) error {
+	body, err := r.store.Get(ctx, file.Key)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", file.Key, err)
+	}
+	r.hash = sha256.New()
+	gz, err := gzip.NewReader(io.TeeReader(body, r.hash))
+	if err != nil {
+		body.Close()
+		return fmt.Errorf("failed to open %s: %w", file.Key, err)
+	}
+	r.file, r.body, r.gz = file, body, gz
+	r.lines = bufio.NewReader(gz)
+	r.rows = 0
+	return nil
+}
+
+func (r *Reader) verify() error {
+	// Hash whatever follows the gzip stream too, so trailing bytes count
+	if _, err := io.Copy(io.Discard, io.TeeReader(r.body, r.hash)); err != nil {
+		return fmt.Errorf("failed to read %s: %w", r.file.Key, err)
+	}
+	file := r.file
+	sum := hex.EncodeToString(r.hash.Sum(nil))
+	rows := r.rows
+	if err := r.Close(); err != nil {
+		return err
+	}
+	if sum != file.SHA256 {
+		return fmt.Errorf("checksum mismatch in %s", file.Key)
+	}
+	if rows != file.Rows {
+		return fmt.Errorf("row count mismatch in %s: manifest has %d, file has %d", file.Key, file.Rows, rows)
+	}
+	return nil
+}
+
+type countingWriter struct {
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,131 @@
+package archive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeArchive(t *testing.T, store Store, rows int, rowsPerFile int) *Manifest {
+	t.Helper()
+	ctx := context.Background()
+	w := NewWriter(store, Manifest{TenantID: "tenant", Table: "messages_tenant_p20250101"}, rowsPerFile)
+	for i := 0; i < rows; i++ {
+		require.NoError(t, w.Write(ctx, json.RawMessage(fmt.Sprintf(`{"id":%d}`, i))))
+	}
+	manifest, err := w.Close(ctx)
+	require.NoError(t, err)
+	return manifest
+}
+
+func readAll(t *testing.T, store Store, manifest *Manifest) ([]string, error) {
+	t.Helper()
+	r := NewReader(store, manifest)
+	defer r.Close()
+	var rows []string
+	for {
+		row, err := r.Next(context.Background())
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return rows, err
+		}
+		rows = append(rows, string(row))
+	}
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	store := NewLocalStore(t.TempDir())
+	manifest := writeArchive(t, store, 5, 2)
+
+	assert.Equal(t, int64(5), manifest.Rows)
+	require.Len(t, manifest.Files, 3)
+	assert.Equal(t, "tenant/messages_tenant_p20250101/part-00001.ndjson.gz", manifest.Files[0].Key)
+	assert.Equal(t, int64(1), manifest.Files[2].Rows)
+
+	stored, err := ReadManifest(context.Background(), store, ManifestKey("tenant", "messages_tenant_p20250101"))
+	require.NoError(t, err)
+	assert.Equal(t, manifest.Files, stored.Files)
+
+	rows, err := readAll(t, store, stored)
+	require.NoError(t, err)
+	assert.Equal(t, []string{`{"id":0}`, `{"id":1}`, `{"id":2}`, `{"id":3}`, `{"id":4}`}, rows)
+}
+
+func TestArchiveVerifiesManifest(t *testing.T) {
+	store := NewLocalStore(t.TempDir())
+	manifest := writeArchive(t, store, 3, 10)
+
+	tampered := *manifest
+	tampered.Files = []File{manifest.Files[0]}
+	tampered.Files[0].SHA256 = strings.Repeat("0", 64)
+	_, err := readAll(t, store, &tampered)
+	assert.ErrorContains(t, err, "checksum mismatch")
+
+	tampered.Files[0] = manifest.Files[0]
+	tampered.Files[0].Rows = 4
+	_, err = readAll(t, store, &tampered)
+	assert.ErrorContains(t, err, "row count mismatch")
+}
+
+func TestS3StoreSignsRequests(t *testing.T) {
+	var mu sync.Mutex
+	objects := map[string][]byte{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/") ||
+			r.Header.Get("x-amz-date") == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			body, _ := io.ReadAll(r.Body)
+			objects[r.URL.Path] = body
+		case http.MethodGet, http.MethodHead:
+			body, ok := objects[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write(body)
+		}
+	}))
+	defer server.Close()
+
+	store, err := NewS3Store(S3Options{Endpoint: server.URL, Bucket: "archive", AccessKey: "key", SecretKey: "secret"})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	exists, err := store.Exists(ctx, "a/b.json")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.NoError(t, store.Put(ctx, "a/b.json", bytes.NewReader([]byte("data")), 4))
+	assert.Contains(t, objects, "/archive/a/b.json")
+
+	exists, err = store.Exists(ctx, "a/b.json")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	r, err := store.Get(ctx, "a/b.json")
+	require.NoError(t, err)
+	body, _ := io.ReadAll(r)
+	r.Close()
+	assert.Equal(t, "data", string(body))
+
+	_, err = store.Get(ctx, "missing")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrMissingToken = errors.New("missing token")
+	ErrForbidden    = errors.New("tenant not allowed")
+)
+
+type claimsKey struct{}
+
+// NewContext returns a context carrying the claims of the caller.
+func NewContext(ctx context.Context, claims Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// FromContext returns the claims stored by NewContext.
+func FromContext(ctx context.Context) (Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(Claims)
+	return claims, ok
+}
+
+// Authorize checks that the caller may use the tenant. Calls that are not
+// about a single tenant pass "*", which only tokens granting every tenant
+// allow.
+func Authorize(ctx context.Context, tenantID string) error {
+	claims, ok := FromContext(ctx)
+	if !ok {
+		return ErrMissingToken
+	}
+	if !claims.Allows(tenantID) {
+		return ErrForbidden
+	}
+	return nil
+}
+
+// AuthorizeOptional is Authorize for APIs where authentication can be turned
+// off: a caller without claims is let through, since only the middleware of
+// an API with authentication on stores them.
+func AuthorizeOptional(ctx context.Context, tenantID string) error {
+	if _, ok := FromContext(ctx); !ok {
+		return nil
+	}
+	return Authorize(ctx, tenantID)
+}
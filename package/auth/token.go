@@ -0,0 +1,111 @@
+// Package auth verifies the bearer tokens clients use to reach tenants over
+// long-lived connections.
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken  = errors.New("invalid token")
+	ErrTokenExpired  = errors.New("token expired")
+	ErrInvalidScheme = errors.New("authorization scheme must be Bearer")
+)
+
+// BearerToken returns the token of an Authorization value. An empty value
+// gives ErrMissingToken, and any scheme but Bearer gives ErrInvalidScheme.
+func BearerToken(header string) (string, error) {
+	if header == "" {
+		return "", ErrMissingToken
+	}
+	scheme, token, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", ErrInvalidScheme
+	}
+	if token = strings.TrimSpace(token); token == "" {
+		return "", ErrMissingToken
+	}
+	return token, nil
+}
+
+// Claims are the token claims the gateway reads. Tenants lists the tenant IDs
+// the client may use; "*" grants every tenant.
+type Claims struct {
+	Subject   string   `json:"sub"`
+	Tenants   []string `json:"tenants"`
+	ExpiresAt int64    `json:"exp,omitempty"`
+}
+
+// Allows reports whether the claims grant access to the tenant.
+func (c Claims) Allows(tenantID string) bool {
+	for _, t := range c.Tenants {
+		if t == "*" || t == tenantID {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseToken verifies an HS256 JWT signed with secret and returns its claims.
+// The token must name a subject.
+func ParseToken(secret, token string) (Claims, error) {
+	var claims Claims
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || secret == "" {
+		return claims, ErrInvalidToken
+	}
+
+	header, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return claims, ErrInvalidToken
+	}
+	var h struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(header, &h); err != nil || h.Alg != "HS256" {
+		return claims, ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return claims, ErrInvalidToken
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return claims, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return claims, ErrInvalidToken
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Subject == "" {
+		return Claims{}, ErrInvalidToken
+	}
+	if claims.ExpiresAt != 0 && time.Now().Unix() >= claims.ExpiresAt {
+		return Claims{}, ErrTokenExpired
+	}
+	return claims, nil
+}
+
+// SignToken creates an HS256 JWT for the claims.
+func SignToken(secret string, claims Claims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) +
+		"." + base64.RawURLEncoding.EncodeToString(payload)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)), nil
+}
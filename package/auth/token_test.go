@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTokenRoundTrip(t *testing.T) {
+	token, err := SignToken("secret", Claims{
+		Subject:   "client-1",
+		Tenants:   []string{"a"},
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	})
+	require.NoError(t, err)
+
+	claims, err := ParseToken("secret", token)
+	require.NoError(t, err)
+	assert.Equal(t, "client-1", claims.Subject)
+	assert.True(t, claims.Allows("a"))
+	assert.False(t, claims.Allows("b"))
+}
+
+func TestParseTokenRejects(t *testing.T) {
+	valid, err := SignToken("secret", Claims{Subject: "client-1"})
+	require.NoError(t, err)
+	expired, err := SignToken("secret", Claims{Subject: "client-1", ExpiresAt: time.Now().Add(-time.Minute).Unix()})
+	require.NoError(t, err)
+	anonymous, err := SignToken("secret", Claims{Tenants: []string{"*"}})
+	require.NoError(t, err)
+
+	_, err = ParseToken("other", valid)
+	assert.Equal(t, ErrInvalidToken, err)
+	_, err = ParseToken("secret", valid+"x")
+	assert.Equal(t, ErrInvalidToken, err)
+	_, err = ParseToken("secret", "not-a-token")
+	assert.Equal(t, ErrInvalidToken, err)
+	_, err = ParseToken("secret", anonymous)
+	assert.Equal(t, ErrInvalidToken, err)
+	_, err = ParseToken("secret", expired)
+	assert.Equal(t, ErrTokenExpired, err)
+}
+
+func TestWildcardAllowsEveryTenant(t *testing.T) {
+	assert.True(t, Claims{Tenants: []string{"*"}}.Allows("any"))
+}
+
+func TestAuthorize(t *testing.T) {
+	ctx := NewContext(context.Background(), Claims{Subject: "client-1", Tenants: []string{"a"}})
+
+	assert.NoError(t, Authorize(ctx, "a"))
+	assert.Equal(t, ErrForbidden, Authorize(ctx, "b"))
+	assert.Equal(t, ErrForbidden, Authorize(ctx, "*"))
+	assert.Equal(t, ErrMissingToken, Authorize(context.Background(), "a"))
+}
+
+func TestBearerToken(t *testing.T) {
+	token, err := BearerToken("Bearer abc")
+	require.NoError(t, err)
+	assert.Equal(t, "abc", token)
+	token, err = BearerToken("bearer abc")
+	require.NoError(t, err)
+	assert.Equal(t, "abc", token)
+
+	_, err = BearerToken("")
+	assert.ErrorIs(t, err, ErrMissingToken)
+	_, err = BearerToken("Bearer ")
+	assert.ErrorIs(t, err, ErrMissingToken)
+	_, err = BearerToken("Basic dXNlcjpwYXNz")
+	assert.ErrorIs(t, err, ErrInvalidScheme)
+	_, err = BearerToken("abc")
+	assert.ErrorIs(t, err, ErrInvalidScheme)
+}
@@ -0,0 +1,95 @@
+// Package broker abstracts the message queue used for tenant queues so the
+// usecases can run against RabbitMQ, Postgres or an in-process broker.
+package broker
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrQueueNotFound      = errors.New("queue not found")
+	ErrUnknownDeliveryTag = errors.New("unknown delivery tag")
+	ErrClosed             = errors.New("broker closed")
+	ErrExclusiveConsumer  = errors.New("queue has an exclusive consumer")
+)
+
+// Message is what gets published to a queue.
+type Message struct {
+	Body          []byte
+	ContentType   string
+	Headers       map[string]interface{}
+	CorrelationID string
+	ReplyTo       string
+	// Delay holds the message back for this long before it can be
+	// delivered. It is not carried to consumers.
+	Delay time.Duration
+}
+
+// Delivery is a message handed to a consumer. It must be acked or nacked.
+type Delivery struct {
+	Message
+	DeliveryTag  uint64
+	Redelivered  bool
+	Acknowledger Acknowledger
+}
+
+type Acknowledger interface {
+	Ack(tag uint64) error
+	Nack(tag uint64, requeue bool) error
+}
+
+func (d Delivery) Ack() error {
+	return d.Acknowledger.Ack(d.DeliveryTag)
+}
+
+// Nack rejects the delivery; with requeue it is redelivered later, without
+// it the message is discarded.
+func (d Delivery) Nack(requeue bool) error {
+	return d.Acknowledger.Nack(d.DeliveryTag, requeue)
+}
+
+type QueueInfo struct {
+	Name string
+	// Messages ready for delivery, not counting unacknowledged ones.
+	Messages  int
+	Consumers int
+}
+
+type QueueOptions struct {
+	// AutoDelete makes a temporary queue for a single process: it is deleted
+	// once its consumer goes away, and by the broker when the process does.
+	AutoDelete bool
+}
+
+type ConsumeOptions struct {
+	Tag string
+	// Prefetch bounds the number of unacknowledged deliveries.
+	Prefetch int
+	// Exclusive makes this the only consumer of the queue. It fails when
+	// the queue already has consumers.
+	Exclusive bool
+}
+
+type Subscription interface {
+	Deliveries() <-chan Delivery
+	// Cancel stops new deliveries and closes the deliveries channel.
+	// Deliveries already buffered in it can still be read and acknowledged.
+	Cancel() error
+	// Close cancels the subscription and requeues every delivery that has
+	// not been acknowledged yet.
+	Close() error
+}
+
+type Broker interface {
+	// DeclareQueue creates the queue if it does not exist.
+	DeclareQueue(ctx context.Context, name string, opts QueueOptions) (QueueInfo, error)
+	// InspectQueue returns the state of an existing queue without creating it.
+	InspectQueue(ctx context.Context, name string) (QueueInfo, error)
+	PurgeQueue(ctx context.Context, name string) (int, error)
+	DeleteQueue(ctx context.Context, name string) error
+	Publish(ctx context.Context, queue string, msg Message) error
+	Consume(ctx context.Context, queue string, opts ConsumeOptions) (Subscription, error)
+	Close() error
+}
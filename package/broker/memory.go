@@ -0,0 +1,354 @@
+package broker
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// defaultMemoryPrefetch is used when a consumer asks for unlimited prefetch.
+const defaultMemoryPrefetch = 1024
+
+// MemoryBroker is an in-process Broker for tests and local development. It
+// keeps RabbitMQ semantics where the service relies on them: per-consumer
+// prefetch, manual acks, requeue to the original position with the
+// redelivered flag and requeue of unacknowledged deliveries when a
+// subscription is closed.
+type MemoryBroker struct {
+	mu      sync.Mutex
+	queues  map[string]*memoryQueue
+	unacked map[uint64]*memoryUnacked
+	nextTag uint64
+	nextSeq uint64
+	closed  bool
+}
+
+type memoryQueue struct {
+	name       string
+	autoDelete bool
+	ready      []memoryMessage
+	consumers  []*memorySubscription
+	next       int
+}
+
+type memoryMessage struct {
+	msg Message
+	// seq orders the queue, so a requeued message gets its place back
+	seq         uint64
+	redelivered bool
+}
+
+type memoryUnacked struct {
+	queue *memoryQueue
+	sub   *memorySubscription
+	msg   memoryMessage
+}
+
+type memorySubscription struct {
+	broker     *MemoryBroker
+	queue      *memoryQueue
+	tag        string
+	prefetch   int
+	inflight   int
+	deliveries chan Delivery
+	cancelled  bool
+	exclusive  bool
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{
+		queues:  make(map[string]*memoryQueue),
+		unacked: make(map[uint64]*memoryUnacked),
+	}
+}
+
+func (b *MemoryBroker) DeclareQueue(ctx context.Context, name string, opts QueueOptions) (QueueInfo, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return QueueInfo{}, ErrClosed
+	}
+	q, exists := b.queues[name]
+	if !exists {
+		q = &memoryQueue{name: name, autoDelete: opts.AutoDelete}
+		b.queues[name] = q
+	}
+	return q.info(), nil
+}
+
+func (b *MemoryBroker) InspectQueue(ctx context.Context, name string) (QueueInfo, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return QueueInfo{}, ErrClosed
+	}
+	q, exists := b.queues[name]
+	if !exists {
+		return QueueInfo{}, ErrQueueNotFound
+	}
+	return q.info(), nil
+}
+
+func (b *MemoryBroker) PurgeQueue(ctx context.Context, name string) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return 0, ErrClosed
+	}
+	q, exists := b.queues[name]
+	if !exists {
+		return 0, ErrQueueNotFound
+	}
+	purged := len(q.ready)
+	q.ready = nil
+	return purged, nil
+}
+
+func (b *MemoryBroker) DeleteQueue(ctx context.Context, name string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+	q, exists := b.queues[name]
+	if !exists {
+		return nil
+	}
+	for _, sub := range q.consumers {
+		sub.cancel()
+	}
+	b.removeQueue(q)
+	return nil
+}
+
+// removeQueue forgets q and its unacknowledged messages. Callers must hold
+// b.mu.
+func (b *MemoryBroker) removeQueue(q *memoryQueue) {
+	for tag, u := range b.unacked {
+		if u.queue == q {
+			delete(b.unacked, tag)
+		}
+	}
+	if b.queues[q.name] == q {
+		delete(b.queues, q.name)
+	}
+}
+
+func (b *MemoryBroker) Publish(ctx context.Context, queue string, msg Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return ErrClosed
+	}
+	q, exists := b.queues[queue]
+	if !exists {
+		return ErrQueueNotFound
+	}
+	if msg.Delay > 0 {
+		delay := msg.Delay
+		msg.Delay = 0
+		time.AfterFunc(delay, func() { b.Publish(context.Background(), queue, msg) })
+		return nil
+	}
+	b.nextSeq++
+	q.ready = append(q.ready, memoryMessage{msg: msg, seq: b.nextSeq})
+	b.dispatch(q)
+	return nil
+}
+
+func (b *MemoryBroker) Consume(ctx context.Context, queue string, opts ConsumeOptions) (Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, ErrClosed
+	}
+	q, exists := b.queues[queue]
+	if !exists {
+		return nil, ErrQueueNotFound
+	}
+	if len(q.consumers) > 0 && (opts.Exclusive || q.consumers[0].exclusive) {
+		return nil, ErrExclusiveConsumer
+	}
+
+	prefetch := opts.Prefetch
+	if prefetch <= 0 {
+		prefetch = defaultMemoryPrefetch
+	}
+	sub := &memorySubscription{
+		broker:     b,
+		queue:      q,
+		tag:        opts.Tag,
+		prefetch:   prefetch,
+		deliveries: make(chan Delivery, prefetch),
+		exclusive:  opts.Exclusive,
+	}
+	q.consumers = append(q.consumers, sub)
+	b.dispatch(q)
+	return sub, nil
+}
+
+func (b *MemoryBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil
+	}
+	for _, q := range b.queues {
+		for _, sub := range q.consumers {
+			sub.cancel()
+		}
+	}
+	b.closed = true
+	return nil
+}
+
+func (b *MemoryBroker) Ack(tag uint64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	u, exists := b.unacked[tag]
+	if !exists {
+		return ErrUnknownDeliveryTag
+	}
+	delete(b.unacked, tag)
+	u.sub.inflight--
+	b.dispatch(u.queue)
+	return nil
+}
+
+func (b *MemoryBroker) Nack(tag uint64, requeue bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	u, exists := b.unacked[tag]
+	if !exists {
+		return ErrUnknownDeliveryTag
+	}
+	delete(b.unacked, tag)
+	u.sub.inflight--
+	if requeue {
+		u.queue.requeue(u.msg)
+	}
+	b.dispatch(u.queue)
+	return nil
+}
+
+// dispatch hands ready messages to consumers with free prefetch capacity in
+// round-robin order. The deliveries channel is sized to the prefetch, so the
+// send never blocks. Callers must hold b.mu.
+func (b *MemoryBroker) dispatch(q *memoryQueue) {
+	for len(q.ready) > 0 {
+		sub := q.nextConsumer()
+		if sub == nil {
+			return
+		}
+
+		m := q.ready[0]
+		q.ready = q.ready[1:]
+
+		b.nextTag++
+		tag := b.nextTag
+		b.unacked[tag] = &memoryUnacked{queue: q, sub: sub, msg: m}
+		sub.inflight++
+		sub.deliveries <- Delivery{
+			Message:      m.msg,
+			DeliveryTag:  tag,
+			Redelivered:  m.redelivered,
+			Acknowledger: b,
+		}
+	}
+}
+
+func (q *memoryQueue) nextConsumer() *memorySubscription {
+	for i := 0; i < len(q.consumers); i++ {
+		sub := q.consumers[(q.next+i)%len(q.consumers)]
+		if sub.inflight < sub.prefetch {
+			q.next = (q.next + i + 1) % len(q.consumers)
+			return sub
+		}
+	}
+	return nil
+}
+
+// requeue puts a message back at its original position in the queue.
+func (q *memoryQueue) requeue(m memoryMessage) {
+	m.redelivered = true
+	i := sort.Search(len(q.ready), func(i int) bool { return q.ready[i].seq > m.seq })
+	q.ready = append(q.ready, memoryMessage{})
+	copy(q.ready[i+1:], q.ready[i:])
+	q.ready[i] = m
+}
+
+func (q *memoryQueue) info() QueueInfo {
+	return QueueInfo{Name: q.name, Messages: len(q.ready), Consumers: len(q.consumers)}
+}
+
+func (s *memorySubscription) Deliveries() <-chan Delivery {
+	return s.deliveries
+}
+
+func (s *memorySubscription) Cancel() error {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	s.cancel()
+	return nil
+}
+
+// Close requeues the unacknowledged deliveries, and deletes an auto-delete
+// queue once its last consumer is closed.
+func (s *memorySubscription) Close() error {
+	b := s.broker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	s.cancel()
+	if s.queue.autoDelete && len(s.queue.consumers) == 0 {
+		b.removeQueue(s.queue)
+		return nil
+	}
+
+	var tags []uint64
+	for tag, u := range b.unacked {
+		if u.sub == s {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+
+	for _, tag := range tags {
+		s.queue.requeue(b.unacked[tag].msg)
+		delete(b.unacked, tag)
+	}
+	s.inflight = 0
+	b.dispatch(s.queue)
+	return nil
+}
+
+// cancel detaches the subscription from its queue. Callers must hold s.broker.mu.
+func (s *memorySubscription) cancel() {
+	if s.cancelled {
+		return
+	}
+	s.cancelled = true
+	for i, sub := range s.queue.consumers {
+		if sub == s {
+			s.queue.consumers = append(s.queue.consumers[:i], s.queue.consumers[i+1:]...)
+			break
+		}
+	}
+	if s.queue.next >= len(s.queue.consumers) {
+		s.queue.next = 0
+	}
+	close(s.deliveries)
+}
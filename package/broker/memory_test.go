@@ -0,0 +1,199 @@
+package broker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receive(t *testing.T, sub Subscription) Delivery {
+	t.Helper()
+	select {
+	case d := <-sub.Deliveries():
+		return d
+	default:
+		t.Fatal("expected a delivery")
+		return Delivery{}
+	}
+}
+
+func assertEmpty(t *testing.T, sub Subscription) {
+	t.Helper()
+	select {
+	case d, ok := <-sub.Deliveries():
+		if ok {
+			t.Fatalf("unexpected delivery %q", d.Body)
+		}
+	default:
+	}
+}
+
+func TestMemoryBrokerAckAndPrefetch(t *testing.T) {
+	ctx := context.Background()
+	b := NewMemoryBroker()
+	_, err := b.DeclareQueue(ctx, "q", QueueOptions{})
+	require.NoError(t, err)
+
+	for _, body := range []string{"a", "b", "c"} {
+		require.NoError(t, b.Publish(ctx, "q", Message{Body: []byte(body)}))
+	}
+
+	sub, err := b.Consume(ctx, "q", ConsumeOptions{Tag: "c1", Prefetch: 2})
+	require.NoError(t, err)
+
+	first := receive(t, sub)
+	second := receive(t, sub)
+	assert.Equal(t, "a", string(first.Body))
+	assert.Equal(t, "b", string(second.Body))
+	assertEmpty(t, sub)
+
+	info, err := b.InspectQueue(ctx, "q")
+	require.NoError(t, err)
+	assert.Equal(t, 1, info.Messages)
+	assert.Equal(t, 1, info.Consumers)
+
+	require.NoError(t, first.Ack())
+	assert.Equal(t, "c", string(receive(t, sub).Body))
+	assert.ErrorIs(t, first.Ack(), ErrUnknownDeliveryTag)
+}
+
+func TestMemoryBrokerRedelivery(t *testing.T) {
+	ctx := context.Background()
+	b := NewMemoryBroker()
+	_, err := b.DeclareQueue(ctx, "q", QueueOptions{})
+	require.NoError(t, err)
+	require.NoError(t, b.Publish(ctx, "q", Message{Body: []byte("a")}))
+	require.NoError(t, b.Publish(ctx, "q", Message{Body: []byte("b")}))
+
+	sub, err := b.Consume(ctx, "q", ConsumeOptions{Prefetch: 1})
+	require.NoError(t, err)
+
+	d := receive(t, sub)
+	assert.False(t, d.Redelivered)
+	require.NoError(t, d.Nack(true))
+
+	d = receive(t, sub)
+	assert.Equal(t, "a", string(d.Body))
+	assert.True(t, d.Redelivered)
+
+	// Closing requeues the unacknowledged delivery for the next consumer
+	require.NoError(t, sub.Close())
+	other, err := b.Consume(ctx, "q", ConsumeOptions{Prefetch: 10})
+	require.NoError(t, err)
+	d = receive(t, other)
+	assert.Equal(t, "a", string(d.Body))
+	assert.True(t, d.Redelivered)
+	assert.Equal(t, "b", string(receive(t, other).Body))
+
+	require.NoError(t, d.Nack(false))
+	info, err := b.InspectQueue(ctx, "q")
+	require.NoError(t, err)
+	assert.Equal(t, 0, info.Messages)
+}
+
+func TestMemoryBrokerRequeueKeepsPosition(t *testing.T) {
+	ctx := context.Background()
+	b := NewMemoryBroker()
+	_, err := b.DeclareQueue(ctx, "q", QueueOptions{})
+	require.NoError(t, err)
+	for _, body := range []string{"a", "b", "c"} {
+		require.NoError(t, b.Publish(ctx, "q", Message{Body: []byte(body)}))
+	}
+
+	sub, err := b.Consume(ctx, "q", ConsumeOptions{Prefetch: 2})
+	require.NoError(t, err)
+	a, bd := receive(t, sub), receive(t, sub)
+	require.NoError(t, sub.Cancel())
+
+	// Nacked in delivery order, the messages still come back in queue order
+	require.NoError(t, a.Nack(true))
+	require.NoError(t, bd.Nack(true))
+
+	other, err := b.Consume(ctx, "q", ConsumeOptions{Prefetch: 10})
+	require.NoError(t, err)
+	for _, body := range []string{"a", "b", "c"} {
+		assert.Equal(t, body, string(receive(t, other).Body))
+	}
+}
+
+func TestMemoryBrokerCancel(t *testing.T) {
+	ctx := context.Background()
+	b := NewMemoryBroker()
+	_, err := b.DeclareQueue(ctx, "q", QueueOptions{})
+	require.NoError(t, err)
+	require.NoError(t, b.Publish(ctx, "q", Message{Body: []byte("a")}))
+
+	sub, err := b.Consume(ctx, "q", ConsumeOptions{Prefetch: 1})
+	require.NoError(t, err)
+	require.NoError(t, sub.Cancel())
+
+	// Buffered deliveries survive the cancel and can still be acked
+	d, ok := <-sub.Deliveries()
+	require.True(t, ok)
+	require.NoError(t, d.Ack())
+	_, ok = <-sub.Deliveries()
+	assert.False(t, ok)
+
+	require.NoError(t, b.Publish(ctx, "q", Message{Body: []byte("b")}))
+	info, err := b.InspectQueue(ctx, "q")
+	require.NoError(t, err)
+	assert.Equal(t, 1, info.Messages)
+	assert.Equal(t, 0, info.Consumers)
+
+	assert.ErrorIs(t, b.Publish(ctx, "missing", Message{}), ErrQueueNotFound)
+}
+
+func TestMemoryBrokerExclusiveConsumer(t *testing.T) {
+	ctx := context.Background()
+	b := NewMemoryBroker()
+	_, err := b.DeclareQueue(ctx, "q", QueueOptions{})
+	require.NoError(t, err)
+
+	sub, err := b.Consume(ctx, "q", ConsumeOptions{Exclusive: true})
+	require.NoError(t, err)
+	_, err = b.Consume(ctx, "q", ConsumeOptions{})
+	assert.ErrorIs(t, err, ErrExclusiveConsumer)
+
+	require.NoError(t, sub.Close())
+	other, err := b.Consume(ctx, "q", ConsumeOptions{})
+	require.NoError(t, err)
+	_, err = b.Consume(ctx, "q", ConsumeOptions{Exclusive: true})
+	assert.ErrorIs(t, err, ErrExclusiveConsumer)
+	require.NoError(t, other.Close())
+}
+
+func TestMemoryBrokerDelay(t *testing.T) {
+	ctx := context.Background()
+	b := NewMemoryBroker()
+	_, err := b.DeclareQueue(ctx, "q", QueueOptions{})
+	require.NoError(t, err)
+
+	require.NoError(t, b.Publish(ctx, "q", Message{Body: []byte("a"), Delay: 50 * time.Millisecond}))
+	info, err := b.InspectQueue(ctx, "q")
+	require.NoError(t, err)
+	assert.Equal(t, 0, info.Messages)
+
+	assert.Eventually(t, func() bool {
+		info, err := b.InspectQueue(ctx, "q")
+		return err == nil && info.Messages == 1
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestMemoryBrokerAutoDelete(t *testing.T) {
+	ctx := context.Background()
+	b := NewMemoryBroker()
+	_, err := b.DeclareQueue(ctx, "q", QueueOptions{AutoDelete: true})
+	require.NoError(t, err)
+
+	sub, err := b.Consume(ctx, "q", ConsumeOptions{})
+	require.NoError(t, err)
+	require.NoError(t, b.Publish(ctx, "q", Message{Body: []byte("a")}))
+	require.NoError(t, sub.Close())
+
+	_, err = b.InspectQueue(ctx, "q")
+	assert.ErrorIs(t, err, ErrQueueNotFound)
+	assert.ErrorIs(t, b.Publish(ctx, "q", Message{}), ErrQueueNotFound)
+}
@@ -0,0 +1,567 @@
+package broker
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const notifyChannel = "queue_jobs"
+
+// defaultPostgresPrefetch bounds the jobs a subscription claims at once when
+// the consumer does not set a prefetch.
+const defaultPostgresPrefetch = 100
+
+type PostgresOptions struct {
+	// VisibilityTimeout is how long a claimed job stays invisible to other
+	// consumers before it is redelivered.
+	VisibilityTimeout time.Duration
+	// PollInterval is the fallback wakeup when no notification arrives, which
+	// also picks up jobs whose visibility timeout expired.
+	PollInterval time.Duration
+}
+
+// PostgresBroker stores queued messages in the queue_jobs table. Consumers
+// claim jobs with SELECT ... FOR UPDATE SKIP LOCKED and are woken up by
+// LISTEN/NOTIFY when a job is published. A claimed job is hidden for the
+// visibility timeout; if it is neither acked nor nacked by then it becomes
+// visible again and is redelivered.
+type PostgresBroker struct {
+	db       *sql.DB
+	listener *pq.Listener
+	opts     PostgresOptions
+
+	mu   sync.Mutex
+	subs map[string][]*postgresSubscription
+	// autoDelete holds the auto-delete queues declared by this broker
+	autoDelete map[string]bool
+	closed     bool
+}
+
+func NewPostgresBroker(db *sql.DB, databaseURL string, opts PostgresOptions) (*PostgresBroker, error) {
+	if opts.VisibilityTimeout <= 0 {
+		opts.VisibilityTimeout = 30 * time.Second
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+
+	listener := pq.NewListener(databaseURL, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("queue listener: %v", err)
+		}
+	})
+	if err := listener.Listen(notifyChannel); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to listen for queue notifications: %w", err)
+	}
+
+	b := &PostgresBroker{
+		db:       db,
+		listener: listener,
+		opts:     opts,
+		subs:     make(map[string][]*postgresSubscription),
+
+		autoDelete: make(map[string]bool),
+	}
+	go b.listen()
+	return b, nil
+}
+
+// listen wakes the subscriptions of the queue named in each notification. A
+// nil notification means the connection was re-established and notifications
+// may have been missed, so every subscription is woken.
+func (b *PostgresBroker) listen() {
+	for n := range b.listener.Notify {
+		b.mu.Lock()
+		if n == nil {
+			for _, subs := range b.subs {
+				for _, sub := range subs {
+					sub.wakeup()
+				}
+			}
+		} else {
+			for _, sub := range b.subs[n.Extra] {
+				sub.wakeup()
+			}
+		}
+		b.mu.Unlock()
+	}
+}
+
+// DeclareQueue registers the queue. An auto-delete queue expires after the
+// visibility timeout unless a consumer keeps renewing it, so the queue of a
+// process that died without closing it is swept by the next declaration.
+func (b *PostgresBroker) DeclareQueue(ctx context.Context, name string, opts QueueOptions) (QueueInfo, error) {
+	if !opts.AutoDelete {
+		_, err := b.db.ExecContext(ctx,
+			"INSERT INTO queue_registry (name) VALUES ($1) ON CONFLICT (name) DO NOTHING", name)
+		if err != nil {
+			return QueueInfo{}, fmt.Errorf("failed to declare queue: %w", err)
+		}
+		return b.InspectQueue(ctx, name)
+	}
+
+	if _, err := b.db.ExecContext(ctx,
+		"DELETE FROM queue_registry WHERE expires_at < NOW()"); err != nil {
+		return QueueInfo{}, fmt.Errorf("failed to delete expired queues: %w", err)
+	}
+	_, err := b.db.ExecContext(ctx, `
+		INSERT INTO queue_registry (name, expires_at)
+		VALUES ($1, NOW() + $2::double precision * INTERVAL '1 millisecond')
+		ON CONFLICT (name) DO NOTHING
+	`, name, b.opts.VisibilityTimeout.Milliseconds())
+	if err != nil {
+		return QueueInfo{}, fmt.Errorf("failed to declare queue: %w", err)
+	}
+	b.mu.Lock()
+	b.autoDelete[name] = true
+	b.mu.Unlock()
+	return b.InspectQueue(ctx, name)
+}
+
+func (b *PostgresBroker) InspectQueue(ctx context.Context, name string) (QueueInfo, error) {
+	info := QueueInfo{Name: name}
+	err := b.db.QueryRowContext(ctx, `
+		SELECT COUNT(j.id)
+		FROM queue_registry r
+		LEFT JOIN queue_jobs j ON j.queue = r.name AND j.visible_at <= NOW()
+		WHERE r.name = $1
+		GROUP BY r.name
+	`, name).Scan(&info.Messages)
+	if err == sql.ErrNoRows {
+		return info, ErrQueueNotFound
+	}
+	if err != nil {
+		return info, fmt.Errorf("failed to inspect queue: %w", err)
+	}
+
+	// Only consumers of this process are known here
+	b.mu.Lock()
+	info.Consumers = len(b.subs[name])
+	b.mu.Unlock()
+	return info, nil
+}
+
+func (b *PostgresBroker) PurgeQueue(ctx context.Context, name string) (int, error) {
+	if _, err := b.InspectQueue(ctx, name); err != nil {
+		return 0, err
+	}
+	res, err := b.db.ExecContext(ctx,
+		"DELETE FROM queue_jobs WHERE queue = $1 AND visible_at <= NOW()", name)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge queue: %w", err)
+	}
+	purged, err := res.RowsAffected()
+	return int(purged), err
+}
+
+func (b *PostgresBroker) DeleteQueue(ctx context.Context, name string) error {
+	b.mu.Lock()
+	subs := b.subs[name]
+	delete(b.subs, name)
+	delete(b.autoDelete, name)
+	b.mu.Unlock()
+	for _, sub := range subs {
+		sub.Cancel()
+	}
+
+	// Jobs are removed by ON DELETE CASCADE
+	if _, err := b.db.ExecContext(ctx, "DELETE FROM queue_registry WHERE name = $1", name); err != nil {
+		return fmt.Errorf("failed to delete queue: %w", err)
+	}
+	return nil
+}
+
+func (b *PostgresBroker) Publish(ctx context.Context, queue string, msg Message) error {
+	headers, err := json.Marshal(msg.Headers)
+	if err != nil {
+		return fmt.Errorf("failed to encode headers: %w", err)
+	}
+	if msg.Headers == nil {
+		headers = []byte("{}")
+	}
+
+	_, err = b.db.ExecContext(ctx, `
+		WITH job AS (
+			INSERT INTO queue_jobs (queue, body, content_type, headers, correlation_id, reply_to, visible_at)
+			VALUES ($1, $2, $3, $4, $5, $6, NOW() + $8 * INTERVAL '1 millisecond')
+			RETURNING queue
+		)
+		SELECT pg_notify($7, queue) FROM job
+	`, queue, msg.Body, msg.ContentType, headers, msg.CorrelationID, msg.ReplyTo, notifyChannel, msg.Delay.Milliseconds())
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return ErrQueueNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+	return nil
+}
+
+func (b *PostgresBroker) Consume(ctx context.Context, queue string, opts ConsumeOptions) (Subscription, error) {
+	if _, err := b.InspectQueue(ctx, queue); err != nil {
+		return nil, err
+	}
+
+	prefetch := opts.Prefetch
+	if prefetch <= 0 {
+		prefetch = defaultPostgresPrefetch
+	}
+	sub := &postgresSubscription{
+		broker:     b,
+		queue:      queue,
+		prefetch:   prefetch,
+		exclusive:  opts.Exclusive,
+		leases:     make(map[uint64]postgresLease),
+		deliveries: make(chan Delivery, prefetch),
+		wake:       make(chan struct{}, 1),
+		stop:       make(chan struct{}),
+		stopped:    make(chan struct{}),
+		closing:    make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil, ErrClosed
+	}
+	// Consumers in other processes are not visible here, so exclusivity
+	// only holds within this broker
+	if queueSubs := b.subs[queue]; len(queueSubs) > 0 && (opts.Exclusive || queueSubs[0].exclusive) {
+		b.mu.Unlock()
+		return nil, ErrExclusiveConsumer
+	}
+	b.subs[queue] = append(b.subs[queue], sub)
+	b.mu.Unlock()
+
+	go sub.run()
+	go sub.keepAlive()
+	return sub, nil
+}
+
+func (b *PostgresBroker) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	var subs []*postgresSubscription
+	for _, queueSubs := range b.subs {
+		subs = append(subs, queueSubs...)
+	}
+	b.subs = make(map[string][]*postgresSubscription)
+	b.mu.Unlock()
+
+	for _, sub := range subs {
+		sub.Close()
+	}
+	return b.listener.Close()
+}
+
+// isAutoDelete reports whether the queue was declared auto-delete by this
+// broker.
+func (b *PostgresBroker) isAutoDelete(queue string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.autoDelete[queue]
+}
+
+// unusedAutoDelete reports whether the queue is auto-delete and has no
+// consumer left in this broker.
+func (b *PostgresBroker) unusedAutoDelete(queue string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.autoDelete[queue] && len(b.subs[queue]) == 0
+}
+
+func (b *PostgresBroker) removeSubscription(sub *postgresSubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subs := b.subs[sub.queue]
+	for i, s := range subs {
+		if s == sub {
+			b.subs[sub.queue] = append(subs[:i], subs[i+1:]...)
+			break
+		}
+	}
+}
+
+// postgresLease identifies a claim on a job. attempts changes on every claim,
+// so an ack arriving after the job was redelivered elsewhere is ignored.
+type postgresLease struct {
+	id       int64
+	attempts int
+}
+
+type postgresSubscription struct {
+	broker    *PostgresBroker
+	queue     string
+	prefetch  int
+	exclusive bool
+
+	mu      sync.Mutex
+	leases  map[uint64]postgresLease
+	nextTag uint64
+
+	deliveries chan Delivery
+	wake       chan struct{}
+	stop       chan struct{}
+	stopped    chan struct{}
+	cancelOnce sync.Once
+	// closing stops keepAlive once Close hands the held jobs back
+	closing   chan struct{}
+	closeOnce sync.Once
+}
+
+func (s *postgresSubscription) wakeup() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run claims jobs while there is free prefetch capacity and sleeps until a
+// notification, an ack or the poll interval otherwise.
+func (s *postgresSubscription) run() {
+	defer close(s.stopped)
+	defer close(s.deliveries)
+
+	ticker := time.NewTicker(s.broker.opts.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.mu.Lock()
+		free := s.prefetch - len(s.leases)
+		s.mu.Unlock()
+
+		if free > 0 {
+			deliveries, err := s.claim(free)
+			if err != nil {
+				log.Printf("Failed to claim jobs from queue %s: %v", s.queue, err)
+			}
+			for _, d := range deliveries {
+				s.deliveries <- d
+			}
+			if len(deliveries) == free {
+				// There may be more work waiting; check again right away
+				select {
+				case <-s.stop:
+					return
+				default:
+					continue
+				}
+			}
+		}
+
+		select {
+		case <-s.stop:
+			return
+		case <-s.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// keepAlive extends the visibility timeout of the jobs the subscription
+// holds every third of the timeout until it is closed, so a job handled for
+// longer than the timeout is not redelivered while it is still running. It
+// keeps going after Cancel, as draining consumers still handle their jobs.
+// The expiry of an auto-delete queue is renewed along with the jobs.
+func (s *postgresSubscription) keepAlive() {
+	ticker := time.NewTicker(max(s.broker.opts.VisibilityTimeout/3, time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.closing:
+			return
+		case <-ticker.C:
+			if err := s.extend(); err != nil {
+				log.Printf("Failed to extend jobs of queue %s: %v", s.queue, err)
+			}
+			if err := s.renewQueue(); err != nil {
+				log.Printf("Failed to renew queue %s: %v", s.queue, err)
+			}
+		}
+	}
+}
+
+func (s *postgresSubscription) extend() error {
+	s.mu.Lock()
+	ids := make([]int64, 0, len(s.leases))
+	attempts := make([]int64, 0, len(s.leases))
+	for _, lease := range s.leases {
+		ids = append(ids, lease.id)
+		attempts = append(attempts, int64(lease.attempts))
+	}
+	s.mu.Unlock()
+	if len(ids) == 0 {
+		return nil
+	}
+
+	// attempts still matching means the job was not redelivered meanwhile
+	_, err := s.broker.db.Exec(`
+		UPDATE queue_jobs
+		SET visible_at = NOW() + $3::double precision * INTERVAL '1 millisecond'
+		FROM unnest($1::bigint[], $2::integer[]) AS held(id, attempts)
+		WHERE queue_jobs.id = held.id AND queue_jobs.attempts = held.attempts
+	`, pq.Array(ids), pq.Array(attempts), s.broker.opts.VisibilityTimeout.Milliseconds())
+	return err
+}
+
+func (s *postgresSubscription) renewQueue() error {
+	if !s.broker.isAutoDelete(s.queue) {
+		return nil
+	}
+	_, err := s.broker.db.Exec(`
+		UPDATE queue_registry
+		SET expires_at = NOW() + $2::double precision * INTERVAL '1 millisecond'
+		WHERE name = $1 AND expires_at IS NOT NULL
+	`, s.queue, s.broker.opts.VisibilityTimeout.Milliseconds())
+	return err
+}
+
+func (s *postgresSubscription) claim(limit int) ([]Delivery, error) {
+	rows, err := s.broker.db.Query(`
+		UPDATE queue_jobs
+		SET visible_at = NOW() + $3::double precision * INTERVAL '1 millisecond', attempts = attempts + 1
+		WHERE id IN (
+			SELECT id FROM queue_jobs
+			WHERE queue = $1 AND visible_at <= NOW()
+			ORDER BY id
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, body, content_type, headers, correlation_id, reply_to, attempts
+	`, s.queue, limit, s.broker.opts.VisibilityTimeout.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	type claimed struct {
+		lease postgresLease
+		msg   Message
+	}
+	var jobs []claimed
+	for rows.Next() {
+		var job claimed
+		var headers []byte
+		if err := rows.Scan(&job.lease.id, &job.msg.Body, &job.msg.ContentType, &headers,
+			&job.msg.CorrelationID, &job.msg.ReplyTo, &job.lease.attempts); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(headers, &job.msg.Headers); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].lease.id < jobs[j].lease.id })
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	deliveries := make([]Delivery, 0, len(jobs))
+	for _, job := range jobs {
+		s.nextTag++
+		s.leases[s.nextTag] = job.lease
+		deliveries = append(deliveries, Delivery{
+			Message:      job.msg,
+			DeliveryTag:  s.nextTag,
+			Redelivered:  job.lease.attempts > 1,
+			Acknowledger: s,
+		})
+	}
+	return deliveries, nil
+}
+
+func (s *postgresSubscription) release(tag uint64) (postgresLease, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lease, exists := s.leases[tag]
+	if !exists {
+		return lease, ErrUnknownDeliveryTag
+	}
+	delete(s.leases, tag)
+	s.wakeup()
+	return lease, nil
+}
+
+func (s *postgresSubscription) Ack(tag uint64) error {
+	lease, err := s.release(tag)
+	if err != nil {
+		return err
+	}
+	_, err = s.broker.db.Exec("DELETE FROM queue_jobs WHERE id = $1 AND attempts = $2",
+		lease.id, lease.attempts)
+	return err
+}
+
+func (s *postgresSubscription) Nack(tag uint64, requeue bool) error {
+	lease, err := s.release(tag)
+	if err != nil {
+		return err
+	}
+	if !requeue {
+		_, err = s.broker.db.Exec("DELETE FROM queue_jobs WHERE id = $1 AND attempts = $2",
+			lease.id, lease.attempts)
+		return err
+	}
+	_, err = s.broker.db.Exec("UPDATE queue_jobs SET visible_at = NOW() WHERE id = $1 AND attempts = $2",
+		lease.id, lease.attempts)
+	return err
+}
+
+func (s *postgresSubscription) Deliveries() <-chan Delivery {
+	return s.deliveries
+}
+
+func (s *postgresSubscription) Cancel() error {
+	s.cancelOnce.Do(func() {
+		s.broker.removeSubscription(s)
+		close(s.stop)
+	})
+	return nil
+}
+
+// Close cancels the subscription and makes every job it still holds visible
+// again right away instead of waiting for the visibility timeout. Closing
+// the last consumer of an auto-delete queue deletes the queue.
+func (s *postgresSubscription) Close() error {
+	s.Cancel()
+	<-s.stopped
+	s.closeOnce.Do(func() { close(s.closing) })
+
+	if s.broker.unusedAutoDelete(s.queue) {
+		return s.broker.DeleteQueue(context.Background(), s.queue)
+	}
+
+	s.mu.Lock()
+	leases := s.leases
+	s.leases = make(map[uint64]postgresLease)
+	s.mu.Unlock()
+
+	for _, lease := range leases {
+		if _, err := s.broker.db.Exec("UPDATE queue_jobs SET visible_at = NOW() WHERE id = $1 AND attempts = $2",
+			lease.id, lease.attempts); err != nil {
+			return fmt.Errorf("failed to requeue job %d: %w", lease.id, err)
+		}
+	}
+	return nil
+}
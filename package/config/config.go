@@ -3,17 +3,38 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"gopkg.in/yaml.v2"
 )
 
 type Config struct {
-	RabbitMQ RabbitMQConfig `yaml:"rabbitmq"`
-	Database DatabaseConfig `yaml:"database"`
-	Server   ServerConfig   `yaml:"server"`
-	Workers  int            `yaml:"workers"`
-	Logging  LoggingConfig  `yaml:"logging"`
-	JWT      JWTConfig      `yaml:"jwt"`
+	Broker     BrokerConfig     `yaml:"broker"`
+	RabbitMQ   RabbitMQConfig   `yaml:"rabbitmq"`
+	Database   DatabaseConfig   `yaml:"database"`
+	Server     ServerConfig     `yaml:"server"`
+	Workers    int              `yaml:"workers"`
+	Logging    LoggingConfig    `yaml:"logging"`
+	JWT        JWTConfig        `yaml:"jwt"`
+	Tenant     TenantConfig     `yaml:"tenant"`
+	Worker     WorkerConfig     `yaml:"worker"`
+	Autoscaler AutoscalerConfig `yaml:"autoscaler"`
+	Stream     StreamConfig     `yaml:"stream"`
+	Gateway    GatewayConfig    `yaml:"gateway"`
+	Pull       PullConfig       `yaml:"pull"`
+	Request    RequestConfig    `yaml:"request"`
+	Partitions PartitionConfig  `yaml:"partitions"`
+	Archive    ArchiveConfig    `yaml:"archive"`
+	Migrations MigrationsConfig `yaml:"migrations"`
+	Webhooks   WebhookConfig    `yaml:"webhooks"`
+}
+
+type BrokerConfig struct {
+	// Driver selects the queue backend: "rabbitmq" (default), "postgres" or "memory".
+	Driver string `yaml:"driver"`
+	// VisibilityTimeout and PollInterval only apply to the postgres driver.
+	VisibilityTimeout time.Duration `yaml:"visibility_timeout"`
+	PollInterval      time.Duration `yaml:"poll_interval"`
 }
 
 type RabbitMQConfig struct {
@@ -26,6 +47,11 @@ type DatabaseConfig struct {
 
 type ServerConfig struct {
 	Port string `yaml:"port"`
+	// GRPCPort serves the gRPC API next to the REST API; empty disables it.
+	GRPCPort string `yaml:"grpc_port"`
+	// ShutdownTimeout is how long in-flight messages get to finish on
+	// shutdown before they are requeued.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
 }
 
 type LoggingConfig struct {
@@ -34,6 +60,140 @@ type LoggingConfig struct {
 
 type JWTConfig struct {
 	Secret string `yaml:"secret"`
+	// RESTAuth makes the REST API require the bearer tokens gRPC and the
+	// gateway take. It is off by default, so existing REST clients keep
+	// working until they send tokens.
+	RESTAuth bool `yaml:"rest_auth"`
+}
+
+type TenantConfig struct {
+	// SuspendedPublishPolicy decides what happens to messages published to a
+	// suspended tenant: "reject" returns an error, "buffer" keeps them in the
+	// tenant queue until the tenant is resumed.
+	SuspendedPublishPolicy string `yaml:"suspended_publish_policy"`
+	// DeletionGracePeriod is how long a deleted tenant can be restored before
+	// the purge worker removes it for good.
+	DeletionGracePeriod time.Duration `yaml:"deletion_grace_period"`
+	PurgeInterval       time.Duration `yaml:"purge_interval"`
+	// MaxDeliveryAttempts is how often a message whose pipeline fails with
+	// a retryable error is tried before it is dead-lettered.
+	MaxDeliveryAttempts int `yaml:"max_delivery_attempts"`
+}
+
+type WorkerConfig struct {
+	// Ownership selects how serve-worker replicas split tenants: "shard"
+	// (default) uses the static shard flags, "lease" coordinates through
+	// leases in Postgres.
+	Ownership string `yaml:"ownership"`
+	// LeaseTTL is how long a tenant lease or replica heartbeat stays valid.
+	// It should be a few times ReconcileInterval.
+	LeaseTTL time.Duration `yaml:"lease_ttl"`
+	// ShardIndex and ShardCount split tenants across serve-worker replicas.
+	ShardIndex int `yaml:"shard_index"`
+	ShardCount int `yaml:"shard_count"`
+	// ReconcileInterval is how often a process syncs its consumers with the
+	// tenants table.
+	ReconcileInterval time.Duration `yaml:"reconcile_interval"`
+}
+
+// AutoscalerConfig drives the autoscaler for tenants that enable it in their
+// settings; the worker bounds are per tenant.
+type AutoscalerConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Interval is how often queue depth and utilization are sampled.
+	Interval time.Duration `yaml:"interval"`
+	// Cooldown is the minimum time between two changes for the same tenant.
+	Cooldown time.Duration `yaml:"cooldown"`
+	// TargetBacklog is the number of queued messages per worker the
+	// autoscaler aims for when scaling up.
+	TargetBacklog int `yaml:"target_backlog"`
+}
+
+// StreamConfig configures the server-sent event stream of stored messages.
+type StreamConfig struct {
+	// Notifier selects how streams learn about new messages: "local"
+	// (default) only sees messages stored by the same process, "postgres"
+	// uses LISTEN/NOTIFY and sees messages stored by any replica.
+	Notifier string `yaml:"notifier"`
+	// HeartbeatInterval is how often an idle stream sends a heartbeat.
+	HeartbeatInterval time.Duration `yaml:"heartbeat_interval"`
+}
+
+// GatewayConfig configures the WebSocket gateway.
+type GatewayConfig struct {
+	// MaxInFlight is how many deliveries a connection may leave unacknowledged
+	// before the gateway stops pushing to it.
+	MaxInFlight int `yaml:"max_in_flight"`
+	// WriteTimeout closes connections whose client stops reading.
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+}
+
+// PullConfig configures the pull API of pull-mode tenants.
+type PullConfig struct {
+	// VisibilityTimeout is how long a pulled message stays leased when the
+	// request does not say.
+	VisibilityTimeout time.Duration `yaml:"visibility_timeout"`
+}
+
+// RequestConfig configures request/reply messaging.
+type RequestConfig struct {
+	// Timeout is how long a request waits for its reply when it does not say.
+	Timeout time.Duration `yaml:"timeout"`
+	// MaxTimeout bounds the timeout a request may ask for.
+	MaxTimeout time.Duration `yaml:"max_timeout"`
+}
+
+// PartitionConfig configures the time sub-partitions of tenant messages.
+type PartitionConfig struct {
+	// Interval is the sub-partition size of tenants that do not set one:
+	// "daily" (default) or "monthly".
+	Interval string `yaml:"interval"`
+	// Premake is how many sub-partitions are kept created ahead of the
+	// current one; 3 when unset. 0 creates only the current one.
+	Premake int `yaml:"premake"`
+	// MaintenanceInterval is how often sub-partitions are created ahead and
+	// retention is applied.
+	MaintenanceInterval time.Duration `yaml:"maintenance_interval"`
+}
+
+// ArchiveConfig configures where archived message partitions are written.
+type ArchiveConfig struct {
+	// Storage is "local" (default) or "s3".
+	Storage string `yaml:"storage"`
+	// Directory holds the archives of local storage.
+	Directory string `yaml:"directory"`
+	// RowsPerFile bounds the rows in one compressed file.
+	RowsPerFile int      `yaml:"rows_per_file"`
+	S3          S3Config `yaml:"s3"`
+}
+
+// S3Config addresses an S3-compatible bucket.
+type S3Config struct {
+	// Endpoint is the base URL of the service, e.g. http://minio:9000.
+	Endpoint  string `yaml:"endpoint"`
+	Region    string `yaml:"region"`
+	Bucket    string `yaml:"bucket"`
+	AccessKey string `yaml:"access_key"`
+	SecretKey string `yaml:"secret_key"`
+}
+
+// MigrationsConfig configures the migrate command.
+type MigrationsConfig struct {
+	// Directory holds the migration files to run; empty runs the ones
+	// embedded in the binary. migrate create writes to it, or to
+	// ./migrations when it is empty.
+	Directory string `yaml:"directory"`
+}
+
+// WebhookConfig limits where webhook pipeline stages and replay targets may
+// send requests.
+type WebhookConfig struct {
+	// AllowedHosts, when set, are the only hosts webhooks may call. An entry
+	// starting with "." matches any subdomain of it.
+	AllowedHosts []string `yaml:"allowed_hosts"`
+	// AllowPrivate permits loopback, private and link-local addresses, which
+	// are blocked by default.
+	AllowPrivate bool `yaml:"allow_private"`
 }
 
 func Load(path string) (*Config, error) {
@@ -43,9 +203,54 @@ func Load(path string) (*Config, error) {
 	}
 
 	var config Config
+	// -1 marks premake as unset, since 0 is a valid setting
+	config.Partitions.Premake = -1
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	if config.Server.ShutdownTimeout <= 0 {
+		config.Server.ShutdownTimeout = 30 * time.Second
+	}
+	if config.Tenant.DeletionGracePeriod <= 0 {
+		config.Tenant.DeletionGracePeriod = 72 * time.Hour
+	}
+	if config.Autoscaler.Cooldown <= 0 {
+		config.Autoscaler.Cooldown = time.Minute
+	}
+	if config.Stream.HeartbeatInterval <= 0 {
+		config.Stream.HeartbeatInterval = 15 * time.Second
+	}
+	if config.Gateway.MaxInFlight <= 0 {
+		config.Gateway.MaxInFlight = 100
+	}
+	if config.Gateway.WriteTimeout <= 0 {
+		config.Gateway.WriteTimeout = 10 * time.Second
+	}
+	if config.Pull.VisibilityTimeout <= 0 {
+		config.Pull.VisibilityTimeout = 30 * time.Second
+	}
+	if config.Request.Timeout <= 0 {
+		config.Request.Timeout = 10 * time.Second
+	}
+	if config.Request.MaxTimeout <= 0 {
+		config.Request.MaxTimeout = time.Minute
+	}
+	if config.Partitions.Interval == "" {
+		config.Partitions.Interval = "daily"
+	}
+	if config.Partitions.Premake < 0 {
+		config.Partitions.Premake = 3
+	}
+	if config.Partitions.MaintenanceInterval <= 0 {
+		config.Partitions.MaintenanceInterval = time.Hour
+	}
+	if config.Archive.Directory == "" {
+		config.Archive.Directory = "archive"
+	}
+	if config.Archive.RowsPerFile <= 0 {
+		config.Archive.RowsPerFile = 100000
+	}
+
 	return &config, nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,99 @@
+// Package notifier tells interested parties that a tenant has new stored
+// messages. Notifications carry no data; subscribers read what is new from
+// the database, so a missed or coalesced notification loses nothing.
+package notifier
+
+import (
+	"context"
+	"sync"
+)
+
+type Notifier interface {
+	// Notify announces that messages were stored for the tenant.
+	Notify(ctx context.Context, tenantID string) error
+	// Subscribe returns a channel that receives a value after messages are
+	// stored for the tenant. Notifications arriving while one is pending are
+	// coalesced. The returned func unsubscribes.
+	Subscribe(tenantID string) (<-chan struct{}, func())
+	Close() error
+}
+
+// hub fans notifications out to the subscribers of a tenant.
+type hub struct {
+	mu   sync.Mutex
+	subs map[string]map[chan struct{}]struct{}
+}
+
+func newHub() *hub {
+	return &hub{subs: make(map[string]map[chan struct{}]struct{})}
+}
+
+func (h *hub) subscribe(tenantID string) (<-chan struct{}, func()) {
+	ch := make(chan struct{}, 1)
+
+	h.mu.Lock()
+	if h.subs[tenantID] == nil {
+		h.subs[tenantID] = make(map[chan struct{}]struct{})
+	}
+	h.subs[tenantID][ch] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subs[tenantID], ch)
+			if len(h.subs[tenantID]) == 0 {
+				delete(h.subs, tenantID)
+			}
+			h.mu.Unlock()
+		})
+	}
+}
+
+func (h *hub) broadcast(tenantID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs[tenantID] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// broadcastAll wakes every subscriber, for when notifications may have been
+// lost.
+func (h *hub) broadcastAll() {
+	h.mu.Lock()
+	tenantIDs := make([]string, 0, len(h.subs))
+	for tenantID := range h.subs {
+		tenantIDs = append(tenantIDs, tenantID)
+	}
+	h.mu.Unlock()
+
+	for _, tenantID := range tenantIDs {
+		h.broadcast(tenantID)
+	}
+}
+
+// Local delivers notifications within the process. It is enough when the
+// messages are stored by the same process that serves the streams.
+type Local struct {
+	hub *hub
+}
+
+func NewLocal() *Local {
+	return &Local{hub: newHub()}
+}
+
+func (l *Local) Notify(ctx context.Context, tenantID string) error {
+	l.hub.broadcast(tenantID)
+	return nil
+}
+
+func (l *Local) Subscribe(tenantID string) (<-chan struct{}, func()) {
+	return l.hub.subscribe(tenantID)
+}
+
+func (l *Local) Close() error { return nil }
@@ -0,0 +1,50 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalNotifiesSubscribersOfTenant(t *testing.T) {
+	n := NewLocal()
+	a, unsubscribeA := n.Subscribe("a")
+	defer unsubscribeA()
+	b, unsubscribeB := n.Subscribe("b")
+	defer unsubscribeB()
+
+	assert.NoError(t, n.Notify(context.Background(), "a"))
+	assert.NoError(t, n.Notify(context.Background(), "a"))
+
+	select {
+	case <-a:
+	default:
+		t.Fatal("subscriber of a was not notified")
+	}
+	select {
+	case <-a:
+		t.Fatal("notifications were not coalesced")
+	default:
+	}
+	select {
+	case <-b:
+		t.Fatal("subscriber of b was notified")
+	default:
+	}
+}
+
+func TestLocalUnsubscribe(t *testing.T) {
+	n := NewLocal()
+	ch, unsubscribe := n.Subscribe("a")
+	unsubscribe()
+	unsubscribe()
+
+	assert.NoError(t, n.Notify(context.Background(), "a"))
+	select {
+	case <-ch:
+		t.Fatal("unsubscribed channel was notified")
+	default:
+	}
+	assert.Empty(t, n.hub.subs)
+}
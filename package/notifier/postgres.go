@@ -0,0 +1,64 @@
+package notifier
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const notifyChannel = "tenant_messages"
+
+// Postgres sends notifications through LISTEN/NOTIFY, so streams served by
+// one replica see messages stored by any other.
+type Postgres struct {
+	db       *sql.DB
+	listener *pq.Listener
+	hub      *hub
+}
+
+func NewPostgres(db *sql.DB, databaseURL string) (*Postgres, error) {
+	listener := pq.NewListener(databaseURL, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("message listener: %v", err)
+		}
+	})
+	if err := listener.Listen(notifyChannel); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("failed to listen for message notifications: %w", err)
+	}
+
+	p := &Postgres{db: db, listener: listener, hub: newHub()}
+	go p.listen()
+	return p, nil
+}
+
+// listen forwards each notification to local subscribers. A nil notification
+// means the connection was re-established, so everyone is woken.
+func (p *Postgres) listen() {
+	for n := range p.listener.Notify {
+		if n == nil {
+			p.hub.broadcastAll()
+			continue
+		}
+		p.hub.broadcast(n.Extra)
+	}
+}
+
+func (p *Postgres) Notify(ctx context.Context, tenantID string) error {
+	if _, err := p.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", notifyChannel, tenantID); err != nil {
+		return fmt.Errorf("failed to notify: %w", err)
+	}
+	return nil
+}
+
+func (p *Postgres) Subscribe(tenantID string) (<-chan struct{}, func()) {
+	return p.hub.subscribe(tenantID)
+}
+
+func (p *Postgres) Close() error {
+	return p.listener.Close()
+}
@@ -0,0 +1,1720 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: multitenant/v1/tenant.proto
+
+package multitenantv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type TenantRef struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TenantRef) Reset() {
+	*x = TenantRef{}
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TenantRef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TenantRef) ProtoMessage() {}
+
+func (x *TenantRef) ProtoReflect() protoreflect.Message {
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TenantRef.ProtoReflect.Descriptor instead.
+func (*TenantRef) Descriptor() ([]byte, []int) {
+	return file_multitenant_v1_tenant_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TenantRef) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type Tenant struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name              string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	ConcurrencyConfig int32                  `protobuf:"varint,3,opt,name=concurrency_config,json=concurrencyConfig,proto3" json:"concurrency_config,omitempty"`
+	Status            string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Labels            map[string]string      `protobuf:"bytes,5,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Settings has the same shape as the settings object of the REST API.
+	Settings      *structpb.Struct       `protobuf:"bytes,6,opt,name=settings,proto3" json:"settings,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	DeletedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Tenant) Reset() {
+	*x = Tenant{}
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tenant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tenant) ProtoMessage() {}
+
+func (x *Tenant) ProtoReflect() protoreflect.Message {
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tenant.ProtoReflect.Descriptor instead.
+func (*Tenant) Descriptor() ([]byte, []int) {
+	return file_multitenant_v1_tenant_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Tenant) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Tenant) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Tenant) GetConcurrencyConfig() int32 {
+	if x != nil {
+		return x.ConcurrencyConfig
+	}
+	return 0
+}
+
+func (x *Tenant) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Tenant) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *Tenant) GetSettings() *structpb.Struct {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+func (x *Tenant) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Tenant) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Tenant) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+type CreateTenantRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Name              string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ConcurrencyConfig int32                  `protobuf:"varint,2,opt,name=concurrency_config,json=concurrencyConfig,proto3" json:"concurrency_config,omitempty"`
+	Labels            map[string]string      `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Settings          *structpb.Struct       `protobuf:"bytes,4,opt,name=settings,proto3" json:"settings,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CreateTenantRequest) Reset() {
+	*x = CreateTenantRequest{}
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateTenantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateTenantRequest) ProtoMessage() {}
+
+func (x *CreateTenantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateTenantRequest.ProtoReflect.Descriptor instead.
+func (*CreateTenantRequest) Descriptor() ([]byte, []int) {
+	return file_multitenant_v1_tenant_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateTenantRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateTenantRequest) GetConcurrencyConfig() int32 {
+	if x != nil {
+		return x.ConcurrencyConfig
+	}
+	return 0
+}
+
+func (x *CreateTenantRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *CreateTenantRequest) GetSettings() *structpb.Struct {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+type ListTenantsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Selector is a label selector such as "env=prod,tier!=free".
+	Selector      string `protobuf:"bytes,1,opt,name=selector,proto3" json:"selector,omitempty"`
+	Status        string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTenantsRequest) Reset() {
+	*x = ListTenantsRequest{}
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTenantsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTenantsRequest) ProtoMessage() {}
+
+func (x *ListTenantsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTenantsRequest.ProtoReflect.Descriptor instead.
+func (*ListTenantsRequest) Descriptor() ([]byte, []int) {
+	return file_multitenant_v1_tenant_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ListTenantsRequest) GetSelector() string {
+	if x != nil {
+		return x.Selector
+	}
+	return ""
+}
+
+func (x *ListTenantsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type ListTenantsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tenants       []*Tenant              `protobuf:"bytes,1,rep,name=tenants,proto3" json:"tenants,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTenantsResponse) Reset() {
+	*x = ListTenantsResponse{}
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTenantsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTenantsResponse) ProtoMessage() {}
+
+func (x *ListTenantsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTenantsResponse.ProtoReflect.Descriptor instead.
+func (*ListTenantsResponse) Descriptor() ([]byte, []int) {
+	return file_multitenant_v1_tenant_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListTenantsResponse) GetTenants() []*Tenant {
+	if x != nil {
+		return x.Tenants
+	}
+	return nil
+}
+
+type Labels struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        map[string]string      `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Labels) Reset() {
+	*x = Labels{}
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Labels) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Labels) ProtoMessage() {}
+
+func (x *Labels) ProtoReflect() protoreflect.Message {
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Labels.ProtoReflect.Descriptor instead.
+func (*Labels) Descriptor() ([]byte, []int) {
+	return file_multitenant_v1_tenant_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Labels) GetValues() map[string]string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+// UpdateTenantRequest is a partial update; unset fields are left unchanged.
+type UpdateTenantRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name     *string                `protobuf:"bytes,2,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	Labels   *Labels                `protobuf:"bytes,3,opt,name=labels,proto3" json:"labels,omitempty"`
+	Settings *structpb.Struct       `protobuf:"bytes,4,opt,name=settings,proto3" json:"settings,omitempty"`
+	// IfMatch is the updated_at the client last saw; the update fails with
+	// ABORTED if the tenant changed since.
+	IfMatch       *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=if_match,json=ifMatch,proto3" json:"if_match,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateTenantRequest) Reset() {
+	*x = UpdateTenantRequest{}
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateTenantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateTenantRequest) ProtoMessage() {}
+
+func (x *UpdateTenantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateTenantRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTenantRequest) Descriptor() ([]byte, []int) {
+	return file_multitenant_v1_tenant_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UpdateTenantRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateTenantRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *UpdateTenantRequest) GetLabels() *Labels {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *UpdateTenantRequest) GetSettings() *structpb.Struct {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+func (x *UpdateTenantRequest) GetIfMatch() *timestamppb.Timestamp {
+	if x != nil {
+		return x.IfMatch
+	}
+	return nil
+}
+
+type DeletionJob struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TenantId      string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Step          string                 `protobuf:"bytes,2,opt,name=step,proto3" json:"step,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Attempts      int32                  `protobuf:"varint,4,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	LastError     string                 `protobuf:"bytes,5,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeletionJob) Reset() {
+	*x = DeletionJob{}
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeletionJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeletionJob) ProtoMessage() {}
+
+func (x *DeletionJob) ProtoReflect() protoreflect.Message {
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeletionJob.ProtoReflect.Descriptor instead.
+func (*DeletionJob) Descriptor() ([]byte, []int) {
+	return file_multitenant_v1_tenant_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DeletionJob) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *DeletionJob) GetStep() string {
+	if x != nil {
+		return x.Step
+	}
+	return ""
+}
+
+func (x *DeletionJob) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *DeletionJob) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *DeletionJob) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *DeletionJob) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *DeletionJob) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type UpdateConcurrencyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Workers       int32                  `protobuf:"varint,2,opt,name=workers,proto3" json:"workers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateConcurrencyRequest) Reset() {
+	*x = UpdateConcurrencyRequest{}
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateConcurrencyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateConcurrencyRequest) ProtoMessage() {}
+
+func (x *UpdateConcurrencyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateConcurrencyRequest.ProtoReflect.Descriptor instead.
+func (*UpdateConcurrencyRequest) Descriptor() ([]byte, []int) {
+	return file_multitenant_v1_tenant_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UpdateConcurrencyRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateConcurrencyRequest) GetWorkers() int32 {
+	if x != nil {
+		return x.Workers
+	}
+	return 0
+}
+
+type BulkRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Selector string                 `protobuf:"bytes,1,opt,name=selector,proto3" json:"selector,omitempty"`
+	// Workers is only used by BulkUpdateConcurrency.
+	Workers       int32 `protobuf:"varint,2,opt,name=workers,proto3" json:"workers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkRequest) Reset() {
+	*x = BulkRequest{}
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkRequest) ProtoMessage() {}
+
+func (x *BulkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkRequest.ProtoReflect.Descriptor instead.
+func (*BulkRequest) Descriptor() ([]byte, []int) {
+	return file_multitenant_v1_tenant_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BulkRequest) GetSelector() string {
+	if x != nil {
+		return x.Selector
+	}
+	return ""
+}
+
+func (x *BulkRequest) GetWorkers() int32 {
+	if x != nil {
+		return x.Workers
+	}
+	return 0
+}
+
+type BulkResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TenantId      string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkResult) Reset() {
+	*x = BulkResult{}
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkResult) ProtoMessage() {}
+
+func (x *BulkResult) ProtoReflect() protoreflect.Message {
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkResult.ProtoReflect.Descriptor instead.
+func (*BulkResult) Descriptor() ([]byte, []int) {
+	return file_multitenant_v1_tenant_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BulkResult) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *BulkResult) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *BulkResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type BulkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*BulkResult          `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkResponse) Reset() {
+	*x = BulkResponse{}
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkResponse) ProtoMessage() {}
+
+func (x *BulkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkResponse.ProtoReflect.Descriptor instead.
+func (*BulkResponse) Descriptor() ([]byte, []int) {
+	return file_multitenant_v1_tenant_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BulkResponse) GetResults() []*BulkResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type DryRunRulesRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Id      string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Payload *structpb.Struct       `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	Headers *structpb.Struct       `protobuf:"bytes,3,opt,name=headers,proto3" json:"headers,omitempty"`
+	// Rules replace the tenant's rules for the dry run when set.
+	Rules         *structpb.ListValue `protobuf:"bytes,4,opt,name=rules,proto3" json:"rules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DryRunRulesRequest) Reset() {
+	*x = DryRunRulesRequest{}
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DryRunRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DryRunRulesRequest) ProtoMessage() {}
+
+func (x *DryRunRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DryRunRulesRequest.ProtoReflect.Descriptor instead.
+func (*DryRunRulesRequest) Descriptor() ([]byte, []int) {
+	return file_multitenant_v1_tenant_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DryRunRulesRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DryRunRulesRequest) GetPayload() *structpb.Struct {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *DryRunRulesRequest) GetHeaders() *structpb.Struct {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *DryRunRulesRequest) GetRules() *structpb.ListValue {
+	if x != nil {
+		return x.Rules
+	}
+	return nil
+}
+
+type DryRunRulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payload       *structpb.Struct       `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`
+	Headers       *structpb.Struct       `protobuf:"bytes,2,opt,name=headers,proto3" json:"headers,omitempty"`
+	Dropped       bool                   `protobuf:"varint,3,opt,name=dropped,proto3" json:"dropped,omitempty"`
+	Routes        []string               `protobuf:"bytes,4,rep,name=routes,proto3" json:"routes,omitempty"`
+	Matched       []string               `protobuf:"bytes,5,rep,name=matched,proto3" json:"matched,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DryRunRulesResponse) Reset() {
+	*x = DryRunRulesResponse{}
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DryRunRulesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DryRunRulesResponse) ProtoMessage() {}
+
+func (x *DryRunRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DryRunRulesResponse.ProtoReflect.Descriptor instead.
+func (*DryRunRulesResponse) Descriptor() ([]byte, []int) {
+	return file_multitenant_v1_tenant_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DryRunRulesResponse) GetPayload() *structpb.Struct {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *DryRunRulesResponse) GetHeaders() *structpb.Struct {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *DryRunRulesResponse) GetDropped() bool {
+	if x != nil {
+		return x.Dropped
+	}
+	return false
+}
+
+func (x *DryRunRulesResponse) GetRoutes() []string {
+	if x != nil {
+		return x.Routes
+	}
+	return nil
+}
+
+func (x *DryRunRulesResponse) GetMatched() []string {
+	if x != nil {
+		return x.Matched
+	}
+	return nil
+}
+
+type ConsumerStatus struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	TenantId       string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	State          string                 `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	Workers        int32                  `protobuf:"varint,3,opt,name=workers,proto3" json:"workers,omitempty"`
+	Prefetch       int32                  `protobuf:"varint,4,opt,name=prefetch,proto3" json:"prefetch,omitempty"`
+	InFlight       int32                  `protobuf:"varint,5,opt,name=in_flight,json=inFlight,proto3" json:"in_flight,omitempty"`
+	Processed      int64                  `protobuf:"varint,6,opt,name=processed,proto3" json:"processed,omitempty"`
+	Failed         int64                  `protobuf:"varint,7,opt,name=failed,proto3" json:"failed,omitempty"`
+	LastDeliveryAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=last_delivery_at,json=lastDeliveryAt,proto3" json:"last_delivery_at,omitempty"`
+	LastError      string                 `protobuf:"bytes,9,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	LastErrorAt    *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=last_error_at,json=lastErrorAt,proto3" json:"last_error_at,omitempty"`
+	QueueDepth     int32                  `protobuf:"varint,11,opt,name=queue_depth,json=queueDepth,proto3" json:"queue_depth,omitempty"`
+	QueueConsumers int32                  `protobuf:"varint,12,opt,name=queue_consumers,json=queueConsumers,proto3" json:"queue_consumers,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ConsumerStatus) Reset() {
+	*x = ConsumerStatus{}
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConsumerStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConsumerStatus) ProtoMessage() {}
+
+func (x *ConsumerStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConsumerStatus.ProtoReflect.Descriptor instead.
+func (*ConsumerStatus) Descriptor() ([]byte, []int) {
+	return file_multitenant_v1_tenant_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ConsumerStatus) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *ConsumerStatus) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ConsumerStatus) GetWorkers() int32 {
+	if x != nil {
+		return x.Workers
+	}
+	return 0
+}
+
+func (x *ConsumerStatus) GetPrefetch() int32 {
+	if x != nil {
+		return x.Prefetch
+	}
+	return 0
+}
+
+func (x *ConsumerStatus) GetInFlight() int32 {
+	if x != nil {
+		return x.InFlight
+	}
+	return 0
+}
+
+func (x *ConsumerStatus) GetProcessed() int64 {
+	if x != nil {
+		return x.Processed
+	}
+	return 0
+}
+
+func (x *ConsumerStatus) GetFailed() int64 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *ConsumerStatus) GetLastDeliveryAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastDeliveryAt
+	}
+	return nil
+}
+
+func (x *ConsumerStatus) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *ConsumerStatus) GetLastErrorAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastErrorAt
+	}
+	return nil
+}
+
+func (x *ConsumerStatus) GetQueueDepth() int32 {
+	if x != nil {
+		return x.QueueDepth
+	}
+	return 0
+}
+
+func (x *ConsumerStatus) GetQueueConsumers() int32 {
+	if x != nil {
+		return x.QueueConsumers
+	}
+	return 0
+}
+
+type ListScalingEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScalingEventsRequest) Reset() {
+	*x = ListScalingEventsRequest{}
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScalingEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScalingEventsRequest) ProtoMessage() {}
+
+func (x *ListScalingEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScalingEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListScalingEventsRequest) Descriptor() ([]byte, []int) {
+	return file_multitenant_v1_tenant_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListScalingEventsRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ListScalingEventsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ScalingEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	TenantId      string                 `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	FromWorkers   int32                  `protobuf:"varint,3,opt,name=from_workers,json=fromWorkers,proto3" json:"from_workers,omitempty"`
+	ToWorkers     int32                  `protobuf:"varint,4,opt,name=to_workers,json=toWorkers,proto3" json:"to_workers,omitempty"`
+	QueueDepth    int32                  `protobuf:"varint,5,opt,name=queue_depth,json=queueDepth,proto3" json:"queue_depth,omitempty"`
+	Utilization   float64                `protobuf:"fixed64,6,opt,name=utilization,proto3" json:"utilization,omitempty"`
+	Reason        string                 `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ScalingEvent) Reset() {
+	*x = ScalingEvent{}
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ScalingEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ScalingEvent) ProtoMessage() {}
+
+func (x *ScalingEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ScalingEvent.ProtoReflect.Descriptor instead.
+func (*ScalingEvent) Descriptor() ([]byte, []int) {
+	return file_multitenant_v1_tenant_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ScalingEvent) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ScalingEvent) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *ScalingEvent) GetFromWorkers() int32 {
+	if x != nil {
+		return x.FromWorkers
+	}
+	return 0
+}
+
+func (x *ScalingEvent) GetToWorkers() int32 {
+	if x != nil {
+		return x.ToWorkers
+	}
+	return 0
+}
+
+func (x *ScalingEvent) GetQueueDepth() int32 {
+	if x != nil {
+		return x.QueueDepth
+	}
+	return 0
+}
+
+func (x *ScalingEvent) GetUtilization() float64 {
+	if x != nil {
+		return x.Utilization
+	}
+	return 0
+}
+
+func (x *ScalingEvent) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ScalingEvent) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ListScalingEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*ScalingEvent        `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListScalingEventsResponse) Reset() {
+	*x = ListScalingEventsResponse{}
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListScalingEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListScalingEventsResponse) ProtoMessage() {}
+
+func (x *ListScalingEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListScalingEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListScalingEventsResponse) Descriptor() ([]byte, []int) {
+	return file_multitenant_v1_tenant_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListScalingEventsResponse) GetEvents() []*ScalingEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type Replica struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	HeartbeatAt   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=heartbeat_at,json=heartbeatAt,proto3" json:"heartbeat_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Replica) Reset() {
+	*x = Replica{}
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Replica) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Replica) ProtoMessage() {}
+
+func (x *Replica) ProtoReflect() protoreflect.Message {
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Replica.ProtoReflect.Descriptor instead.
+func (*Replica) Descriptor() ([]byte, []int) {
+	return file_multitenant_v1_tenant_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Replica) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Replica) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *Replica) GetHeartbeatAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.HeartbeatAt
+	}
+	return nil
+}
+
+type Lease struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TenantId      string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Owner         string                 `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	AcquiredAt    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=acquired_at,json=acquiredAt,proto3" json:"acquired_at,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Lease) Reset() {
+	*x = Lease{}
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Lease) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Lease) ProtoMessage() {}
+
+func (x *Lease) ProtoReflect() protoreflect.Message {
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Lease.ProtoReflect.Descriptor instead.
+func (*Lease) Descriptor() ([]byte, []int) {
+	return file_multitenant_v1_tenant_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Lease) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *Lease) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *Lease) GetAcquiredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AcquiredAt
+	}
+	return nil
+}
+
+func (x *Lease) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+type Assignments struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Replicas      []*Replica             `protobuf:"bytes,1,rep,name=replicas,proto3" json:"replicas,omitempty"`
+	Leases        []*Lease               `protobuf:"bytes,2,rep,name=leases,proto3" json:"leases,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Assignments) Reset() {
+	*x = Assignments{}
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Assignments) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Assignments) ProtoMessage() {}
+
+func (x *Assignments) ProtoReflect() protoreflect.Message {
+	mi := &file_multitenant_v1_tenant_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Assignments.ProtoReflect.Descriptor instead.
+func (*Assignments) Descriptor() ([]byte, []int) {
+	return file_multitenant_v1_tenant_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Assignments) GetReplicas() []*Replica {
+	if x != nil {
+		return x.Replicas
+	}
+	return nil
+}
+
+func (x *Assignments) GetLeases() []*Lease {
+	if x != nil {
+		return x.Leases
+	}
+	return nil
+}
+
+var File_multitenant_v1_tenant_proto protoreflect.FileDescriptor
+
+const file_multitenant_v1_tenant_proto_rawDesc = "" +
+	"\n" +
+	"\x1bmultitenant/v1/tenant.proto\x12\x0emultitenant.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x1b\n" +
+	"\tTenantRef\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xd0\x03\n" +
+	"\x06Tenant\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12-\n" +
+	"\x12concurrency_config\x18\x03 \x01(\x05R\x11concurrencyConfig\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12:\n" +
+	"\x06labels\x18\x05 \x03(\v2\".multitenant.v1.Tenant.LabelsEntryR\x06labels\x123\n" +
+	"\bsettings\x18\x06 \x01(\v2\x17.google.protobuf.StructR\bsettings\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x129\n" +
+	"\n" +
+	"deleted_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x91\x02\n" +
+	"\x13CreateTenantRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12-\n" +
+	"\x12concurrency_config\x18\x02 \x01(\x05R\x11concurrencyConfig\x12G\n" +
+	"\x06labels\x18\x03 \x03(\v2/.multitenant.v1.CreateTenantRequest.LabelsEntryR\x06labels\x123\n" +
+	"\bsettings\x18\x04 \x01(\v2\x17.google.protobuf.StructR\bsettings\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"H\n" +
+	"\x12ListTenantsRequest\x12\x1a\n" +
+	"\bselector\x18\x01 \x01(\tR\bselector\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\"G\n" +
+	"\x13ListTenantsResponse\x120\n" +
+	"\atenants\x18\x01 \x03(\v2\x16.multitenant.v1.TenantR\atenants\"\x7f\n" +
+	"\x06Labels\x12:\n" +
+	"\x06values\x18\x01 \x03(\v2\".multitenant.v1.Labels.ValuesEntryR\x06values\x1a9\n" +
+	"\vValuesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xe3\x01\n" +
+	"\x13UpdateTenantRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\x04name\x18\x02 \x01(\tH\x00R\x04name\x88\x01\x01\x12.\n" +
+	"\x06labels\x18\x03 \x01(\v2\x16.multitenant.v1.LabelsR\x06labels\x123\n" +
+	"\bsettings\x18\x04 \x01(\v2\x17.google.protobuf.StructR\bsettings\x125\n" +
+	"\bif_match\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\aifMatchB\a\n" +
+	"\x05_name\"\x87\x02\n" +
+	"\vDeletionJob\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x12\n" +
+	"\x04step\x18\x02 \x01(\tR\x04step\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1a\n" +
+	"\battempts\x18\x04 \x01(\x05R\battempts\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x05 \x01(\tR\tlastError\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"D\n" +
+	"\x18UpdateConcurrencyRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\aworkers\x18\x02 \x01(\x05R\aworkers\"C\n" +
+	"\vBulkRequest\x12\x1a\n" +
+	"\bselector\x18\x01 \x01(\tR\bselector\x12\x18\n" +
+	"\aworkers\x18\x02 \x01(\x05R\aworkers\"Y\n" +
+	"\n" +
+	"BulkResult\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"D\n" +
+	"\fBulkResponse\x124\n" +
+	"\aresults\x18\x01 \x03(\v2\x1a.multitenant.v1.BulkResultR\aresults\"\xbc\x01\n" +
+	"\x12DryRunRulesRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x121\n" +
+	"\apayload\x18\x02 \x01(\v2\x17.google.protobuf.StructR\apayload\x121\n" +
+	"\aheaders\x18\x03 \x01(\v2\x17.google.protobuf.StructR\aheaders\x120\n" +
+	"\x05rules\x18\x04 \x01(\v2\x1a.google.protobuf.ListValueR\x05rules\"\xc7\x01\n" +
+	"\x13DryRunRulesResponse\x121\n" +
+	"\apayload\x18\x01 \x01(\v2\x17.google.protobuf.StructR\apayload\x121\n" +
+	"\aheaders\x18\x02 \x01(\v2\x17.google.protobuf.StructR\aheaders\x12\x18\n" +
+	"\adropped\x18\x03 \x01(\bR\adropped\x12\x16\n" +
+	"\x06routes\x18\x04 \x03(\tR\x06routes\x12\x18\n" +
+	"\amatched\x18\x05 \x03(\tR\amatched\"\xbb\x03\n" +
+	"\x0eConsumerStatus\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x14\n" +
+	"\x05state\x18\x02 \x01(\tR\x05state\x12\x18\n" +
+	"\aworkers\x18\x03 \x01(\x05R\aworkers\x12\x1a\n" +
+	"\bprefetch\x18\x04 \x01(\x05R\bprefetch\x12\x1b\n" +
+	"\tin_flight\x18\x05 \x01(\x05R\binFlight\x12\x1c\n" +
+	"\tprocessed\x18\x06 \x01(\x03R\tprocessed\x12\x16\n" +
+	"\x06failed\x18\a \x01(\x03R\x06failed\x12D\n" +
+	"\x10last_delivery_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x0elastDeliveryAt\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\t \x01(\tR\tlastError\x12>\n" +
+	"\rlast_error_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\vlastErrorAt\x12\x1f\n" +
+	"\vqueue_depth\x18\v \x01(\x05R\n" +
+	"queueDepth\x12'\n" +
+	"\x0fqueue_consumers\x18\f \x01(\x05R\x0equeueConsumers\"@\n" +
+	"\x18ListScalingEventsRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"\x93\x02\n" +
+	"\fScalingEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1b\n" +
+	"\ttenant_id\x18\x02 \x01(\tR\btenantId\x12!\n" +
+	"\ffrom_workers\x18\x03 \x01(\x05R\vfromWorkers\x12\x1d\n" +
+	"\n" +
+	"to_workers\x18\x04 \x01(\x05R\ttoWorkers\x12\x1f\n" +
+	"\vqueue_depth\x18\x05 \x01(\x05R\n" +
+	"queueDepth\x12 \n" +
+	"\vutilization\x18\x06 \x01(\x01R\vutilization\x12\x16\n" +
+	"\x06reason\x18\a \x01(\tR\x06reason\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"Q\n" +
+	"\x19ListScalingEventsResponse\x124\n" +
+	"\x06events\x18\x01 \x03(\v2\x1c.multitenant.v1.ScalingEventR\x06events\"\x93\x01\n" +
+	"\aReplica\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x129\n" +
+	"\n" +
+	"started_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12=\n" +
+	"\fheartbeat_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\vheartbeatAt\"\xb2\x01\n" +
+	"\x05Lease\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x14\n" +
+	"\x05owner\x18\x02 \x01(\tR\x05owner\x12;\n" +
+	"\vacquired_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"acquiredAt\x129\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"q\n" +
+	"\vAssignments\x123\n" +
+	"\breplicas\x18\x01 \x03(\v2\x17.multitenant.v1.ReplicaR\breplicas\x12-\n" +
+	"\x06leases\x18\x02 \x03(\v2\x15.multitenant.v1.LeaseR\x06leases2\x80\f\n" +
+	"\rTenantService\x12K\n" +
+	"\fCreateTenant\x12#.multitenant.v1.CreateTenantRequest\x1a\x16.multitenant.v1.Tenant\x12>\n" +
+	"\tGetTenant\x12\x19.multitenant.v1.TenantRef\x1a\x16.multitenant.v1.Tenant\x12V\n" +
+	"\vListTenants\x12\".multitenant.v1.ListTenantsRequest\x1a#.multitenant.v1.ListTenantsResponse\x12K\n" +
+	"\fUpdateTenant\x12#.multitenant.v1.UpdateTenantRequest\x1a\x16.multitenant.v1.Tenant\x12A\n" +
+	"\fDeleteTenant\x12\x19.multitenant.v1.TenantRef\x1a\x16.multitenant.v1.Tenant\x12B\n" +
+	"\rRestoreTenant\x12\x19.multitenant.v1.TenantRef\x1a\x16.multitenant.v1.Tenant\x12H\n" +
+	"\x0eGetDeletionJob\x12\x19.multitenant.v1.TenantRef\x1a\x1b.multitenant.v1.DeletionJob\x12B\n" +
+	"\rSuspendTenant\x12\x19.multitenant.v1.TenantRef\x1a\x16.google.protobuf.Empty\x12A\n" +
+	"\fResumeTenant\x12\x19.multitenant.v1.TenantRef\x1a\x16.google.protobuf.Empty\x12U\n" +
+	"\x11UpdateConcurrency\x12(.multitenant.v1.UpdateConcurrencyRequest\x1a\x16.google.protobuf.Empty\x12R\n" +
+	"\x15BulkUpdateConcurrency\x12\x1b.multitenant.v1.BulkRequest\x1a\x1c.multitenant.v1.BulkResponse\x12H\n" +
+	"\vBulkSuspend\x12\x1b.multitenant.v1.BulkRequest\x1a\x1c.multitenant.v1.BulkResponse\x12G\n" +
+	"\n" +
+	"BulkResume\x12\x1b.multitenant.v1.BulkRequest\x1a\x1c.multitenant.v1.BulkResponse\x12V\n" +
+	"\vDryRunRules\x12\".multitenant.v1.DryRunRulesRequest\x1a#.multitenant.v1.DryRunRulesResponse\x12N\n" +
+	"\x11GetConsumerStatus\x12\x19.multitenant.v1.TenantRef\x1a\x1e.multitenant.v1.ConsumerStatus\x12D\n" +
+	"\x0fRestartConsumer\x12\x19.multitenant.v1.TenantRef\x1a\x16.google.protobuf.Empty\x12B\n" +
+	"\rPauseConsumer\x12\x19.multitenant.v1.TenantRef\x1a\x16.google.protobuf.Empty\x12C\n" +
+	"\x0eResumeConsumer\x12\x19.multitenant.v1.TenantRef\x1a\x16.google.protobuf.Empty\x12h\n" +
+	"\x11ListScalingEvents\x12(.multitenant.v1.ListScalingEventsRequest\x1a).multitenant.v1.ListScalingEventsResponse\x12F\n" +
+	"\x0fListAssignments\x12\x16.google.protobuf.Empty\x1a\x1b.multitenant.v1.AssignmentsB>Z<multi-tenant-service/package/pb/multitenant/v1;multitenantv1b\x06proto3"
+
+var (
+	file_multitenant_v1_tenant_proto_rawDescOnce sync.Once
+	file_multitenant_v1_tenant_proto_rawDescData []byte
+)
+
+func file_multitenant_v1_tenant_proto_rawDescGZIP() []byte {
+	file_multitenant_v1_tenant_proto_rawDescOnce.Do(func() {
+		file_multitenant_v1_tenant_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_multitenant_v1_tenant_proto_rawDesc), len(file_multitenant_v1_tenant_proto_rawDesc)))
+	})
+	return file_multitenant_v1_tenant_proto_rawDescData
+}
+
+var file_multitenant_v1_tenant_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
+var file_multitenant_v1_tenant_proto_goTypes = []any{
+	(*TenantRef)(nil),                 // 0: multitenant.v1.TenantRef
+	(*Tenant)(nil),                    // 1: multitenant.v1.Tenant
+	(*CreateTenantRequest)(nil),       // 2: multitenant.v1.CreateTenantRequest
+	(*ListTenantsRequest)(nil),        // 3: multitenant.v1.ListTenantsRequest
+	(*ListTenantsResponse)(nil),       // 4: multitenant.v1.ListTenantsResponse
+	(*Labels)(nil),                    // 5: multitenant.v1.Labels
+	(*UpdateTenantRequest)(nil),       // 6: multitenant.v1.UpdateTenantRequest
+	(*DeletionJob)(nil),               // 7: multitenant.v1.DeletionJob
+	(*UpdateConcurrencyRequest)(nil),  // 8: multitenant.v1.UpdateConcurrencyRequest
+	(*BulkRequest)(nil),               // 9: multitenant.v1.BulkRequest
+	(*BulkResult)(nil),                // 10: multitenant.v1.BulkResult
+	(*BulkResponse)(nil),              // 11: multitenant.v1.BulkResponse
+	(*DryRunRulesRequest)(nil),        // 12: multitenant.v1.DryRunRulesRequest
+	(*DryRunRulesResponse)(nil),       // 13: multitenant.v1.DryRunRulesResponse
+	(*ConsumerStatus)(nil),            // 14: multitenant.v1.ConsumerStatus
+	(*ListScalingEventsRequest)(nil),  // 15: multitenant.v1.ListScalingEventsRequest
+	(*ScalingEvent)(nil),              // 16: multitenant.v1.ScalingEvent
+	(*ListScalingEventsResponse)(nil), // 17: multitenant.v1.ListScalingEventsResponse
+	(*Replica)(nil),                   // 18: multitenant.v1.Replica
+	(*Lease)(nil),                     // 19: multitenant.v1.Lease
+	(*Assignments)(nil),               // 20: multitenant.v1.Assignments
+	nil,                               // 21: multitenant.v1.Tenant.LabelsEntry
+	nil,                               // 22: multitenant.v1.CreateTenantRequest.LabelsEntry
+	nil,                               // 23: multitenant.v1.Labels.ValuesEntry
+	(*structpb.Struct)(nil),           // 24: google.protobuf.Struct
+	(*timestamppb.Timestamp)(nil),     // 25: google.protobuf.Timestamp
+	(*structpb.ListValue)(nil),        // 26: google.protobuf.ListValue
+	(*emptypb.Empty)(nil),             // 27: google.protobuf.Empty
+}
+var file_multitenant_v1_tenant_proto_depIdxs = []int32{
+	21, // 0: multitenant.v1.Tenant.labels:type_name -> multitenant.v1.Tenant.LabelsEntry
+	24, // 1: multitenant.v1.Tenant.settings:type_name -> google.protobuf.Struct
+	25, // 2: multitenant.v1.Tenant.created_at:type_name -> google.protobuf.Timestamp
+	25, // 3: multitenant.v1.Tenant.updated_at:type_name -> google.protobuf.Timestamp
+	25, // 4: multitenant.v1.Tenant.deleted_at:type_name -> google.protobuf.Timestamp
+	22, // 5: multitenant.v1.CreateTenantRequest.labels:type_name -> multitenant.v1.CreateTenantRequest.LabelsEntry
+	24, // 6: multitenant.v1.CreateTenantRequest.settings:type_name -> google.protobuf.Struct
+	1,  // 7: multitenant.v1.ListTenantsResponse.tenants:type_name -> multitenant.v1.Tenant
+	23, // 8: multitenant.v1.Labels.values:type_name -> multitenant.v1.Labels.ValuesEntry
+	5,  // 9: multitenant.v1.UpdateTenantRequest.labels:type_name -> multitenant.v1.Labels
+	24, // 10: multitenant.v1.UpdateTenantRequest.settings:type_name -> google.protobuf.Struct
+	25, // 11: multitenant.v1.UpdateTenantRequest.if_match:type_name -> google.protobuf.Timestamp
+	25, // 12: multitenant.v1.DeletionJob.created_at:type_name -> google.protobuf.Timestamp
+	25, // 13: multitenant.v1.DeletionJob.updated_at:type_name -> google.protobuf.Timestamp
+	10, // 14: multitenant.v1.BulkResponse.results:type_name -> multitenant.v1.BulkResult
+	24, // 15: multitenant.v1.DryRunRulesRequest.payload:type_name -> google.protobuf.Struct
+	24, // 16: multitenant.v1.DryRunRulesRequest.headers:type_name -> google.protobuf.Struct
+	26, // 17: multitenant.v1.DryRunRulesRequest.rules:type_name -> google.protobuf.ListValue
+	24, // 18: multitenant.v1.DryRunRulesResponse.payload:type_name -> google.protobuf.Struct
+	24, // 19: multitenant.v1.DryRunRulesResponse.headers:type_name -> google.protobuf.Struct
+	25, // 20: multitenant.v1.ConsumerStatus.last_delivery_at:type_name -> google.protobuf.Timestamp
+	25, // 21: multitenant.v1.ConsumerStatus.last_error_at:type_name -> google.protobuf.Timestamp
+	25, // 22: multitenant.v1.ScalingEvent.created_at:type_name -> google.protobuf.Timestamp
+	16, // 23: multitenant.v1.ListScalingEventsResponse.events:type_name -> multitenant.v1.ScalingEvent
+	25, // 24: multitenant.v1.Replica.started_at:type_name -> google.protobuf.Timestamp
+	25, // 25: multitenant.v1.Replica.heartbeat_at:type_name -> google.protobuf.Timestamp
+	25, // 26: multitenant.v1.Lease.acquired_at:type_name -> google.protobuf.Timestamp
+	25, // 27: multitenant.v1.Lease.expires_at:type_name -> google.protobuf.Timestamp
+	18, // 28: multitenant.v1.Assignments.replicas:type_name -> multitenant.v1.Replica
+	19, // 29: multitenant.v1.Assignments.leases:type_name -> multitenant.v1.Lease
+	2,  // 30: multitenant.v1.TenantService.CreateTenant:input_type -> multitenant.v1.CreateTenantRequest
+	0,  // 31: multitenant.v1.TenantService.GetTenant:input_type -> multitenant.v1.TenantRef
+	3,  // 32: multitenant.v1.TenantService.ListTenants:input_type -> multitenant.v1.ListTenantsRequest
+	6,  // 33: multitenant.v1.TenantService.UpdateTenant:input_type -> multitenant.v1.UpdateTenantRequest
+	0,  // 34: multitenant.v1.TenantService.DeleteTenant:input_type -> multitenant.v1.TenantRef
+	0,  // 35: multitenant.v1.TenantService.RestoreTenant:input_type -> multitenant.v1.TenantRef
+	0,  // 36: multitenant.v1.TenantService.GetDeletionJob:input_type -> multitenant.v1.TenantRef
+	0,  // 37: multitenant.v1.TenantService.SuspendTenant:input_type -> multitenant.v1.TenantRef
+	0,  // 38: multitenant.v1.TenantService.ResumeTenant:input_type -> multitenant.v1.TenantRef
+	8,  // 39: multitenant.v1.TenantService.UpdateConcurrency:input_type -> multitenant.v1.UpdateConcurrencyRequest
+	9,  // 40: multitenant.v1.TenantService.BulkUpdateConcurrency:input_type -> multitenant.v1.BulkRequest
+	9,  // 41: multitenant.v1.TenantService.BulkSuspend:input_type -> multitenant.v1.BulkRequest
+	9,  // 42: multitenant.v1.TenantService.BulkResume:input_type -> multitenant.v1.BulkRequest
+	12, // 43: multitenant.v1.TenantService.DryRunRules:input_type -> multitenant.v1.DryRunRulesRequest
+	0,  // 44: multitenant.v1.TenantService.GetConsumerStatus:input_type -> multitenant.v1.TenantRef
+	0,  // 45: multitenant.v1.TenantService.RestartConsumer:input_type -> multitenant.v1.TenantRef
+	0,  // 46: multitenant.v1.TenantService.PauseConsumer:input_type -> multitenant.v1.TenantRef
+	0,  // 47: multitenant.v1.TenantService.ResumeConsumer:input_type -> multitenant.v1.TenantRef
+	15, // 48: multitenant.v1.TenantService.ListScalingEvents:input_type -> multitenant.v1.ListScalingEventsRequest
+	27, // 49: multitenant.v1.TenantService.ListAssignments:input_type -> google.protobuf.Empty
+	1,  // 50: multitenant.v1.TenantService.CreateTenant:output_type -> multitenant.v1.Tenant
+	1,  // 51: multitenant.v1.TenantService.GetTenant:output_type -> multitenant.v1.Tenant
+	4,  // 52: multitenant.v1.TenantService.ListTenants:output_type -> multitenant.v1.ListTenantsResponse
+	1,  // 53: multitenant.v1.TenantService.UpdateTenant:output_type -> multitenant.v1.Tenant
+	1,  // 54: multitenant.v1.TenantService.DeleteTenant:output_type -> multitenant.v1.Tenant
+	1,  // 55: multitenant.v1.TenantService.RestoreTenant:output_type -> multitenant.v1.Tenant
+	7,  // 56: multitenant.v1.TenantService.GetDeletionJob:output_type -> multitenant.v1.DeletionJob
+	27, // 57: multitenant.v1.TenantService.SuspendTenant:output_type -> google.protobuf.Empty
+	27, // 58: multitenant.v1.TenantService.ResumeTenant:output_type -> google.protobuf.Empty
+	27, // 59: multitenant.v1.TenantService.UpdateConcurrency:output_type -> google.protobuf.Empty
+	11, // 60: multitenant.v1.TenantService.BulkUpdateConcurrency:output_type -> multitenant.v1.BulkResponse
+	11, // 61: multitenant.v1.TenantService.BulkSuspend:output_type -> multitenant.v1.BulkResponse
+	11, // 62: multitenant.v1.TenantService.BulkResume:output_type -> multitenant.v1.BulkResponse
+	13, // 63: multitenant.v1.TenantService.DryRunRules:output_type -> multitenant.v1.DryRunRulesResponse
+	14, // 64: multitenant.v1.TenantService.GetConsumerStatus:output_type -> multitenant.v1.ConsumerStatus
+	27, // 65: multitenant.v1.TenantService.RestartConsumer:output_type -> google.protobuf.Empty
+	27, // 66: multitenant.v1.TenantService.PauseConsumer:output_type -> google.protobuf.Empty
+	27, // 67: multitenant.v1.TenantService.ResumeConsumer:output_type -> google.protobuf.Empty
+	17, // 68: multitenant.v1.TenantService.ListScalingEvents:output_type -> multitenant.v1.ListScalingEventsResponse
+	20, // 69: multitenant.v1.TenantService.ListAssignments:output_type -> multitenant.v1.Assignments
+	50, // [50:70] is the sub-list for method output_type
+	30, // [30:50] is the sub-list for method input_type
+	30, // [30:30] is the sub-list for extension type_name
+	30, // [30:30] is the sub-list for extension extendee
+	0,  // [0:30] is the sub-list for field type_name
+}
+
+func init() { file_multitenant_v1_tenant_proto_init() }
+func file_multitenant_v1_tenant_proto_init() {
+	if File_multitenant_v1_tenant_proto != nil {
+		return
+	}
+	file_multitenant_v1_tenant_proto_msgTypes[6].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_multitenant_v1_tenant_proto_rawDesc), len(file_multitenant_v1_tenant_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   24,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_multitenant_v1_tenant_proto_goTypes,
+		DependencyIndexes: file_multitenant_v1_tenant_proto_depIdxs,
+		MessageInfos:      file_multitenant_v1_tenant_proto_msgTypes,
+	}.Build()
+	File_multitenant_v1_tenant_proto = out.File
+	file_multitenant_v1_tenant_proto_goTypes = nil
+	file_multitenant_v1_tenant_proto_depIdxs = nil
+}
@@ -0,0 +1,49 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+type enrichConfig struct {
+	// Fields are added when the payload does not set them.
+	Fields map[string]interface{} `json:"fields"`
+	// TenantField, if set, receives the tenant ID.
+	TenantField string `json:"tenant_field"`
+	// TimestampField, if set, receives the processing time in RFC 3339.
+	TimestampField string `json:"timestamp_field"`
+}
+
+type enrich struct {
+	config enrichConfig
+	now    func() time.Time
+}
+
+// NewEnrich builds a stage that adds fixed fields, the tenant ID and the
+// processing time to the payload.
+func NewEnrich(config json.RawMessage) (Processor, error) {
+	e := &enrich{now: time.Now}
+	if err := decodeConfig(config, &e.config); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *enrich) Process(ctx context.Context, msg *Message) error {
+	if msg.Payload == nil {
+		msg.Payload = map[string]interface{}{}
+	}
+	for field, value := range e.config.Fields {
+		if _, ok := msg.Payload[field]; !ok {
+			msg.Payload[field] = value
+		}
+	}
+	if e.config.TenantField != "" {
+		msg.Payload[e.config.TenantField] = msg.TenantID
+	}
+	if e.config.TimestampField != "" {
+		msg.Payload[e.config.TimestampField] = e.now().UTC().Format(time.RFC3339Nano)
+	}
+	return nil
+}
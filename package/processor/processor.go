@@ -0,0 +1,126 @@
+// Package processor runs consumed tenant messages through an ordered
+// pipeline of stages. The error a stage returns decides whether the
+// delivery is acked, retried or dead-lettered.
+package processor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Message is the decoded message a pipeline works on. Stages may change the
+// payload in place; later stages see the changes.
+type Message struct {
+	TenantID string
+	Payload  map[string]interface{}
+	Headers  map[string]interface{}
+	// Attempt counts deliveries of this message, starting at 1.
+	Attempt int
+	// Replayed marks a message a replay job republished. It is read from
+	// the delivery before any stage runs, so rules that rewrite headers
+	// cannot change it.
+	Replayed bool
+	// Reply is what a request is answered with. A stage may set it; when
+	// none does, the request is answered with the final payload.
+	Reply map[string]interface{}
+}
+
+type Processor interface {
+	Process(ctx context.Context, msg *Message) error
+}
+
+// Func adapts a plain function to a Processor.
+type Func func(ctx context.Context, msg *Message) error
+
+func (f Func) Process(ctx context.Context, msg *Message) error {
+	return f(ctx, msg)
+}
+
+type Outcome int
+
+const (
+	// OutcomeAck acknowledges the message.
+	OutcomeAck Outcome = iota
+	// OutcomeRetry delivers the message again, up to the attempt limit.
+	OutcomeRetry
+	// OutcomeDeadLetter moves the message to the tenant's dead-letter queue.
+	OutcomeDeadLetter
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeAck:
+		return "ack"
+	case OutcomeRetry:
+		return "retry"
+	case OutcomeDeadLetter:
+		return "dead_letter"
+	}
+	return fmt.Sprintf("outcome(%d)", int(o))
+}
+
+// ErrDrop stops the pipeline and acks the message without running the
+// remaining stages.
+var ErrDrop = errors.New("message dropped")
+
+type outcomeError struct {
+	outcome Outcome
+	err     error
+}
+
+func (e *outcomeError) Error() string { return e.err.Error() }
+func (e *outcomeError) Unwrap() error { return e.err }
+
+// Retry marks err as transient so the message is delivered again.
+func Retry(err error) error {
+	return &outcomeError{outcome: OutcomeRetry, err: err}
+}
+
+// DeadLetter marks err as permanent so the message is not retried.
+func DeadLetter(err error) error {
+	return &outcomeError{outcome: OutcomeDeadLetter, err: err}
+}
+
+// OutcomeOf maps a pipeline error to what should happen to the delivery.
+// Errors not marked with Retry or DeadLetter are retried.
+func OutcomeOf(err error) Outcome {
+	if err == nil || errors.Is(err, ErrDrop) {
+		return OutcomeAck
+	}
+	var oe *outcomeError
+	if errors.As(err, &oe) {
+		return oe.outcome
+	}
+	return OutcomeRetry
+}
+
+// Stage is a named processor inside a pipeline.
+type Stage struct {
+	Name      string
+	Processor Processor
+}
+
+// Pipeline runs its stages in order and stops at the first error.
+type Pipeline []Stage
+
+func (p Pipeline) Process(ctx context.Context, msg *Message) error {
+	for _, stage := range p {
+		if err := stage.Processor.Process(ctx, msg); err != nil {
+			if errors.Is(err, ErrDrop) {
+				return nil
+			}
+			return &StageError{Stage: stage.Name, Err: err}
+		}
+	}
+	return nil
+}
+
+// StageError tells which stage of a pipeline failed.
+type StageError struct {
+	Stage string
+	Err   error
+}
+
+func (e *StageError) Error() string { return fmt.Sprintf("stage %s: %v", e.Stage, e.Err) }
+func (e *StageError) Unwrap() error { return e.Err }
@@ -0,0 +1,126 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"multi-tenant-service/package/structs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutcomeOf(t *testing.T) {
+	assert.Equal(t, OutcomeAck, OutcomeOf(nil))
+	assert.Equal(t, OutcomeAck, OutcomeOf(ErrDrop))
+	assert.Equal(t, OutcomeRetry, OutcomeOf(errors.New("boom")))
+	assert.Equal(t, OutcomeRetry, OutcomeOf(&StageError{Stage: "x", Err: Retry(errors.New("boom"))}))
+	assert.Equal(t, OutcomeDeadLetter, OutcomeOf(&StageError{Stage: "x", Err: DeadLetter(errors.New("boom"))}))
+}
+
+func TestPipeline(t *testing.T) {
+	registry := NewRegistry()
+	var persisted map[string]interface{}
+	registry.RegisterHandler("persist", Func(func(ctx context.Context, msg *Message) error {
+		persisted = msg.Payload
+		return nil
+	}))
+	registry.RegisterHandler("drop_test", Func(func(ctx context.Context, msg *Message) error {
+		if msg.Payload["test"] == true {
+			return ErrDrop
+		}
+		return nil
+	}))
+
+	pipeline, err := registry.Build([]structs.PipelineStage{
+		{Type: "validate", Config: json.RawMessage(`{"required":["order_id"],"types":{"amount":"number"}}`)},
+		{Type: "transform", Config: json.RawMessage(`{"rename":{"order_id":"id"},"remove":["secret"]}`)},
+		{Type: "enrich", Config: json.RawMessage(`{"fields":{"source":"api"},"tenant_field":"tenant"}`)},
+		{Type: "drop_test"},
+		{Type: "persist"},
+	})
+	require.NoError(t, err)
+
+	msg := &Message{TenantID: "t1", Payload: map[string]interface{}{"order_id": "o1", "amount": 3.5, "secret": "x"}}
+	require.NoError(t, pipeline.Process(context.Background(), msg))
+	assert.Equal(t, map[string]interface{}{"id": "o1", "amount": 3.5, "source": "api", "tenant": "t1"}, persisted)
+
+	persisted = nil
+	msg = &Message{TenantID: "t1", Payload: map[string]interface{}{"order_id": "o2", "test": true}}
+	require.NoError(t, pipeline.Process(context.Background(), msg))
+	assert.Nil(t, persisted)
+
+	err = pipeline.Process(context.Background(), &Message{Payload: map[string]interface{}{"amount": "3"}})
+	var stageErr *StageError
+	require.ErrorAs(t, err, &stageErr)
+	assert.Equal(t, "validate", stageErr.Stage)
+	assert.Equal(t, OutcomeDeadLetter, OutcomeOf(err))
+}
+
+func TestBuildRejectsInvalidStages(t *testing.T) {
+	registry := NewRegistry()
+
+	_, err := registry.Build([]structs.PipelineStage{{Type: "missing"}})
+	assert.Error(t, err)
+
+	_, err = registry.Build([]structs.PipelineStage{{Type: "transform", Config: json.RawMessage(`{"renmae":{}}`)}})
+	assert.Error(t, err)
+
+	_, err = registry.Build([]structs.PipelineStage{{Type: "webhook", Config: json.RawMessage(`{"url":"ftp://x"}`)}})
+	assert.Error(t, err)
+}
+
+func TestWebhookOutcomes(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "t1", r.Header.Get("X-Tenant-ID"))
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	p, err := NewWebhookFactory(WebhookPolicy{AllowPrivate: true})(json.RawMessage(`{"url":"` + server.URL + `"}`))
+	require.NoError(t, err)
+	msg := &Message{TenantID: "t1", Payload: map[string]interface{}{"a": 1}}
+
+	assert.Equal(t, OutcomeAck, OutcomeOf(p.Process(context.Background(), msg)))
+	status = http.StatusServiceUnavailable
+	assert.Equal(t, OutcomeRetry, OutcomeOf(p.Process(context.Background(), msg)))
+	status = http.StatusBadRequest
+	assert.Equal(t, OutcomeDeadLetter, OutcomeOf(p.Process(context.Background(), msg)))
+}
+
+func TestWebhookReply(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"total": 3}`))
+	}))
+	defer server.Close()
+
+	p, err := NewWebhookFactory(WebhookPolicy{AllowPrivate: true})(json.RawMessage(`{"url":"` + server.URL + `","reply":true}`))
+	require.NoError(t, err)
+	msg := &Message{TenantID: "t1", Payload: map[string]interface{}{"a": 1}}
+	require.NoError(t, p.Process(context.Background(), msg))
+	assert.Equal(t, map[string]interface{}{"total": float64(3)}, msg.Reply)
+}
+
+func TestWebhookBlocksPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached a loopback address")
+	}))
+	defer server.Close()
+
+	_, err := NewWebhook(json.RawMessage(`{"url":"` + server.URL + `"}`))
+	assert.ErrorIs(t, err, ErrWebhookAddressBlocked)
+	_, err = NewWebhookFactory(WebhookPolicy{AllowedHosts: []string{".example.com"}})(json.RawMessage(`{"url":"https://internal.local/"}`))
+	assert.ErrorIs(t, err, ErrWebhookAddressBlocked)
+
+	// Names are checked once resolved
+	p, err := NewWebhook(json.RawMessage(`{"url":"` + strings.Replace(server.URL, "127.0.0.1", "localhost", 1) + `"}`))
+	require.NoError(t, err)
+	err = p.Process(context.Background(), &Message{TenantID: "t1", Payload: map[string]interface{}{}})
+	assert.Equal(t, OutcomeDeadLetter, OutcomeOf(err))
+}
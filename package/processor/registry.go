@@ -0,0 +1,94 @@
+package processor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"multi-tenant-service/package/structs"
+	"sort"
+	"sync"
+)
+
+// Factory builds a processor from the stage config stored on a tenant.
+type Factory func(config json.RawMessage) (Processor, error)
+
+// Registry holds the stage types pipelines can be built from. Custom Go
+// handlers are added at startup with Register or RegisterHandler.
+type Registry struct {
+	mu        sync.RWMutex
+	factories map[string]Factory
+}
+
+// NewRegistry returns a registry with the built-in validate, transform,
+// enrich and webhook stages.
+func NewRegistry() *Registry {
+	r := &Registry{factories: make(map[string]Factory)}
+	r.Register("validate", NewValidate)
+	r.Register("transform", NewTransform)
+	r.Register("enrich", NewEnrich)
+	r.Register("webhook", NewWebhook)
+	return r
+}
+
+func (r *Registry) Register(name string, factory Factory) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.factories[name] = factory
+}
+
+// RegisterHandler adds a stage type that takes no config.
+func (r *Registry) RegisterHandler(name string, p Processor) {
+	r.Register(name, func(json.RawMessage) (Processor, error) { return p, nil })
+}
+
+func (r *Registry) Types() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	types := make([]string, 0, len(r.factories))
+	for name := range r.factories {
+		types = append(types, name)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// New builds a single processor of a registered stage type.
+func (r *Registry) New(stageType string, config json.RawMessage) (Processor, error) {
+	r.mu.RLock()
+	factory, ok := r.factories[stageType]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown pipeline stage %q", stageType)
+	}
+	return factory(config)
+}
+
+// Build turns the stage configs of a tenant into a pipeline.
+func (r *Registry) Build(stages []structs.PipelineStage) (Pipeline, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	pipeline := make(Pipeline, 0, len(stages))
+	for i, stage := range stages {
+		factory, ok := r.factories[stage.Type]
+		if !ok {
+			return nil, fmt.Errorf("unknown pipeline stage %q", stage.Type)
+		}
+		p, err := factory(stage.Config)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pipeline stage %d (%s): %w", i, stage.Type, err)
+		}
+		pipeline = append(pipeline, Stage{Name: stage.Type, Processor: p})
+	}
+	return pipeline, nil
+}
+
+// decodeConfig unmarshals an optional stage config, rejecting unknown fields.
+func decodeConfig(config json.RawMessage, dest interface{}) error {
+	if len(config) == 0 || string(config) == "null" {
+		return nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(config))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(dest)
+}
@@ -0,0 +1,48 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+)
+
+type transformConfig struct {
+	// Rename moves a field to a new name.
+	Rename map[string]string `json:"rename"`
+	// Set overwrites fields with fixed values.
+	Set map[string]interface{} `json:"set"`
+	// Remove deletes fields.
+	Remove []string `json:"remove"`
+}
+
+type transform struct {
+	config transformConfig
+}
+
+// NewTransform builds a stage that renames, sets and removes payload
+// fields, in that order.
+func NewTransform(config json.RawMessage) (Processor, error) {
+	t := &transform{}
+	if err := decodeConfig(config, &t.config); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *transform) Process(ctx context.Context, msg *Message) error {
+	if msg.Payload == nil {
+		msg.Payload = map[string]interface{}{}
+	}
+	for from, to := range t.config.Rename {
+		if value, ok := msg.Payload[from]; ok {
+			delete(msg.Payload, from)
+			msg.Payload[to] = value
+		}
+	}
+	for field, value := range t.config.Set {
+		msg.Payload[field] = value
+	}
+	for _, field := range t.config.Remove {
+		delete(msg.Payload, field)
+	}
+	return nil
+}
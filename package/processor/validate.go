@@ -0,0 +1,72 @@
+package processor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+type validateConfig struct {
+	// Required fields must be present in the payload.
+	Required []string `json:"required"`
+	// Types maps a field to string, number, bool, object or array.
+	Types map[string]string `json:"types"`
+}
+
+type validate struct {
+	config validateConfig
+}
+
+// NewValidate builds a stage that dead-letters payloads missing required
+// fields or holding fields of the wrong type.
+func NewValidate(config json.RawMessage) (Processor, error) {
+	v := &validate{}
+	if err := decodeConfig(config, &v.config); err != nil {
+		return nil, err
+	}
+	for field, kind := range v.config.Types {
+		switch kind {
+		case "string", "number", "bool", "object", "array":
+		default:
+			return nil, fmt.Errorf("unknown type %q for field %s", kind, field)
+		}
+	}
+	return v, nil
+}
+
+func (v *validate) Process(ctx context.Context, msg *Message) error {
+	for _, field := range v.config.Required {
+		if _, ok := msg.Payload[field]; !ok {
+			return DeadLetter(fmt.Errorf("missing required field %s", field))
+		}
+	}
+	for field, kind := range v.config.Types {
+		value, ok := msg.Payload[field]
+		if !ok {
+			continue
+		}
+		if typeOf(value) != kind {
+			return DeadLetter(fmt.Errorf("field %s must be %s", field, kind))
+		}
+	}
+	return nil
+}
+
+// typeOf names the JSON type of a decoded value.
+func typeOf(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case float64, int, int64, float32, int32:
+		return "number"
+	case bool:
+		return "bool"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", value)
+}
@@ -0,0 +1,218 @@
+package processor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrWebhookAddressBlocked is returned when a webhook resolves to an address
+// its policy does not allow.
+var ErrWebhookAddressBlocked = errors.New("webhook address is not allowed")
+
+// blockedNetworks are non-public ranges not covered by the net.IP helpers.
+var blockedNetworks = mustParseCIDRs("0.0.0.0/8", "100.64.0.0/10", "192.0.0.0/24", "198.18.0.0/15", "240.0.0.0/4")
+
+// WebhookPolicy limits where webhook stages may send requests. Webhook URLs
+// come from tenant settings, so by default only public addresses can be
+// reached and internal services stay out of reach.
+type WebhookPolicy struct {
+	// AllowedHosts, when set, are the only hosts webhooks may call. An entry
+	// starting with "." matches any subdomain of it.
+	AllowedHosts []string
+	// AllowPrivate permits loopback, private and link-local addresses, for
+	// webhooks served inside the deployment.
+	AllowPrivate bool
+}
+
+type webhookConfig struct {
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+	// Timeout is a Go duration such as "5s"; it defaults to 10s.
+	Timeout string `json:"timeout"`
+	// Reply answers requests with the JSON object the webhook responds with.
+	Reply bool `json:"reply"`
+}
+
+type webhook struct {
+	config webhookConfig
+	client *http.Client
+}
+
+// NewWebhook builds a webhook stage under the default policy, which only
+// allows public addresses.
+func NewWebhook(config json.RawMessage) (Processor, error) {
+	return newWebhook(config, WebhookPolicy{})
+}
+
+// NewWebhookFactory returns a factory for webhook stages that enforce policy.
+func NewWebhookFactory(policy WebhookPolicy) Factory {
+	return func(config json.RawMessage) (Processor, error) {
+		return newWebhook(config, policy)
+	}
+}
+
+// newWebhook builds a stage that POSTs the payload as JSON. Network errors
+// and 5xx or 429 responses are retried; other non-2xx responses and
+// addresses the policy blocks dead-letter the message.
+func newWebhook(config json.RawMessage, policy WebhookPolicy) (Processor, error) {
+	w := &webhook{}
+	if err := decodeConfig(config, &w.config); err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(w.config.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("url must be an absolute http or https URL")
+	}
+	if err := policy.checkHost(u.Hostname()); err != nil {
+		return nil, err
+	}
+
+	timeout := 10 * time.Second
+	if w.config.Timeout != "" {
+		parsed, err := time.ParseDuration(w.config.Timeout)
+		if err != nil || parsed <= 0 {
+			return nil, fmt.Errorf("invalid timeout %q", w.config.Timeout)
+		}
+		timeout = parsed
+	}
+	w.client = policy.client(timeout)
+	return w, nil
+}
+
+// checkHost rejects hosts outside AllowedHosts and literal addresses that are
+// not allowed. Names are checked again once resolved, on every connection.
+func (p WebhookPolicy) checkHost(host string) error {
+	if len(p.AllowedHosts) > 0 {
+		host = strings.ToLower(strings.TrimSuffix(host, "."))
+		allowed := false
+		for _, entry := range p.AllowedHosts {
+			entry = strings.ToLower(entry)
+			if host == entry || (strings.HasPrefix(entry, ".") && strings.HasSuffix(host, entry)) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("%w: host %s is not in the allowed hosts", ErrWebhookAddressBlocked, host)
+		}
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		return p.checkIP(ip)
+	}
+	return nil
+}
+
+func (p WebhookPolicy) checkIP(ip net.IP) error {
+	if p.AllowPrivate {
+		return nil
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() {
+		return fmt.Errorf("%w: %s is not a public address", ErrWebhookAddressBlocked, ip)
+	}
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return fmt.Errorf("%w: %s is not a public address", ErrWebhookAddressBlocked, ip)
+		}
+	}
+	return nil
+}
+
+// client checks the address of every connection after DNS resolution, so a
+// name cannot be pointed at an internal address later, and the host of every
+// redirect. Proxies are not used, as they would hide the real address.
+func (p WebhookPolicy) client(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: timeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil {
+				return fmt.Errorf("%w: %s", ErrWebhookAddressBlocked, address)
+			}
+			return p.checkIP(ip)
+		},
+	}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConnsPerHost: 10,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return fmt.Errorf("stopped after 10 redirects")
+			}
+			return p.checkHost(req.URL.Hostname())
+		},
+	}
+}
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
+
+func (w *webhook) Process(ctx context.Context, msg *Message) error {
+	body, err := json.Marshal(msg.Payload)
+	if err != nil {
+		return DeadLetter(fmt.Errorf("failed to marshal payload: %w", err))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return DeadLetter(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Tenant-ID", msg.TenantID)
+	for key, value := range w.config.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := w.client.Do(req)
+	if errors.Is(err, ErrWebhookAddressBlocked) {
+		return DeadLetter(fmt.Errorf("webhook request failed: %w", err))
+	}
+	if err != nil {
+		return Retry(fmt.Errorf("webhook request failed: %w", err))
+	}
+	defer resp.Body.Close()
+	// Drain the body so the connection can be reused
+	defer io.Copy(io.Discard, resp.Body)
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		if w.config.Reply {
+			var reply map[string]interface{}
+			if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+				return DeadLetter(fmt.Errorf("invalid webhook reply: %w", err))
+			}
+			msg.Reply = reply
+		}
+		return nil
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+		return Retry(fmt.Errorf("webhook returned %d", resp.StatusCode))
+	}
+	return DeadLetter(fmt.Errorf("webhook returned %d", resp.StatusCode))
+}
@@ -0,0 +1,255 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"multi-tenant-service/package/broker"
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// channelPoolSize bounds the idle channels kept for queue operations and
+// publishing.
+const channelPoolSize = 16
+
+// Broker implements broker.Broker on top of a RabbitMQ Client. Every
+// subscription gets its own channel so prefetch applies per consumer.
+type Broker struct {
+	client *Client
+	// channels holds idle channels. Each call takes one for itself, so a
+	// failing call only loses its own channel.
+	channels chan *amqp.Channel
+}
+
+var _ broker.Broker = (*Broker)(nil)
+
+func NewBroker(client *Client) *Broker {
+	return &Broker{
+		client:   client,
+		channels: make(chan *amqp.Channel, channelPoolSize),
+	}
+}
+
+// withChannel runs fn on a channel no other call uses meanwhile. AMQP closes
+// a channel on any operation error, so a channel that saw an error is closed
+// instead of going back to the pool.
+func (b *Broker) withChannel(fn func(ch *amqp.Channel) error) error {
+	var ch *amqp.Channel
+	select {
+	case ch = <-b.channels:
+	default:
+	}
+	if ch == nil || ch.IsClosed() {
+		var err error
+		if ch, err = b.client.OpenChannel(); err != nil {
+			return err
+		}
+	}
+
+	if err := fn(ch); err != nil {
+		ch.Close()
+		return err
+	}
+	select {
+	case b.channels <- ch:
+	default:
+		ch.Close()
+	}
+	return nil
+}
+
+func (b *Broker) DeclareQueue(ctx context.Context, name string, opts broker.QueueOptions) (broker.QueueInfo, error) {
+	var info broker.QueueInfo
+	err := b.withChannel(func(ch *amqp.Channel) error {
+		declare := b.client.DeclareQueue
+		if opts.AutoDelete {
+			declare = b.client.DeclareTemporaryQueue
+		}
+		q, err := declare(ch, name)
+		info = queueInfo(q)
+		return err
+	})
+	return info, err
+}
+
+func (b *Broker) InspectQueue(ctx context.Context, name string) (broker.QueueInfo, error) {
+	var info broker.QueueInfo
+	err := b.withChannel(func(ch *amqp.Channel) error {
+		q, err := ch.QueueDeclarePassive(name, true, false, false, false, nil)
+		info = queueInfo(q)
+		return err
+	})
+	var amqpErr *amqp.Error
+	if errors.As(err, &amqpErr) && amqpErr.Code == amqp.NotFound {
+		return info, broker.ErrQueueNotFound
+	}
+	return info, err
+}
+
+func (b *Broker) PurgeQueue(ctx context.Context, name string) (int, error) {
+	var purged int
+	err := b.withChannel(func(ch *amqp.Channel) error {
+		var err error
+		purged, err = b.client.PurgeQueue(ch, name)
+		return err
+	})
+	return purged, err
+}
+
+func (b *Broker) DeleteQueue(ctx context.Context, name string) error {
+	return b.withChannel(func(ch *amqp.Channel) error {
+		return b.client.DeleteQueue(ch, name)
+	})
+}
+
+// Publish sends delayed messages through a delay queue whose messages expire
+// after the delay and are dead-lettered into queue.
+func (b *Broker) Publish(ctx context.Context, queue string, msg broker.Message) error {
+	return b.withChannel(func(ch *amqp.Channel) error {
+		if msg.Delay > 0 {
+			delayQueue, err := b.client.DeclareDelayQueue(ch, queue, msg.Delay)
+			if err != nil {
+				return fmt.Errorf("failed to declare delay queue: %w", err)
+			}
+			queue = delayQueue
+		}
+		return ch.PublishWithContext(ctx,
+			"",    // exchange
+			queue, // routing key
+			false, // mandatory
+			false, // immediate
+			amqp.Publishing{
+				ContentType:   msg.ContentType,
+				Headers:       amqp.Table(msg.Headers),
+				CorrelationId: msg.CorrelationID,
+				ReplyTo:       msg.ReplyTo,
+				Body:          msg.Body,
+			})
+	})
+}
+
+func (b *Broker) Consume(ctx context.Context, queue string, opts broker.ConsumeOptions) (broker.Subscription, error) {
+	channelName := fmt.Sprintf("consumer_%s", opts.Tag)
+	ch, err := b.client.CreateChannel(channelName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ch.Qos(opts.Prefetch, 0, false); err != nil {
+		b.client.CloseChannel(channelName)
+		return nil, fmt.Errorf("failed to set prefetch: %w", err)
+	}
+
+	msgs, err := ch.Consume(
+		queue,
+		opts.Tag,
+		false, // auto-ack
+		opts.Exclusive,
+		false, // no-local
+		false, // no-wait
+		nil,   // args
+	)
+	if err != nil {
+		b.client.CloseChannel(channelName)
+		return nil, err
+	}
+
+	sub := &subscription{
+		client:      b.client,
+		channel:     ch,
+		channelName: channelName,
+		tag:         opts.Tag,
+		deliveries:  make(chan broker.Delivery),
+		done:        make(chan struct{}),
+	}
+	go sub.forward(msgs)
+	return sub, nil
+}
+
+func (b *Broker) Close() error {
+	for {
+		select {
+		case ch := <-b.channels:
+			ch.Close()
+		default:
+			return b.client.Close()
+		}
+	}
+}
+
+func queueInfo(q amqp.Queue) broker.QueueInfo {
+	return broker.QueueInfo{Name: q.Name, Messages: q.Messages, Consumers: q.Consumers}
+}
+
+type subscription struct {
+	client      *Client
+	channel     *amqp.Channel
+	channelName string
+	tag         string
+	deliveries  chan broker.Delivery
+	done        chan struct{}
+	closeOnce   sync.Once
+}
+
+// forward converts AMQP deliveries until the server stops the consumer or
+// the subscription is closed.
+func (s *subscription) forward(msgs <-chan amqp.Delivery) {
+	defer close(s.deliveries)
+	for msg := range msgs {
+		d := broker.Delivery{
+			Message: broker.Message{
+				Body:          msg.Body,
+				ContentType:   msg.ContentType,
+				Headers:       msg.Headers,
+				CorrelationID: msg.CorrelationId,
+				ReplyTo:       msg.ReplyTo,
+			},
+			DeliveryTag:  msg.DeliveryTag,
+			Redelivered:  msg.Redelivered,
+			Acknowledger: acknowledger{msg.Acknowledger},
+		}
+		select {
+		case s.deliveries <- d:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+func (s *subscription) Deliveries() <-chan broker.Delivery {
+	return s.deliveries
+}
+
+// Cancel sends basic.cancel; the library closes msgs once the server confirms.
+func (s *subscription) Cancel() error {
+	if s.channel.IsClosed() {
+		return nil
+	}
+	return s.channel.Cancel(s.tag, false)
+}
+
+// Close closes the consumer channel, which makes RabbitMQ requeue every
+// unacknowledged delivery.
+func (s *subscription) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.done)
+		err = s.client.CloseChannel(s.channelName)
+	})
+	return err
+}
+
+// acknowledger adapts amqp.Acknowledger to single-message acks.
+type acknowledger struct {
+	ack amqp.Acknowledger
+}
+
+func (a acknowledger) Ack(tag uint64) error {
+	return a.ack.Ack(tag, false)
+}
+
+func (a acknowledger) Nack(tag uint64, requeue bool) error {
+	return a.ack.Nack(tag, false, requeue)
+}
@@ -3,6 +3,7 @@ package rabbitmq
 import (
 	"fmt"
 	"sync"
+	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 )
@@ -29,7 +30,7 @@ func (c *Client) CreateChannel(name string) (*amqp.Channel, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if ch, exists := c.channels[name]; exists {
+	if ch, exists := c.channels[name]; exists && !ch.IsClosed() {
 		return ch, nil
 	}
 
@@ -42,15 +43,25 @@ func (c *Client) CreateChannel(name string) (*amqp.Channel, error) {
 	return ch, nil
 }
 
+// OpenChannel opens a channel that is not tracked by name; the caller
+// closes it.
+func (c *Client) OpenChannel() (*amqp.Channel, error) {
+	ch, err := c.conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create channel: %w", err)
+	}
+	return ch, nil
+}
+
 func (c *Client) CloseChannel(name string) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
 	if ch, exists := c.channels[name]; exists {
-		if err := ch.Close(); err != nil {
-			return err
-		}
 		delete(c.channels, name)
+		if !ch.IsClosed() {
+			return ch.Close()
+		}
 	}
 	return nil
 }
@@ -78,7 +89,46 @@ func (c *Client) DeclareQueue(ch *amqp.Channel, queueName string) (amqp.Queue, e
 	)
 }
 
+// DeclareTemporaryQueue declares a queue owned by this connection. RabbitMQ
+// deletes it when its last consumer cancels or the connection closes.
+func (c *Client) DeclareTemporaryQueue(ch *amqp.Channel, queueName string) (amqp.Queue, error) {
+	return ch.QueueDeclare(
+		queueName,
+		false, // durable
+		true,  // delete when unused
+		true,  // exclusive
+		false, // no-wait
+		nil,   // arguments
+	)
+}
+
+// DeclareDelayQueue declares the queue holding messages for queueName that
+// are delayed by delay. Messages expire into queueName through the default
+// exchange, and the delay queue itself expires a minute after it was last
+// used.
+func (c *Client) DeclareDelayQueue(ch *amqp.Channel, queueName string, delay time.Duration) (string, error) {
+	name := fmt.Sprintf("%s.delay.%d", queueName, delay.Milliseconds())
+	_, err := ch.QueueDeclare(
+		name,
+		true,  // durable
+		false, // delete when unused
+		false, // exclusive
+		false, // no-wait
+		amqp.Table{
+			"x-message-ttl":             delay.Milliseconds(),
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": queueName,
+			"x-expires":                 delay.Milliseconds() + time.Minute.Milliseconds(),
+		},
+	)
+	return name, err
+}
+
 func (c *Client) DeleteQueue(ch *amqp.Channel, queueName string) error {
 	_, err := ch.QueueDelete(queueName, false, false, false)
 	return err
-}
\ No newline at end of file
+}
+
+func (c *Client) PurgeQueue(ch *amqp.Channel, queueName string) (int, error) {
+	return ch.QueuePurge(queueName, false)
+}
@@ -0,0 +1,387 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Expr is a compiled condition such as
+//
+//	$.payload.amount >= 100 && ($.headers.source == "api" || !$.payload.test)
+//
+// Operands are paths, strings, numbers, true, false and null. Comparisons
+// are ==, !=, <, <=, >, >= and =~ (regular expression match). A bare path is
+// true when it exists and is not false or null.
+type Expr struct {
+	source string
+	root   node
+}
+
+type node interface {
+	eval(doc map[string]interface{}) interface{}
+}
+
+func Compile(source string) (*Expr, error) {
+	p := &parser{input: source}
+	p.next()
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition %q: %w", source, err)
+	}
+	if p.tok.kind != tokEOF {
+		return nil, fmt.Errorf("invalid condition %q: unexpected %q", source, p.tok.text)
+	}
+	return &Expr{source: source, root: root}, nil
+}
+
+// Match evaluates the condition against a document with payload and headers.
+func (e *Expr) Match(doc map[string]interface{}) bool {
+	return truthy(e.root.eval(doc))
+}
+
+func (e *Expr) String() string { return e.source }
+
+type tokKind int
+
+const (
+	tokEOF tokKind = iota
+	tokPath
+	tokString
+	tokNumber
+	tokIdent
+	tokOp
+	tokError
+)
+
+type token struct {
+	kind  tokKind
+	text  string
+	path  Path
+	value interface{}
+}
+
+type parser struct {
+	input string
+	tok   token
+}
+
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "=~", "<", ">", "!", "(", ")"}
+
+func (p *parser) next() {
+	p.input = strings.TrimLeft(p.input, " \t\r\n")
+	if p.input == "" {
+		p.tok = token{kind: tokEOF}
+		return
+	}
+
+	switch c := p.input[0]; {
+	case c == '$':
+		path, rest, err := parsePath(p.input)
+		if err != nil {
+			p.tok = token{kind: tokError, text: err.Error()}
+			return
+		}
+		p.tok = token{kind: tokPath, text: p.input[:len(p.input)-len(rest)], path: path}
+		p.input = rest
+		return
+	case c == '"' || c == '\'':
+		end := 1
+		for end < len(p.input) && p.input[end] != c {
+			if p.input[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(p.input) {
+			p.tok = token{kind: tokError, text: "unterminated string"}
+			return
+		}
+		raw := p.input[:end+1]
+		if c == '\'' {
+			raw = `"` + strings.ReplaceAll(raw[1:len(raw)-1], `"`, `\"`) + `"`
+		}
+		value, err := strconv.Unquote(raw)
+		if err != nil {
+			p.tok = token{kind: tokError, text: "invalid string " + p.input[:end+1]}
+			return
+		}
+		p.tok = token{kind: tokString, text: p.input[:end+1], value: value}
+		p.input = p.input[end+1:]
+		return
+	case c == '-' || c >= '0' && c <= '9':
+		end := 1
+		for end < len(p.input) && strings.IndexByte("0123456789.eE+-", p.input[end]) >= 0 {
+			end++
+		}
+		value, err := strconv.ParseFloat(p.input[:end], 64)
+		if err != nil {
+			p.tok = token{kind: tokError, text: "invalid number " + p.input[:end]}
+			return
+		}
+		p.tok = token{kind: tokNumber, text: p.input[:end], value: value}
+		p.input = p.input[end:]
+		return
+	case c >= 'a' && c <= 'z':
+		end := 1
+		for end < len(p.input) && p.input[end] >= 'a' && p.input[end] <= 'z' {
+			end++
+		}
+		p.tok = token{kind: tokIdent, text: p.input[:end]}
+		p.input = p.input[end:]
+		return
+	}
+
+	for _, op := range operators {
+		if strings.HasPrefix(p.input, op) {
+			p.tok = token{kind: tokOp, text: op}
+			p.input = p.input[len(op):]
+			return
+		}
+	}
+	p.tok = token{kind: tokError, text: "unexpected " + p.input[:1]}
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokOp && p.tok.text == "||" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok.kind == tokOp && p.tok.text == "&&" {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.tok.kind == tokOp && p.tok.text == "!" {
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokOp {
+		return left, nil
+	}
+
+	op := p.tok.text
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=":
+		p.next()
+		right, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		return compareNode{op: op, left: left, right: right}, nil
+	case "=~":
+		p.next()
+		if p.tok.kind != tokString {
+			return nil, fmt.Errorf("=~ needs a string pattern")
+		}
+		re, err := regexp.Compile(p.tok.value.(string))
+		if err != nil {
+			return nil, err
+		}
+		p.next()
+		return matchNode{left: left, re: re}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parseOperand() (node, error) {
+	tok := p.tok
+	switch tok.kind {
+	case tokPath:
+		p.next()
+		return pathNode(tok.path), nil
+	case tokString, tokNumber:
+		p.next()
+		return literalNode{tok.value}, nil
+	case tokIdent:
+		p.next()
+		switch tok.text {
+		case "true":
+			return literalNode{true}, nil
+		case "false":
+			return literalNode{false}, nil
+		case "null":
+			return literalNode{nil}, nil
+		}
+		return nil, fmt.Errorf("unknown identifier %q", tok.text)
+	case tokOp:
+		if tok.text == "(" {
+			p.next()
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if p.tok.kind != tokOp || p.tok.text != ")" {
+				return nil, fmt.Errorf("missing )")
+			}
+			p.next()
+			return inner, nil
+		}
+	case tokError:
+		return nil, fmt.Errorf("%s", tok.text)
+	case tokEOF:
+		return nil, fmt.Errorf("unexpected end of condition")
+	}
+	return nil, fmt.Errorf("unexpected %q", tok.text)
+}
+
+type pathNode Path
+
+func (n pathNode) eval(doc map[string]interface{}) interface{} {
+	value, _ := Path(n).Get(doc)
+	return value
+}
+
+type literalNode struct{ value interface{} }
+
+func (n literalNode) eval(map[string]interface{}) interface{} { return n.value }
+
+type andNode struct{ left, right node }
+
+func (n andNode) eval(doc map[string]interface{}) interface{} {
+	return truthy(n.left.eval(doc)) && truthy(n.right.eval(doc))
+}
+
+type orNode struct{ left, right node }
+
+func (n orNode) eval(doc map[string]interface{}) interface{} {
+	return truthy(n.left.eval(doc)) || truthy(n.right.eval(doc))
+}
+
+type notNode struct{ operand node }
+
+func (n notNode) eval(doc map[string]interface{}) interface{} {
+	return !truthy(n.operand.eval(doc))
+}
+
+type matchNode struct {
+	left node
+	re   *regexp.Regexp
+}
+
+func (n matchNode) eval(doc map[string]interface{}) interface{} {
+	s, ok := n.left.eval(doc).(string)
+	return ok && n.re.MatchString(s)
+}
+
+type compareNode struct {
+	op          string
+	left, right node
+}
+
+func (n compareNode) eval(doc map[string]interface{}) interface{} {
+	left, right := normalize(n.left.eval(doc)), normalize(n.right.eval(doc))
+	switch n.op {
+	case "==":
+		return equal(left, right)
+	case "!=":
+		return !equal(left, right)
+	}
+
+	var cmp int
+	switch l := left.(type) {
+	case float64:
+		r, ok := right.(float64)
+		if !ok {
+			return false
+		}
+		cmp = compareFloat(l, r)
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return false
+		}
+		cmp = strings.Compare(l, r)
+	default:
+		return false
+	}
+
+	switch n.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	}
+	return cmp >= 0
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func equal(a, b interface{}) bool {
+	switch a.(type) {
+	case nil, bool, float64, string:
+		return a == b
+	}
+	return false
+}
+
+// normalize turns the integer types headers may carry into float64.
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int:
+		return float64(v)
+	case int32:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	}
+	return value
+}
+
+func truthy(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	}
+	return true
+}
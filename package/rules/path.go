@@ -0,0 +1,200 @@
+package rules
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Path is a parsed JSONPath-like reference such as $.payload.items[0].sku or
+// $.headers["x-source"]. The first segment is always payload or headers.
+type Path []interface{} // string keys and int indexes
+
+func ParsePath(s string) (Path, error) {
+	path, rest, err := parsePath(s)
+	if err != nil {
+		return nil, err
+	}
+	if rest != "" {
+		return nil, fmt.Errorf("unexpected %q after path", rest)
+	}
+	return path, nil
+}
+
+// parsePath reads a path from the start of s and returns what follows it.
+func parsePath(s string) (Path, string, error) {
+	if !strings.HasPrefix(s, "$") {
+		return nil, s, fmt.Errorf("path must start with $")
+	}
+	s = s[1:]
+
+	var path Path
+	for len(s) > 0 {
+		switch s[0] {
+		case '.':
+			end := 1
+			for end < len(s) && isIdentChar(s[end]) {
+				end++
+			}
+			if end == 1 {
+				return nil, s, fmt.Errorf("empty path segment")
+			}
+			path = append(path, s[1:end])
+			s = s[end:]
+		case '[':
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return nil, s, fmt.Errorf("unterminated [")
+			}
+			inner := s[1:end]
+			if unquoted, err := strconv.Unquote(inner); err == nil {
+				path = append(path, unquoted)
+			} else if index, err := strconv.Atoi(inner); err == nil && index >= 0 {
+				path = append(path, index)
+			} else {
+				return nil, s, fmt.Errorf("invalid index %q", inner)
+			}
+			s = s[end+1:]
+		default:
+			return path, s, path.validate()
+		}
+	}
+	return path, s, path.validate()
+}
+
+func (p Path) validate() error {
+	if len(p) == 0 {
+		return fmt.Errorf("path must name payload or headers")
+	}
+	if root, _ := p[0].(string); root != "payload" && root != "headers" {
+		return fmt.Errorf("path must start with $.payload or $.headers")
+	}
+	return nil
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '-' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func (p Path) String() string {
+	var b strings.Builder
+	b.WriteString("$")
+	for _, segment := range p {
+		switch s := segment.(type) {
+		case int:
+			fmt.Fprintf(&b, "[%d]", s)
+		case string:
+			if isIdent(s) {
+				b.WriteString("." + s)
+			} else {
+				fmt.Fprintf(&b, "[%q]", s)
+			}
+		}
+	}
+	return b.String()
+}
+
+func isIdent(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if !isIdentChar(s[i]) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// Get returns the value at the path and whether it exists.
+func (p Path) Get(doc map[string]interface{}) (interface{}, bool) {
+	var current interface{} = doc
+	for _, segment := range p {
+		switch s := segment.(type) {
+		case string:
+			m, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if current, ok = m[s]; !ok {
+				return nil, false
+			}
+		case int:
+			a, ok := current.([]interface{})
+			if !ok || s >= len(a) {
+				return nil, false
+			}
+			current = a[s]
+		}
+	}
+	return current, true
+}
+
+// Set stores value at the path, creating missing objects on the way.
+func (p Path) Set(doc map[string]interface{}, value interface{}) error {
+	parent, last, err := p.parent(doc, true)
+	if err != nil {
+		return err
+	}
+	switch container := parent.(type) {
+	case map[string]interface{}:
+		key, ok := last.(string)
+		if !ok {
+			return fmt.Errorf("%s: cannot index an object", p)
+		}
+		container[key] = value
+	case []interface{}:
+		index, ok := last.(int)
+		if !ok || index >= len(container) {
+			return fmt.Errorf("%s: index out of range", p)
+		}
+		container[index] = value
+	}
+	return nil
+}
+
+// Delete removes the value at the path and returns it.
+func (p Path) Delete(doc map[string]interface{}) (interface{}, bool) {
+	parent, last, err := p.parent(doc, false)
+	if err != nil {
+		return nil, false
+	}
+	m, ok := parent.(map[string]interface{})
+	key, isKey := last.(string)
+	if !ok || !isKey {
+		return nil, false
+	}
+	value, ok := m[key]
+	delete(m, key)
+	return value, ok
+}
+
+func (p Path) parent(doc map[string]interface{}, create bool) (interface{}, interface{}, error) {
+	var current interface{} = doc
+	for _, segment := range p[:len(p)-1] {
+		switch s := segment.(type) {
+		case string:
+			m, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, nil, fmt.Errorf("%s: not an object", p)
+			}
+			next, ok := m[s]
+			if !ok || next == nil {
+				if !create {
+					return nil, nil, fmt.Errorf("%s: not found", p)
+				}
+				next = map[string]interface{}{}
+				m[s] = next
+			}
+			current = next
+		case int:
+			a, ok := current.([]interface{})
+			if !ok || s >= len(a) {
+				return nil, nil, fmt.Errorf("%s: index out of range", p)
+			}
+			current = a[s]
+		}
+	}
+	switch current.(type) {
+	case map[string]interface{}, []interface{}:
+		return current, p[len(p)-1], nil
+	}
+	return nil, nil, fmt.Errorf("%s: not an object", p)
+}
@@ -0,0 +1,234 @@
+// Package rules evaluates per-tenant rules that drop, reshape or route
+// messages without code changes.
+package rules
+
+import (
+	"fmt"
+	"multi-tenant-service/package/structs"
+	"regexp"
+	"strings"
+)
+
+const (
+	ActionDrop   = "drop"
+	ActionRename = "rename"
+	ActionSet    = "set"
+	ActionMask   = "mask"
+	ActionRoute  = "route"
+
+	PhasePublish = "publish"
+	PhaseConsume = "consume"
+)
+
+var subscriptionName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+type action struct {
+	structs.RuleAction
+	field Path
+	to    Path
+}
+
+type rule struct {
+	name    string
+	when    *Expr
+	actions []action
+}
+
+// Engine is a compiled, ordered list of rules.
+type Engine struct {
+	rules []rule
+}
+
+// Result is the outcome of running the rules on one message.
+type Result struct {
+	Payload map[string]interface{} `json:"payload"`
+	Headers map[string]interface{} `json:"headers"`
+	Dropped bool                   `json:"dropped"`
+	// Routes are the subscriptions the message is copied to.
+	Routes []string `json:"routes"`
+	// Matched names the rules whose condition held, in order.
+	Matched []string `json:"matched"`
+}
+
+// ValidPhase reports whether phase is a known rules phase; empty means publish.
+func ValidPhase(phase string) bool {
+	return phase == "" || phase == PhasePublish || phase == PhaseConsume
+}
+
+func New(defs []structs.Rule) (*Engine, error) {
+	engine := &Engine{}
+	for i, def := range defs {
+		name := def.Name
+		if name == "" {
+			name = fmt.Sprintf("rule %d", i+1)
+		}
+		compiled := rule{name: name}
+
+		if strings.TrimSpace(def.When) != "" {
+			when, err := Compile(def.When)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			compiled.when = when
+		}
+		if len(def.Actions) == 0 {
+			return nil, fmt.Errorf("%s: no actions", name)
+		}
+		for _, actionDef := range def.Actions {
+			a, err := compileAction(actionDef)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			compiled.actions = append(compiled.actions, a)
+		}
+		engine.rules = append(engine.rules, compiled)
+	}
+	return engine, nil
+}
+
+func compileAction(def structs.RuleAction) (action, error) {
+	a := action{RuleAction: def}
+	var err error
+	switch def.Type {
+	case ActionDrop:
+		return a, nil
+	case ActionRoute:
+		if !ValidSubscription(def.Subscription) {
+			return a, fmt.Errorf("route needs a subscription of lowercase letters, digits, - and _")
+		}
+		return a, nil
+	case ActionRename:
+		if a.to, err = parseFieldPath(def.To); err != nil {
+			return a, fmt.Errorf("rename to: %w", err)
+		}
+	case ActionSet, ActionMask:
+		if def.Keep < 0 {
+			return a, fmt.Errorf("mask keep must not be negative")
+		}
+	default:
+		return a, fmt.Errorf("unknown action %q", def.Type)
+	}
+	if a.field, err = parseFieldPath(def.Field); err != nil {
+		return a, fmt.Errorf("%s field: %w", def.Type, err)
+	}
+	return a, nil
+}
+
+// parseFieldPath parses a path an action writes to, which must point below
+// the payload or headers object.
+func parseFieldPath(s string) (Path, error) {
+	path, err := ParsePath(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(path) < 2 {
+		return nil, fmt.Errorf("path must name a field")
+	}
+	return path, nil
+}
+
+// Routes lists the subscriptions the rules can route to.
+func (e *Engine) Routes() []string {
+	var routes []string
+	seen := make(map[string]bool)
+	for _, r := range e.rules {
+		for _, a := range r.actions {
+			if a.Type == ActionRoute && !seen[a.Subscription] {
+				seen[a.Subscription] = true
+				routes = append(routes, a.Subscription)
+			}
+		}
+	}
+	return routes
+}
+
+// ValidSubscription reports whether name can be used as a subscription.
+func ValidSubscription(name string) bool {
+	return subscriptionName.MatchString(name)
+}
+
+// SubscriptionQueue names the queue that holds the copies routed to a
+// subscription of a tenant.
+func SubscriptionQueue(tenantID, subscription string) string {
+	return fmt.Sprintf("tenant_%s_sub_%s", tenantID, subscription)
+}
+
+// Len returns the number of rules.
+func (e *Engine) Len() int { return len(e.rules) }
+
+// Apply runs the rules in order on the payload and headers, which are
+// changed in place. A drop action stops evaluation; routes collected before
+// it still apply, so route followed by drop sends the message only to the
+// subscription.
+func (e *Engine) Apply(payload, headers map[string]interface{}) (*Result, error) {
+	if payload == nil {
+		payload = map[string]interface{}{}
+	}
+	if headers == nil {
+		headers = map[string]interface{}{}
+	}
+	doc := map[string]interface{}{"payload": payload, "headers": headers}
+	result := &Result{Routes: []string{}, Matched: []string{}}
+
+	for _, r := range e.rules {
+		if r.when != nil && !r.when.Match(doc) {
+			continue
+		}
+		result.Matched = append(result.Matched, r.name)
+
+		for _, a := range r.actions {
+			if err := a.apply(doc, result); err != nil {
+				return nil, fmt.Errorf("%s: %w", r.name, err)
+			}
+			if result.Dropped {
+				break
+			}
+		}
+		if result.Dropped {
+			break
+		}
+	}
+
+	// Actions may replace the payload or headers object itself
+	result.Payload, _ = doc["payload"].(map[string]interface{})
+	result.Headers, _ = doc["headers"].(map[string]interface{})
+	return result, nil
+}
+
+func (a action) apply(doc map[string]interface{}, result *Result) error {
+	switch a.Type {
+	case ActionDrop:
+		result.Dropped = true
+	case ActionRoute:
+		for _, route := range result.Routes {
+			if route == a.Subscription {
+				return nil
+			}
+		}
+		result.Routes = append(result.Routes, a.Subscription)
+	case ActionRename:
+		if value, ok := a.field.Delete(doc); ok {
+			return a.to.Set(doc, value)
+		}
+	case ActionSet:
+		return a.field.Set(doc, a.Value)
+	case ActionMask:
+		if value, ok := a.field.Get(doc); ok && value != nil {
+			return a.field.Set(doc, mask(value, a.Keep))
+		}
+	}
+	return nil
+}
+
+// mask hides a value, keeping the last keep characters of strings.
+func mask(value interface{}, keep int) string {
+	s, ok := value.(string)
+	if !ok {
+		return "****"
+	}
+	runes := []rune(s)
+	if keep >= len(runes) {
+		keep = 0
+	}
+	return strings.Repeat("*", len(runes)-keep) + string(runes[len(runes)-keep:])
+}
@@ -0,0 +1,97 @@
+package rules
+
+import (
+	"testing"
+
+	"multi-tenant-service/package/structs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompile(t *testing.T) {
+	doc := map[string]interface{}{
+		"payload": map[string]interface{}{
+			"amount": 150.0,
+			"user":   map[string]interface{}{"email": "a@example.com"},
+			"items":  []interface{}{map[string]interface{}{"sku": "X1"}},
+			"test":   false,
+		},
+		"headers": map[string]interface{}{"x-source": "api", "x-attempts": int64(2)},
+	}
+
+	cases := map[string]bool{
+		`$.payload.amount > 100`:                            true,
+		`$.payload.amount <= 100`:                           false,
+		`$.payload.user.email =~ "@example\\.com$"`:         true,
+		`$.payload.items[0].sku == 'X1'`:                    true,
+		`$.headers["x-source"] == "api" && !$.payload.test`: true,
+		`$.headers.x-attempts >= 2`:                         true,
+		`$.payload.missing || $.payload.amount == 150`:      true,
+		`($.payload.missing || false) && true`:              false,
+		`$.payload.missing == null`:                         true,
+		`$.payload.amount == "150"`:                         false,
+	}
+	for source, want := range cases {
+		expr, err := Compile(source)
+		require.NoError(t, err, source)
+		assert.Equal(t, want, expr.Match(doc), source)
+	}
+
+	for _, source := range []string{`$.payload.a >`, `$.body.a`, `$.payload.a == "x`, `($.payload.a`, `foo`, `$.payload.a =~ 1`} {
+		_, err := Compile(source)
+		assert.Error(t, err, source)
+	}
+}
+
+func TestApply(t *testing.T) {
+	engine, err := New([]structs.Rule{
+		{Name: "drop-tests", When: `$.payload.test == true`, Actions: []structs.RuleAction{{Type: ActionDrop}}},
+		{Name: "reshape", Actions: []structs.RuleAction{
+			{Type: ActionRename, Field: "$.payload.cc", To: "$.payload.card.number"},
+			{Type: ActionMask, Field: "$.payload.card.number", Keep: 4},
+			{Type: ActionSet, Field: "$.headers.x-team", Value: "billing"},
+		}},
+		{Name: "big", When: `$.payload.amount > 1000`, Actions: []structs.RuleAction{
+			{Type: ActionRoute, Subscription: "fraud"},
+			{Type: ActionDrop},
+		}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"fraud"}, engine.Routes())
+
+	result, err := engine.Apply(map[string]interface{}{"cc": "4111111111111111", "amount": 20.0}, nil)
+	require.NoError(t, err)
+	assert.False(t, result.Dropped)
+	assert.Equal(t, []string{"reshape"}, result.Matched)
+	assert.Equal(t, map[string]interface{}{
+		"card":   map[string]interface{}{"number": "************1111"},
+		"amount": 20.0,
+	}, result.Payload)
+	assert.Equal(t, map[string]interface{}{"x-team": "billing"}, result.Headers)
+
+	result, err = engine.Apply(map[string]interface{}{"amount": 5000.0}, nil)
+	require.NoError(t, err)
+	assert.True(t, result.Dropped)
+	assert.Equal(t, []string{"fraud"}, result.Routes)
+
+	result, err = engine.Apply(map[string]interface{}{"test": true, "amount": 5000.0}, nil)
+	require.NoError(t, err)
+	assert.True(t, result.Dropped)
+	assert.Empty(t, result.Routes)
+	assert.Equal(t, []string{"drop-tests"}, result.Matched)
+}
+
+func TestNewRejectsInvalidRules(t *testing.T) {
+	invalid := [][]structs.Rule{
+		{{When: `$.payload.a >`, Actions: []structs.RuleAction{{Type: ActionDrop}}}},
+		{{Actions: nil}},
+		{{Actions: []structs.RuleAction{{Type: "explode"}}}},
+		{{Actions: []structs.RuleAction{{Type: ActionSet, Field: "$.payload"}}}},
+		{{Actions: []structs.RuleAction{{Type: ActionRoute, Subscription: "Bad Name"}}}},
+	}
+	for _, defs := range invalid {
+		_, err := New(defs)
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,117 @@
+package selector
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Operators supported in a label selector.
+const (
+	OpEquals       = "="
+	OpNotEquals    = "!="
+	OpExists       = "exists"
+	OpDoesNotExist = "!exists"
+)
+
+// Requirement is a single comma-separated term of a selector, e.g. plan=gold.
+type Requirement struct {
+	Key      string
+	Operator string
+	Value    string
+}
+
+// Selector matches labels when all of its requirements match.
+type Selector []Requirement
+
+// Parse parses selectors such as "plan=gold,region!=eu,team,!legacy".
+// An empty string yields a selector that matches everything.
+func Parse(s string) (Selector, error) {
+	var sel Selector
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+
+		var req Requirement
+		switch {
+		case strings.Contains(term, "!="):
+			parts := strings.SplitN(term, "!=", 2)
+			req = Requirement{Key: parts[0], Operator: OpNotEquals, Value: parts[1]}
+		case strings.Contains(term, "=="):
+			parts := strings.SplitN(term, "==", 2)
+			req = Requirement{Key: parts[0], Operator: OpEquals, Value: parts[1]}
+		case strings.Contains(term, "="):
+			parts := strings.SplitN(term, "=", 2)
+			req = Requirement{Key: parts[0], Operator: OpEquals, Value: parts[1]}
+		case strings.HasPrefix(term, "!"):
+			req = Requirement{Key: term[1:], Operator: OpDoesNotExist}
+		default:
+			req = Requirement{Key: term, Operator: OpExists}
+		}
+
+		req.Key = strings.TrimSpace(req.Key)
+		req.Value = strings.TrimSpace(req.Value)
+		if !validKey(req.Key) {
+			return nil, fmt.Errorf("invalid selector key %q", req.Key)
+		}
+		sel = append(sel, req)
+	}
+	return sel, nil
+}
+
+// Matches reports whether labels satisfy every requirement.
+func (s Selector) Matches(labels map[string]string) bool {
+	for _, req := range s {
+		value, ok := labels[req.Key]
+		switch req.Operator {
+		case OpEquals:
+			if !ok || value != req.Value {
+				return false
+			}
+		case OpNotEquals:
+			if ok && value == req.Value {
+				return false
+			}
+		case OpExists:
+			if !ok {
+				return false
+			}
+		case OpDoesNotExist:
+			if ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (s Selector) String() string {
+	terms := make([]string, 0, len(s))
+	for _, req := range s {
+		switch req.Operator {
+		case OpExists:
+			terms = append(terms, req.Key)
+		case OpDoesNotExist:
+			terms = append(terms, "!"+req.Key)
+		default:
+			terms = append(terms, req.Key+req.Operator+req.Value)
+		}
+	}
+	return strings.Join(terms, ",")
+}
+
+func validKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for _, r := range key {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == '/':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package selector
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	sel, err := Parse("plan=gold, region!=eu,team,!legacy,tier==1")
+	require.NoError(t, err)
+	assert.Equal(t, Selector{
+		{Key: "plan", Operator: OpEquals, Value: "gold"},
+		{Key: "region", Operator: OpNotEquals, Value: "eu"},
+		{Key: "team", Operator: OpExists},
+		{Key: "legacy", Operator: OpDoesNotExist},
+		{Key: "tier", Operator: OpEquals, Value: "1"},
+	}, sel)
+	assert.Equal(t, "plan=gold,region!=eu,team,!legacy,tier=1", sel.String())
+
+	sel, err = Parse("")
+	require.NoError(t, err)
+	assert.Empty(t, sel)
+
+	_, err = Parse("=gold")
+	assert.Error(t, err)
+
+	_, err = Parse("pl an=gold")
+	assert.Error(t, err)
+}
+
+func TestMatches(t *testing.T) {
+	labels := map[string]string{"plan": "gold", "region": "us", "team": "core"}
+
+	cases := map[string]bool{
+		"":                     true,
+		"plan=gold":            true,
+		"plan=silver":          false,
+		"region!=eu":           true,
+		"region!=us":           false,
+		"owner!=ops":           true,
+		"team":                 true,
+		"owner":                false,
+		"!owner":               true,
+		"!team":                false,
+		"plan=gold,region!=eu": true,
+		"plan=gold,region=eu":  false,
+	}
+	for s, want := range cases {
+		sel, err := Parse(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, want, sel.Matches(labels), s)
+	}
+}
@@ -0,0 +1,36 @@
+package structs
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ConsumerStateRunning = "running"
+	ConsumerStatePaused  = "paused"
+	// ConsumerStateStopped means no replica runs a consumer for the tenant,
+	// or its delivery channel was closed by the broker.
+	ConsumerStateStopped = "stopped"
+)
+
+// ConsumerStatus describes the consumer of a tenant as run by the replica
+// that owns it. Replicas other than the owner answer with the status the
+// owner last reported at ReportedAt. Counters cover the lifetime of the
+// owner process.
+type ConsumerStatus struct {
+	TenantID       uuid.UUID  `json:"tenant_id"`
+	State          string     `json:"state"`
+	Owner          string     `json:"owner,omitempty"`
+	ReportedAt     *time.Time `json:"reported_at,omitempty"`
+	Workers        int        `json:"workers"`
+	Prefetch       int        `json:"prefetch"`
+	InFlight       int        `json:"in_flight"`
+	Processed      int64      `json:"processed"`
+	Failed         int64      `json:"failed"`
+	LastDeliveryAt *time.Time `json:"last_delivery_at"`
+	LastError      string     `json:"last_error,omitempty"`
+	LastErrorAt    *time.Time `json:"last_error_at"`
+	QueueDepth     int        `json:"queue_depth"`
+	QueueConsumers int        `json:"queue_consumers"`
+}
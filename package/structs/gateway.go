@@ -0,0 +1,44 @@
+package structs
+
+import (
+	"encoding/json"
+
+	"github.com/google/uuid"
+)
+
+// Gateway frame ops. Clients send subscribe, unsubscribe, publish and ack;
+// the gateway answers with subscribed, unsubscribed, published or error and
+// pushes message and heartbeat frames.
+const (
+	GatewayOpSubscribe    = "subscribe"
+	GatewayOpUnsubscribe  = "unsubscribe"
+	GatewayOpPublish      = "publish"
+	GatewayOpAck          = "ack"
+	GatewayOpSubscribed   = "subscribed"
+	GatewayOpUnsubscribed = "unsubscribed"
+	GatewayOpPublished    = "published"
+	GatewayOpMessage      = "message"
+	GatewayOpHeartbeat    = "heartbeat"
+	GatewayOpError        = "error"
+)
+
+// GatewayFrame is one JSON frame on a gateway connection.
+type GatewayFrame struct {
+	Op string `json:"op"`
+	// ID correlates a reply with the request that caused it.
+	ID       string    `json:"id,omitempty"`
+	TenantID uuid.UUID `json:"tenant_id,omitempty"`
+	// Subscription selects a rules subscription queue instead of the tenant
+	// stream of stored messages.
+	Subscription string `json:"subscription,omitempty"`
+	// Cursor and Filter apply to tenant streams, as in the SSE stream.
+	Cursor string `json:"cursor,omitempty"`
+	Filter string `json:"filter,omitempty"`
+	// Payload and OrderingKey are the message to publish.
+	Payload     map[string]interface{} `json:"payload,omitempty"`
+	OrderingKey string                 `json:"ordering_key,omitempty"`
+	// Delivery names a pushed message; clients send it back to ack it.
+	Delivery string          `json:"delivery,omitempty"`
+	Message  json.RawMessage `json:"message,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
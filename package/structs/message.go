@@ -13,9 +13,16 @@ type Message struct {
 	CreatedAt time.Time       `json:"created_at" db:"created_at"`
 }
 
+// HeaderOrderingKey carries CreateMessageRequest.OrderingKey on the queued
+// message so consumers can order deliveries without decoding the body.
+const HeaderOrderingKey = "x-ordering-key"
+
 type CreateMessageRequest struct {
 	TenantID uuid.UUID       `json:"tenant_id" binding:"required"`
 	Payload  map[string]interface{} `json:"payload" binding:"required"`
+	// OrderingKey makes messages with the same key get processed one at a
+	// time, in publish order.
+	OrderingKey string `json:"ordering_key,omitempty"`
 }
 
 type MessageResponse struct {
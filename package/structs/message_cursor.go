@@ -0,0 +1,66 @@
+package structs
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MessageCursor is a keyset position in a tenant's messages, ordered by
+// created_at and then id.
+type MessageCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// String encodes the cursor as <created_at RFC 3339>_<id>.
+func (c MessageCursor) String() string {
+	return c.CreatedAt.UTC().Format(time.RFC3339Nano) + "_" + c.ID.String()
+}
+
+// Before reports whether c comes before other, comparing ids the way
+// Postgres orders uuids.
+func (c MessageCursor) Before(other MessageCursor) bool {
+	if !c.CreatedAt.Equal(other.CreatedAt) {
+		return c.CreatedAt.Before(other.CreatedAt)
+	}
+	return bytes.Compare(c.ID[:], other.ID[:]) < 0
+}
+
+// ParseMessageCursor reads a cursor written by String. A bare RFC 3339
+// timestamp, as used by GET /messages, is accepted too and points after
+// every message at that time.
+func ParseMessageCursor(s string) (MessageCursor, error) {
+	timestamp, id, hasID := strings.Cut(s, "_")
+	createdAt, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return MessageCursor{}, fmt.Errorf("invalid cursor")
+	}
+	if !hasID {
+		return MessageCursor{CreatedAt: createdAt, ID: uuid.Max}, nil
+	}
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return MessageCursor{}, fmt.Errorf("invalid cursor")
+	}
+	return MessageCursor{CreatedAt: createdAt, ID: parsed}, nil
+}
+
+type RequestStreamMessages struct {
+	TenantID uuid.UUID
+	// Cursor resumes after this position, usually from Last-Event-ID; empty
+	// streams only messages stored from now on.
+	Cursor string
+	// Filter is a rules condition such as `$.payload.type == "order"`.
+	Filter string
+}
+
+// StreamEvent is one frame of a message stream. A nil Message is a
+// heartbeat.
+type StreamEvent struct {
+	ID      string
+	Message *Message
+}
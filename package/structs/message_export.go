@@ -0,0 +1,27 @@
+package structs
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Formats of a message export.
+const (
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatCSV    = "csv"
+)
+
+// ExportMessagesRequest selects the stored messages of a tenant to export.
+type ExportMessagesRequest struct {
+	TenantID uuid.UUID
+	// From is inclusive and To exclusive; nil leaves that end open.
+	From *time.Time
+	To   *time.Time
+	// Filter is a rules condition such as `$.payload.type == "order"`.
+	Filter string
+}
+
+type ImportMessagesResult struct {
+	Imported int64 `json:"imported"`
+}
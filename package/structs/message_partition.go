@@ -0,0 +1,30 @@
+package structs
+
+import "time"
+
+// Sizes of the time sub-partitions of a tenant's messages partition.
+const (
+	PartitionIntervalDaily   = "daily"
+	PartitionIntervalMonthly = "monthly"
+)
+
+// What retention does with an expired sub-partition: drop deletes it,
+// detach keeps it as a standalone table outside the messages table.
+const (
+	RetentionActionDrop   = "drop"
+	RetentionActionDetach = "detach"
+)
+
+// MessagePartition is a sub-partition of a tenant's messages partition,
+// holding the messages created from From up to, but not including, To. A
+// nil From is unbounded; it is used for the rows of a tenant created before
+// sub-partitioning. The default sub-partition catches messages outside
+// every range and has neither bound.
+type MessagePartition struct {
+	Name    string     `json:"name"`
+	From    *time.Time `json:"from,omitempty"`
+	To      *time.Time `json:"to,omitempty"`
+	Default bool       `json:"default,omitempty"`
+	// Archived is set on detached sub-partitions whose archive is complete.
+	Archived bool `json:"archived,omitempty"`
+}
@@ -0,0 +1,59 @@
+package structs
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PullRequest leases messages from a pull-mode tenant.
+type PullRequest struct {
+	// MaxMessages is how many messages to lease, 1 to 100; 0 means 1.
+	MaxMessages int `json:"max_messages"`
+	// VisibilityTimeout is how many seconds the messages stay hidden from
+	// other pulls; 0 uses the configured default.
+	VisibilityTimeout int `json:"visibility_timeout"`
+	// WaitSeconds waits up to 20 seconds for a message when none is ready.
+	WaitSeconds int `json:"wait_seconds"`
+}
+
+// PulledMessage is a leased message. The receipt handle identifies this
+// lease; it changes every time the message is received.
+type PulledMessage struct {
+	MessageID     uuid.UUID              `json:"message_id"`
+	ReceiptHandle uuid.UUID              `json:"receipt_handle"`
+	Payload       map[string]interface{} `json:"payload"`
+	OrderingKey   string                 `json:"ordering_key,omitempty"`
+	Headers       map[string]interface{} `json:"headers,omitempty"`
+	ReceiveCount  int                    `json:"receive_count"`
+	VisibleAt     time.Time              `json:"visible_at"`
+}
+
+// MessageLease is a leased message as stored in message_leases.
+type MessageLease struct {
+	MessageID     uuid.UUID
+	ReceiptHandle uuid.UUID
+	TenantID      uuid.UUID
+	Body          []byte
+	Headers       map[string]interface{}
+	ReceiveCount  int
+	VisibleAt     time.Time
+}
+
+// ReceiptRequest acks, nacks or extends leases.
+type ReceiptRequest struct {
+	ReceiptHandles []string `json:"receipt_handles"`
+	// VisibilityTimeout is the new timeout in seconds for extend, and the
+	// delay before redelivery for nack.
+	VisibilityTimeout int `json:"visibility_timeout"`
+}
+
+type ReceiptResult struct {
+	Succeeded []string         `json:"succeeded"`
+	Failed    []ReceiptFailure `json:"failed"`
+}
+
+type ReceiptFailure struct {
+	ReceiptHandle string `json:"receipt_handle"`
+	Error         string `json:"error"`
+}
@@ -1,6 +1,8 @@
 package structs
 
 type CreateTenantRequest struct {
-	Name              string `json:"name" binding:"required"`
-	ConcurrencyConfig int    `json:"concurrency_config"`
-}
\ No newline at end of file
+	Name              string         `json:"name" binding:"required"`
+	ConcurrencyConfig int            `json:"concurrency_config"`
+	Labels            Labels         `json:"labels"`
+	Settings          TenantSettings `json:"settings"`
+}
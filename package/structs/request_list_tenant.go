@@ -0,0 +1,22 @@
+package structs
+
+import "multi-tenant-service/package/selector"
+
+type RequestListTenant struct {
+	Selector selector.Selector
+	// Status limits the result to one status; deleted tenants are only
+	// returned when asked for explicitly.
+	Status string
+}
+
+// BulkTenantRequest targets every tenant matched by Selector.
+type BulkTenantRequest struct {
+	Selector string `json:"selector"`
+	Workers  int    `json:"workers"`
+}
+
+type BulkTenantResult struct {
+	TenantID string `json:"tenant_id"`
+	Success  bool   `json:"success"`
+	Error    string `json:"error,omitempty"`
+}
@@ -0,0 +1,22 @@
+package structs
+
+import "github.com/google/uuid"
+
+// MessageRequest publishes a message and waits for the reply of the tenant
+// pipeline.
+type MessageRequest struct {
+	TenantID    uuid.UUID              `json:"tenant_id"`
+	Payload     map[string]interface{} `json:"payload"`
+	OrderingKey string                 `json:"ordering_key,omitempty"`
+	// Timeout is how many seconds to wait for the reply; 0 uses the
+	// configured default.
+	Timeout int `json:"timeout"`
+}
+
+// MessageReply answers a request. Error is set instead of Payload when the
+// request was dead-lettered.
+type MessageReply struct {
+	CorrelationID string                 `json:"correlation_id"`
+	Payload       map[string]interface{} `json:"payload,omitempty"`
+	Error         string                 `json:"error,omitempty"`
+}
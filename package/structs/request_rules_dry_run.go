@@ -0,0 +1,9 @@
+package structs
+
+// RulesDryRunRequest is a sample message to run rules on. Rules, when set,
+// are tried instead of the tenant's stored rules.
+type RulesDryRunRequest struct {
+	Payload map[string]interface{} `json:"payload"`
+	Headers map[string]interface{} `json:"headers"`
+	Rules   *[]Rule                `json:"rules"`
+}
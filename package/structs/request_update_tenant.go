@@ -0,0 +1,8 @@
+package structs
+
+// UpdateTenantRequest is a partial update; nil fields are left unchanged.
+type UpdateTenantRequest struct {
+	Name     *string         `json:"name"`
+	Labels   *Labels         `json:"labels"`
+	Settings *TenantSettings `json:"settings"`
+}
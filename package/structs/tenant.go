@@ -6,10 +6,27 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	TenantStatusActive    = "active"
+	TenantStatusSuspended = "suspended"
+	TenantStatusDeleted   = "deleted"
+	TenantStatusDeleting  = "deleting"
+)
+
 type Tenant struct {
-	ID                uuid.UUID `json:"id" db:"id"`
-	Name              string    `json:"name" db:"name"`
-	ConcurrencyConfig int       `json:"concurrency_config" db:"concurrency_config"`
-	CreatedAt         time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at" db:"updated_at"`
-}
\ No newline at end of file
+	ID                uuid.UUID      `json:"id" db:"id"`
+	Name              string         `json:"name" db:"name"`
+	ConcurrencyConfig int            `json:"concurrency_config" db:"concurrency_config"`
+	Status            string         `json:"status" db:"status"`
+	Labels            Labels         `json:"labels" db:"labels"`
+	Settings          TenantSettings `json:"settings" db:"settings"`
+	CreatedAt         time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at" db:"updated_at"`
+	DeletedAt         *time.Time     `json:"deleted_at,omitempty" db:"deleted_at"`
+	// ConsumerPaused keeps the owning replica from consuming the tenant
+	// queue until the consumer is resumed.
+	ConsumerPaused bool `json:"consumer_paused" db:"consumer_paused"`
+	// ConsumerRestartAt asks the owning replica to restart a consumer
+	// started before it.
+	ConsumerRestartAt *time.Time `json:"-" db:"consumer_restart_at"`
+}
@@ -0,0 +1,41 @@
+package structs
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Deletion steps, executed in this order.
+const (
+	DeletionStepPurgeQueue    = "purge_queue"
+	DeletionStepDeleteQueue   = "delete_queue"
+	DeletionStepArchiveData   = "archive_data"
+	DeletionStepDropPartition = "drop_partition"
+	DeletionStepDeleteRow     = "delete_row"
+	DeletionStepDone          = "done"
+)
+
+const (
+	DeletionStatusRunning   = "running"
+	DeletionStatusFailed    = "failed"
+	DeletionStatusCompleted = "completed"
+)
+
+var DeletionSteps = []string{
+	DeletionStepPurgeQueue,
+	DeletionStepDeleteQueue,
+	DeletionStepArchiveData,
+	DeletionStepDropPartition,
+	DeletionStepDeleteRow,
+}
+
+type TenantDeletionJob struct {
+	TenantID  uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	Step      string    `json:"step" db:"step"`
+	Status    string    `json:"status" db:"status"`
+	Attempts  int       `json:"attempts" db:"attempts"`
+	LastError *string   `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,26 @@
+package structs
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type WorkerReplica struct {
+	ID          string    `json:"id" db:"id"`
+	StartedAt   time.Time `json:"started_at" db:"started_at"`
+	HeartbeatAt time.Time `json:"heartbeat_at" db:"heartbeat_at"`
+}
+
+type TenantLease struct {
+	TenantID   uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	Owner      string    `json:"owner" db:"owner"`
+	AcquiredAt time.Time `json:"acquired_at" db:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at" db:"expires_at"`
+}
+
+// TenantAssignment is the cluster-wide view of which replica consumes which tenant.
+type TenantAssignment struct {
+	Replicas []WorkerReplica `json:"replicas"`
+	Leases   []TenantLease   `json:"leases"`
+}
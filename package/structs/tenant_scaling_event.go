@@ -0,0 +1,25 @@
+package structs
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ScalingReasonBacklog = "backlog"
+	ScalingReasonIdle    = "idle"
+	ScalingReasonBounds  = "bounds"
+)
+
+// TenantScalingEvent records one worker count change made by the autoscaler.
+type TenantScalingEvent struct {
+	ID          int64     `json:"id" db:"id"`
+	TenantID    uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	FromWorkers int       `json:"from_workers" db:"from_workers"`
+	ToWorkers   int       `json:"to_workers" db:"to_workers"`
+	QueueDepth  int       `json:"queue_depth" db:"queue_depth"`
+	Utilization float64   `json:"utilization" db:"utilization"`
+	Reason      string    `json:"reason" db:"reason"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,124 @@
+package structs
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Labels are free-form key/value metadata attached to a tenant.
+type Labels map[string]string
+
+// TenantSettings is the per-tenant settings document stored in tenants.settings.
+type TenantSettings struct {
+	Retention    RetentionSettings `json:"retention"`
+	Partitioning PartitionSettings `json:"partitioning"`
+	Limits       LimitSettings     `json:"limits"`
+	Autoscale    AutoscaleSettings `json:"autoscale"`
+	// Pipeline lists the stages consumed messages go through; empty means
+	// persist only.
+	Pipeline []PipelineStage `json:"pipeline,omitempty"`
+	// Rules drop, reshape or route messages. RulesPhase picks where they
+	// run: "publish" (default) before the message is queued, or "consume"
+	// ahead of the pipeline.
+	Rules      []Rule `json:"rules,omitempty"`
+	RulesPhase string `json:"rules_phase,omitempty"`
+	// Defaults are payload fields added to every message that does not set them.
+	Defaults map[string]interface{} `json:"defaults,omitempty"`
+	// Delivery is "push" (default), where the server consumes the tenant
+	// queue, or "pull", where the tenant leases messages through the pull API.
+	Delivery string `json:"delivery,omitempty"`
+}
+
+const (
+	DeliveryPush = "push"
+	DeliveryPull = "pull"
+)
+
+type RetentionSettings struct {
+	// Days messages are kept; 0 keeps them forever. Messages are removed a
+	// whole sub-partition at a time, once its newest possible message expired.
+	Days int `json:"days"`
+	// Action is drop (default) or detach.
+	Action string `json:"action,omitempty"`
+	// Archive writes expired sub-partitions to the archive storage before
+	// Action is applied to them.
+	Archive bool `json:"archive,omitempty"`
+}
+
+type PartitionSettings struct {
+	// Interval is the size of new sub-partitions, daily or monthly; empty
+	// uses partitions.interval from the config.
+	Interval string `json:"interval,omitempty"`
+}
+
+type LimitSettings struct {
+	// MaxPayloadBytes rejects larger payloads at publish time; 0 disables the check.
+	MaxPayloadBytes int `json:"max_payload_bytes"`
+}
+
+// AutoscaleSettings bound the worker count the autoscaler may pick.
+type AutoscaleSettings struct {
+	Enabled    bool `json:"enabled"`
+	MinWorkers int  `json:"min_workers"`
+	MaxWorkers int  `json:"max_workers"`
+}
+
+func (l Labels) Value() (driver.Value, error) {
+	if l == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(l)
+}
+
+func (l *Labels) Scan(src interface{}) error {
+	return scanJSON(src, l)
+}
+
+func (s TenantSettings) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+func (s *TenantSettings) Scan(src interface{}) error {
+	return scanJSON(src, s)
+}
+
+func scanJSON(src interface{}, dest interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		return nil
+	case []byte:
+		return json.Unmarshal(v, dest)
+	case string:
+		return json.Unmarshal([]byte(v), dest)
+	}
+	return fmt.Errorf("unsupported JSON source type %T", src)
+}
+
+// PipelineStage configures one stage of a tenant's processing pipeline.
+// Config is passed to the stage type registered under Type.
+type PipelineStage struct {
+	Type   string          `json:"type"`
+	Config json.RawMessage `json:"config,omitempty"`
+}
+
+// Rule applies its actions to messages matching When, a condition over the
+// payload and headers such as `$.payload.amount > 100`. An empty When
+// matches every message.
+type Rule struct {
+	Name    string       `json:"name"`
+	When    string       `json:"when,omitempty"`
+	Actions []RuleAction `json:"actions"`
+}
+
+// RuleAction is one of drop, rename (Field to To), set (Field to Value),
+// mask (Field, keeping the last Keep characters) or route (a copy to
+// Subscription).
+type RuleAction struct {
+	Type         string      `json:"type"`
+	Field        string      `json:"field,omitempty"`
+	To           string      `json:"to,omitempty"`
+	Value        interface{} `json:"value,omitempty"`
+	Keep         int         `json:"keep,omitempty"`
+	Subscription string      `json:"subscription,omitempty"`
+}